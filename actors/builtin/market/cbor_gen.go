@@ -6,14 +6,16 @@ import (
 	"fmt"
 	"io"
 
+	address "github.com/filecoin-project/go-address"
 	abi "github.com/filecoin-project/go-state-types/abi"
+	market "github.com/filecoin-project/specs-actors/v3/actors/builtin/market"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{139}
+var lengthBufState = []byte{145}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -79,6 +81,11 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		}
 	}
 
+	// t.LastCronTick (market.CronTickSummary) (struct)
+	if err := t.LastCronTick.MarshalCBOR(w); err != nil {
+		return err
+	}
+
 	// t.TotalClientLockedCollateral (big.Int) (struct)
 	if err := t.TotalClientLockedCollateral.MarshalCBOR(w); err != nil {
 		return err
@@ -93,6 +100,46 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 	if err := t.TotalClientStorageFee.MarshalCBOR(w); err != nil {
 		return err
 	}
+
+	// t.AutoWithdrawDeals (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.AutoWithdrawDeals); err != nil {
+		return xerrors.Errorf("failed to write cid field t.AutoWithdrawDeals: %w", err)
+	}
+
+	// t.DataCapLedger (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.DataCapLedger); err != nil {
+		return xerrors.Errorf("failed to write cid field t.DataCapLedger: %w", err)
+	}
+
+	// t.ClientAgents (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.ClientAgents); err != nil {
+		return xerrors.Errorf("failed to write cid field t.ClientAgents: %w", err)
+	}
+
+	// t.DealMinDuration (abi.ChainEpoch) (int64)
+	if t.DealMinDuration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealMinDuration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.DealMinDuration-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.DealMaxDuration (abi.ChainEpoch) (int64)
+	if t.DealMaxDuration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealMaxDuration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.DealMaxDuration-1)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -110,7 +157,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 11 {
+	if extra != 17 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -225,6 +272,15 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		t.LastCron = abi.ChainEpoch(extraI)
 	}
+	// t.LastCronTick (market.CronTickSummary) (struct)
+
+	{
+
+		if err := t.LastCronTick.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.LastCronTick: %w", err)
+		}
+
+	}
 	// t.TotalClientLockedCollateral (big.Int) (struct)
 
 	{
@@ -252,6 +308,92 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		}
 
 	}
+	// t.AutoWithdrawDeals (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.AutoWithdrawDeals: %w", err)
+		}
+
+		t.AutoWithdrawDeals = c
+
+	}
+	// t.DataCapLedger (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.DataCapLedger: %w", err)
+		}
+
+		t.DataCapLedger = c
+
+	}
+	// t.ClientAgents (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.ClientAgents: %w", err)
+		}
+
+		t.ClientAgents = c
+
+	}
+	// t.DealMinDuration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.DealMinDuration = abi.ChainEpoch(extraI)
+	}
+	// t.DealMaxDuration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.DealMaxDuration = abi.ChainEpoch(extraI)
+	}
 	return nil
 }
 
@@ -399,37 +541,49 @@ func (t *DealState) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
-var lengthBufPublishStorageDealsParams = []byte{129}
+var lengthBufDataCapLedgerEntry = []byte{132}
 
-func (t *PublishStorageDealsParams) MarshalCBOR(w io.Writer) error {
+func (t *DataCapLedgerEntry) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufPublishStorageDealsParams); err != nil {
+	if _, err := w.Write(lengthBufDataCapLedgerEntry); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.Deals ([]market.ClientDealProposal) (slice)
-	if len(t.Deals) > cbg.MaxLength {
-		return xerrors.Errorf("Slice value in field t.Deals was too long")
+	// t.Client (address.Address) (struct)
+	if err := t.Client.MarshalCBOR(w); err != nil {
+		return err
 	}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Deals))); err != nil {
+	// t.Consumed (big.Int) (struct)
+	if err := t.Consumed.MarshalCBOR(w); err != nil {
 		return err
 	}
-	for _, v := range t.Deals {
-		if err := v.MarshalCBOR(w); err != nil {
+
+	// t.Restored (big.Int) (struct)
+	if err := t.Restored.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.RestoreEpoch (abi.ChainEpoch) (int64)
+	if t.RestoreEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.RestoreEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.RestoreEpoch-1)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (t *PublishStorageDealsParams) UnmarshalCBOR(r io.Reader) error {
-	*t = PublishStorageDealsParams{}
+func (t *DataCapLedgerEntry) UnmarshalCBOR(r io.Reader) error {
+	*t = DataCapLedgerEntry{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -442,128 +596,1851 @@ func (t *PublishStorageDealsParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 1 {
+	if extra != 4 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Deals ([]market.ClientDealProposal) (slice)
+	// t.Client (address.Address) (struct)
 
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
+	{
 
-	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Deals: array too large (%d)", extra)
-	}
+		if err := t.Client.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Client: %w", err)
+		}
 
-	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
 	}
+	// t.Consumed (big.Int) (struct)
+
+	{
+
+		if err := t.Consumed.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Consumed: %w", err)
+		}
 
-	if extra > 0 {
-		t.Deals = make([]ClientDealProposal, extra)
 	}
+	// t.Restored (big.Int) (struct)
 
-	for i := 0; i < int(extra); i++ {
+	{
 
-		var v ClientDealProposal
-		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+		if err := t.Restored.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Restored: %w", err)
 		}
 
-		t.Deals[i] = v
 	}
+	// t.RestoreEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
 
+		t.RestoreEpoch = abi.ChainEpoch(extraI)
+	}
 	return nil
 }
 
-var lengthBufDealProposal = []byte{139}
+var lengthBufClientAgents = []byte{129}
 
-func (t *DealProposal) MarshalCBOR(w io.Writer) error {
+func (t *ClientAgents) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufDealProposal); err != nil {
+	if _, err := w.Write(lengthBufClientAgents); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.PieceCID (cid.Cid) (struct)
+	// t.Agents ([]address.Address) (slice)
+	if len(t.Agents) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Agents was too long")
+	}
 
-	if err := cbg.WriteCidBuf(scratch, w, t.PieceCID); err != nil {
-		return xerrors.Errorf("failed to write cid field t.PieceCID: %w", err)
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Agents))); err != nil {
+		return err
+	}
+	for _, v := range t.Agents {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// t.PieceSize (abi.PaddedPieceSize) (uint64)
+func (t *ClientAgents) UnmarshalCBOR(r io.Reader) error {
+	*t = ClientAgents{}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.PieceSize)); err != nil {
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
 		return err
 	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
 
-	// t.VerifiedDeal (bool) (bool)
-	if err := cbg.WriteBool(w, t.VerifiedDeal); err != nil {
-		return err
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Client (address.Address) (struct)
-	if err := t.Client.MarshalCBOR(w); err != nil {
+	// t.Agents ([]address.Address) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
 		return err
 	}
 
-	// t.Provider (address.Address) (struct)
-	if err := t.Provider.MarshalCBOR(w); err != nil {
-		return err
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Agents: array too large (%d)", extra)
 	}
 
-	// t.Label (market.DealLabel) (struct)
-	if err := t.Label.MarshalCBOR(w); err != nil {
-		return err
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
 	}
 
-	// t.StartEpoch (abi.ChainEpoch) (int64)
-	if t.StartEpoch >= 0 {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.StartEpoch)); err != nil {
-			return err
-		}
-	} else {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.StartEpoch-1)); err != nil {
+	if extra > 0 {
+		t.Agents = make([]address.Address, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v address.Address
+		if err := v.UnmarshalCBOR(br); err != nil {
 			return err
 		}
+
+		t.Agents[i] = v
 	}
 
-	// t.EndEpoch (abi.ChainEpoch) (int64)
-	if t.EndEpoch >= 0 {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.EndEpoch)); err != nil {
+	return nil
+}
+
+var lengthBufCronTickSummary = []byte{132}
+
+func (t *CronTickSummary) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufCronTickSummary); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
 			return err
 		}
 	} else {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.EndEpoch-1)); err != nil {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
 			return err
 		}
 	}
 
-	// t.StoragePricePerEpoch (big.Int) (struct)
-	if err := t.StoragePricePerEpoch.MarshalCBOR(w); err != nil {
+	// t.AmountSlashed (big.Int) (struct)
+	if err := t.AmountSlashed.MarshalCBOR(w); err != nil {
 		return err
 	}
 
-	// t.ProviderCollateral (big.Int) (struct)
-	if err := t.ProviderCollateral.MarshalCBOR(w); err != nil {
+	// t.PaymentTransferred (big.Int) (struct)
+	if err := t.PaymentTransferred.MarshalCBOR(w); err != nil {
 		return err
 	}
 
-	// t.ClientCollateral (big.Int) (struct)
+	// t.DealsProcessed (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealsProcessed)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *CronTickSummary) UnmarshalCBOR(r io.Reader) error {
+	*t = CronTickSummary{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 4 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	// t.AmountSlashed (big.Int) (struct)
+
+	{
+
+		if err := t.AmountSlashed.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.AmountSlashed: %w", err)
+		}
+
+	}
+	// t.PaymentTransferred (big.Int) (struct)
+
+	{
+
+		if err := t.PaymentTransferred.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.PaymentTransferred: %w", err)
+		}
+
+	}
+	// t.DealsProcessed (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealsProcessed = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufPublishStorageDealsParams = []byte{129}
+
+func (t *PublishStorageDealsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufPublishStorageDealsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Deals ([]market.ClientDealProposal) (slice)
+	if len(t.Deals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Deals was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Deals))); err != nil {
+		return err
+	}
+	for _, v := range t.Deals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *PublishStorageDealsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = PublishStorageDealsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Deals ([]market.ClientDealProposal) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Deals: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Deals = make([]ClientDealProposal, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ClientDealProposal
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Deals[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufDealCollateralBoundsParams = []byte{131}
+
+func (t *DealCollateralBoundsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealCollateralBoundsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.PieceSize (abi.PaddedPieceSize) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.PieceSize)); err != nil {
+		return err
+	}
+
+	// t.VerifiedDeal (bool) (bool)
+	if err := cbg.WriteBool(w, t.VerifiedDeal); err != nil {
+		return err
+	}
+
+	// t.Duration (abi.ChainEpoch) (int64)
+	if t.Duration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Duration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Duration-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *DealCollateralBoundsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = DealCollateralBoundsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.PieceSize (abi.PaddedPieceSize) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.PieceSize = abi.PaddedPieceSize(extra)
+
+	}
+	// t.VerifiedDeal (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.VerifiedDeal = false
+	case 21:
+		t.VerifiedDeal = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	// t.Duration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Duration = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufDealCollateralBoundsReturn = []byte{132}
+
+func (t *DealCollateralBoundsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealCollateralBoundsReturn); err != nil {
+		return err
+	}
+
+	// t.MinProviderCollateral (big.Int) (struct)
+	if err := t.MinProviderCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.MaxProviderCollateral (big.Int) (struct)
+	if err := t.MaxProviderCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.MinClientCollateral (big.Int) (struct)
+	if err := t.MinClientCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.MaxClientCollateral (big.Int) (struct)
+	if err := t.MaxClientCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DealCollateralBoundsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = DealCollateralBoundsReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 4 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.MinProviderCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.MinProviderCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MinProviderCollateral: %w", err)
+		}
+
+	}
+	// t.MaxProviderCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.MaxProviderCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MaxProviderCollateral: %w", err)
+		}
+
+	}
+	// t.MinClientCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.MinClientCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MinClientCollateral: %w", err)
+		}
+
+	}
+	// t.MaxClientCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.MaxClientCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MaxClientCollateral: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDealDurationHistogramParams = []byte{131}
+
+func (t *DealDurationHistogramParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealDurationHistogramParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.BucketBounds ([]abi.ChainEpoch) (slice)
+	if len(t.BucketBounds) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.BucketBounds was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.BucketBounds))); err != nil {
+		return err
+	}
+	for _, v := range t.BucketBounds {
+		if v >= 0 {
+			if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+				return err
+			}
+		} else {
+			if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-v-1)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.StartDealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.StartDealID)); err != nil {
+		return err
+	}
+
+	// t.Limit (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Limit)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *DealDurationHistogramParams) UnmarshalCBOR(r io.Reader) error {
+	*t = DealDurationHistogramParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.BucketBounds ([]abi.ChainEpoch) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.BucketBounds: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.BucketBounds = make([]abi.ChainEpoch, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+		{
+			maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+			var extraI int64
+			if err != nil {
+				return err
+			}
+			switch maj {
+			case cbg.MajUnsignedInt:
+				extraI = int64(extra)
+				if extraI < 0 {
+					return fmt.Errorf("int64 positive overflow")
+				}
+			case cbg.MajNegativeInt:
+				extraI = int64(extra)
+				if extraI < 0 {
+					return fmt.Errorf("int64 negative oveflow")
+				}
+				extraI = -1 - extraI
+			default:
+				return fmt.Errorf("wrong type for int64 field: %d", maj)
+			}
+
+			t.BucketBounds[i] = abi.ChainEpoch(extraI)
+		}
+	}
+
+	// t.StartDealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.StartDealID = abi.DealID(extra)
+
+	}
+	// t.Limit (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Limit = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufDealDurationHistogramReturn = []byte{131}
+
+func (t *DealDurationHistogramReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealDurationHistogramReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Counts ([]uint64) (slice)
+	if len(t.Counts) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Counts was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Counts))); err != nil {
+		return err
+	}
+	for _, v := range t.Counts {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+
+	// t.NextDealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NextDealID)); err != nil {
+		return err
+	}
+
+	// t.Done (bool) (bool)
+	if err := cbg.WriteBool(w, t.Done); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *DealDurationHistogramReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = DealDurationHistogramReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Counts ([]uint64) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Counts: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Counts = make([]uint64, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.Counts slice: %w", err)
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.Counts was not a uint, instead got %d", maj)
+		}
+
+		t.Counts[i] = uint64(val)
+	}
+
+	// t.NextDealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.NextDealID = abi.DealID(extra)
+
+	}
+	// t.Done (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.Done = false
+	case 21:
+		t.Done = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	return nil
+}
+
+var lengthBufPublishStorageDealsWithOptionsParams = []byte{130}
+
+func (t *PublishStorageDealsWithOptionsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufPublishStorageDealsWithOptionsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Deals ([]market.ClientDealProposal) (slice)
+	if len(t.Deals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Deals was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Deals))); err != nil {
+		return err
+	}
+	for _, v := range t.Deals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.AutoWithdraw (bool) (bool)
+	if err := cbg.WriteBool(w, t.AutoWithdraw); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *PublishStorageDealsWithOptionsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = PublishStorageDealsWithOptionsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Deals ([]market.ClientDealProposal) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Deals: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Deals = make([]ClientDealProposal, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ClientDealProposal
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Deals[i] = v
+	}
+
+	// t.AutoWithdraw (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.AutoWithdraw = false
+	case 21:
+		t.AutoWithdraw = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	return nil
+}
+
+var lengthBufDataCapReconciliationReturn = []byte{130}
+
+func (t *DataCapReconciliationReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDataCapReconciliationReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Mismatches ([]market.DataCapMismatch) (slice)
+	if len(t.Mismatches) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Mismatches was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Mismatches))); err != nil {
+		return err
+	}
+	for _, v := range t.Mismatches {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.Pending (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Pending)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *DataCapReconciliationReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = DataCapReconciliationReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Mismatches ([]market.DataCapMismatch) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Mismatches: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Mismatches = make([]DataCapMismatch, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v DataCapMismatch
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Mismatches[i] = v
+	}
+
+	// t.Pending (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Pending = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufClientAgentParams = []byte{129}
+
+func (t *ClientAgentParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufClientAgentParams); err != nil {
+		return err
+	}
+
+	// t.Agent (address.Address) (struct)
+	if err := t.Agent.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ClientAgentParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ClientAgentParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Agent (address.Address) (struct)
+
+	{
+
+		if err := t.Agent.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Agent: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDealProposal = []byte{139}
+
+func (t *DealProposal) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDealProposal); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.PieceCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.PieceCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.PieceCID: %w", err)
+	}
+
+	// t.PieceSize (abi.PaddedPieceSize) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.PieceSize)); err != nil {
+		return err
+	}
+
+	// t.VerifiedDeal (bool) (bool)
+	if err := cbg.WriteBool(w, t.VerifiedDeal); err != nil {
+		return err
+	}
+
+	// t.Client (address.Address) (struct)
+	if err := t.Client.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Provider (address.Address) (struct)
+	if err := t.Provider.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Label (market.DealLabel) (struct)
+	if err := t.Label.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.StartEpoch (abi.ChainEpoch) (int64)
+	if t.StartEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.StartEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.StartEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.EndEpoch (abi.ChainEpoch) (int64)
+	if t.EndEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.EndEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.EndEpoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.StoragePricePerEpoch (big.Int) (struct)
+	if err := t.StoragePricePerEpoch.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ProviderCollateral (big.Int) (struct)
+	if err := t.ProviderCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ClientCollateral (big.Int) (struct)
 	if err := t.ClientCollateral.MarshalCBOR(w); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (t *DealProposal) UnmarshalCBOR(r io.Reader) error {
-	*t = DealProposal{}
+func (t *DealProposal) UnmarshalCBOR(r io.Reader) error {
+	*t = DealProposal{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 11 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.PieceCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.PieceCID: %w", err)
+		}
+
+		t.PieceCID = c
+
+	}
+	// t.PieceSize (abi.PaddedPieceSize) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.PieceSize = abi.PaddedPieceSize(extra)
+
+	}
+	// t.VerifiedDeal (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.VerifiedDeal = false
+	case 21:
+		t.VerifiedDeal = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	// t.Client (address.Address) (struct)
+
+	{
+
+		if err := t.Client.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Client: %w", err)
+		}
+
+	}
+	// t.Provider (address.Address) (struct)
+
+	{
+
+		if err := t.Provider.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Provider: %w", err)
+		}
+
+	}
+	// t.Label (market.DealLabel) (struct)
+
+	{
+
+		if err := t.Label.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Label: %w", err)
+		}
+
+	}
+	// t.StartEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.StartEpoch = abi.ChainEpoch(extraI)
+	}
+	// t.EndEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.EndEpoch = abi.ChainEpoch(extraI)
+	}
+	// t.StoragePricePerEpoch (big.Int) (struct)
+
+	{
+
+		if err := t.StoragePricePerEpoch.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.StoragePricePerEpoch: %w", err)
+		}
+
+	}
+	// t.ProviderCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.ProviderCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ProviderCollateral: %w", err)
+		}
+
+	}
+	// t.ClientCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.ClientCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ClientCollateral: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufClientDealProposal = []byte{130}
+
+func (t *ClientDealProposal) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufClientDealProposal); err != nil {
+		return err
+	}
+
+	// t.Proposal (market.DealProposal) (struct)
+	if err := t.Proposal.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ClientSignature (crypto.Signature) (struct)
+	if err := t.ClientSignature.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ClientDealProposal) UnmarshalCBOR(r io.Reader) error {
+	*t = ClientDealProposal{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Proposal (market.DealProposal) (struct)
+
+	{
+
+		if err := t.Proposal.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Proposal: %w", err)
+		}
+
+	}
+	// t.ClientSignature (crypto.Signature) (struct)
+
+	{
+
+		if err := t.ClientSignature.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ClientSignature: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufDataCapMismatch = []byte{133}
+
+func (t *DataCapMismatch) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufDataCapMismatch); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.DealID)); err != nil {
+		return err
+	}
+
+	// t.Client (address.Address) (struct)
+	if err := t.Client.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Consumed (big.Int) (struct)
+	if err := t.Consumed.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Restored (big.Int) (struct)
+	if err := t.Restored.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.RestoreEpoch (abi.ChainEpoch) (int64)
+	if t.RestoreEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.RestoreEpoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.RestoreEpoch-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *DataCapMismatch) UnmarshalCBOR(r io.Reader) error {
+	*t = DataCapMismatch{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 5 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.DealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.DealID = abi.DealID(extra)
+
+	}
+	// t.Client (address.Address) (struct)
+
+	{
+
+		if err := t.Client.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Client: %w", err)
+		}
+
+	}
+	// t.Consumed (big.Int) (struct)
+
+	{
+
+		if err := t.Consumed.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Consumed: %w", err)
+		}
+
+	}
+	// t.Restored (big.Int) (struct)
+
+	{
+
+		if err := t.Restored.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Restored: %w", err)
+		}
+
+	}
+	// t.RestoreEpoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.RestoreEpoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufEscrowBreakdownParams = []byte{131}
+
+func (t *EscrowBreakdownParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufEscrowBreakdownParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Address (address.Address) (struct)
+	if err := t.Address.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.StartDealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.StartDealID)); err != nil {
+		return err
+	}
+
+	// t.Limit (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Limit)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *EscrowBreakdownParams) UnmarshalCBOR(r io.Reader) error {
+	*t = EscrowBreakdownParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Address (address.Address) (struct)
+
+	{
+
+		if err := t.Address.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Address: %w", err)
+		}
+
+	}
+	// t.StartDealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.StartDealID = abi.DealID(extra)
+
+	}
+	// t.Limit (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Limit = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufEscrowBreakdownReturn = []byte{135}
+
+func (t *EscrowBreakdownReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufEscrowBreakdownReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Total (big.Int) (struct)
+	if err := t.Total.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Locked (big.Int) (struct)
+	if err := t.Locked.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Available (big.Int) (struct)
+	if err := t.Available.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.LockedStorageFee (big.Int) (struct)
+	if err := t.LockedStorageFee.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.LockedCollateral (big.Int) (struct)
+	if err := t.LockedCollateral.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.NextDealID (abi.DealID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NextDealID)); err != nil {
+		return err
+	}
+
+	// t.Done (bool) (bool)
+	if err := cbg.WriteBool(w, t.Done); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *EscrowBreakdownReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = EscrowBreakdownReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 7 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Total (big.Int) (struct)
+
+	{
+
+		if err := t.Total.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Total: %w", err)
+		}
+
+	}
+	// t.Locked (big.Int) (struct)
+
+	{
+
+		if err := t.Locked.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Locked: %w", err)
+		}
+
+	}
+	// t.Available (big.Int) (struct)
+
+	{
+
+		if err := t.Available.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Available: %w", err)
+		}
+
+	}
+	// t.LockedStorageFee (big.Int) (struct)
+
+	{
+
+		if err := t.LockedStorageFee.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.LockedStorageFee: %w", err)
+		}
+
+	}
+	// t.LockedCollateral (big.Int) (struct)
+
+	{
+
+		if err := t.LockedCollateral.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.LockedCollateral: %w", err)
+		}
+
+	}
+	// t.NextDealID (abi.DealID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.NextDealID = abi.DealID(extra)
+
+	}
+	// t.Done (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.Done = false
+	case 21:
+		t.Done = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	return nil
+}
+
+var lengthBufBatchActivateDealsParams = []byte{129}
+
+func (t *BatchActivateDealsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufBatchActivateDealsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Sectors ([]market.SectorDeals) (slice)
+	if len(t.Sectors) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Sectors was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Sectors))); err != nil {
+		return err
+	}
+	for _, v := range t.Sectors {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *BatchActivateDealsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = BatchActivateDealsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Sectors ([]market.SectorDeals) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Sectors: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Sectors = make([]market.SectorDeals, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v market.SectorDeals
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Sectors[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufBatchActivateDealsReturn = []byte{129}
+
+func (t *BatchActivateDealsReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufBatchActivateDealsReturn); err != nil {
+		return err
+	}
+
+	// t.Activated (bitfield.BitField) (struct)
+	if err := t.Activated.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *BatchActivateDealsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = BatchActivateDealsReturn{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -576,81 +2453,78 @@ func (t *DealProposal) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 11 {
+	if extra != 1 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.PieceCID (cid.Cid) (struct)
+	// t.Activated (bitfield.BitField) (struct)
 
 	{
 
-		c, err := cbg.ReadCid(br)
-		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.PieceCID: %w", err)
+		if err := t.Activated.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Activated: %w", err)
 		}
 
-		t.PieceCID = c
-
 	}
-	// t.PieceSize (abi.PaddedPieceSize) (uint64)
-
-	{
-
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-		if err != nil {
-			return err
-		}
-		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
-		}
-		t.PieceSize = abi.PaddedPieceSize(extra)
+	return nil
+}
 
-	}
-	// t.VerifiedDeal (bool) (bool)
+var lengthBufUpdateDealDurationBoundsParams = []byte{130}
 
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
+func (t *UpdateDealDurationBoundsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if maj != cbg.MajOther {
-		return fmt.Errorf("booleans must be major type 7")
-	}
-	switch extra {
-	case 20:
-		t.VerifiedDeal = false
-	case 21:
-		t.VerifiedDeal = true
-	default:
-		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	if _, err := w.Write(lengthBufUpdateDealDurationBoundsParams); err != nil {
+		return err
 	}
-	// t.Client (address.Address) (struct)
 
-	{
+	scratch := make([]byte, 9)
 
-		if err := t.Client.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Client: %w", err)
+	// t.MinDuration (abi.ChainEpoch) (int64)
+	if t.MinDuration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.MinDuration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.MinDuration-1)); err != nil {
+			return err
 		}
-
 	}
-	// t.Provider (address.Address) (struct)
-
-	{
 
-		if err := t.Provider.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Provider: %w", err)
+	// t.MaxDuration (abi.ChainEpoch) (int64)
+	if t.MaxDuration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.MaxDuration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.MaxDuration-1)); err != nil {
+			return err
 		}
-
 	}
-	// t.Label (market.DealLabel) (struct)
+	return nil
+}
 
-	{
+func (t *UpdateDealDurationBoundsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = UpdateDealDurationBoundsParams{}
 
-		if err := t.Label.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Label: %w", err)
-		}
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
 
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
 	}
-	// t.StartEpoch (abi.ChainEpoch) (int64)
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.MinDuration (abi.ChainEpoch) (int64)
 	{
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
@@ -673,9 +2547,9 @@ func (t *DealProposal) UnmarshalCBOR(r io.Reader) error {
 			return fmt.Errorf("wrong type for int64 field: %d", maj)
 		}
 
-		t.StartEpoch = abi.ChainEpoch(extraI)
+		t.MinDuration = abi.ChainEpoch(extraI)
 	}
-	// t.EndEpoch (abi.ChainEpoch) (int64)
+	// t.MaxDuration (abi.ChainEpoch) (int64)
 	{
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
@@ -698,63 +2572,187 @@ func (t *DealProposal) UnmarshalCBOR(r io.Reader) error {
 			return fmt.Errorf("wrong type for int64 field: %d", maj)
 		}
 
-		t.EndEpoch = abi.ChainEpoch(extraI)
+		t.MaxDuration = abi.ChainEpoch(extraI)
 	}
-	// t.StoragePricePerEpoch (big.Int) (struct)
+	return nil
+}
 
-	{
+var lengthBufSettleDealPaymentsParams = []byte{130}
 
-		if err := t.StoragePricePerEpoch.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.StoragePricePerEpoch: %w", err)
+func (t *SettleDealPaymentsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSettleDealPaymentsParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Provider (address.Address) (struct)
+	if err := t.Provider.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+func (t *SettleDealPaymentsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SettleDealPaymentsParams{}
 
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
 	}
-	// t.ProviderCollateral (big.Int) (struct)
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Provider (address.Address) (struct)
 
 	{
 
-		if err := t.ProviderCollateral.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ProviderCollateral: %w", err)
+		if err := t.Provider.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Provider: %w", err)
 		}
 
 	}
-	// t.ClientCollateral (big.Int) (struct)
+	// t.DealIDs ([]abi.DealID) (slice)
 
-	{
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
 
-		if err := t.ClientCollateral.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ClientCollateral: %w", err)
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
 		}
 
+		t.DealIDs[i] = abi.DealID(val)
 	}
+
 	return nil
 }
 
-var lengthBufClientDealProposal = []byte{130}
+var lengthBufSettleDealPaymentsReturn = []byte{129}
 
-func (t *ClientDealProposal) MarshalCBOR(w io.Writer) error {
+func (t *SettleDealPaymentsReturn) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufClientDealProposal); err != nil {
+	if _, err := w.Write(lengthBufSettleDealPaymentsReturn); err != nil {
 		return err
 	}
 
-	// t.Proposal (market.DealProposal) (struct)
-	if err := t.Proposal.MarshalCBOR(w); err != nil {
+	// t.PaymentTransferred (big.Int) (struct)
+	if err := t.PaymentTransferred.MarshalCBOR(w); err != nil {
 		return err
 	}
+	return nil
+}
 
-	// t.ClientSignature (crypto.Signature) (struct)
-	if err := t.ClientSignature.MarshalCBOR(w); err != nil {
+func (t *SettleDealPaymentsReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = SettleDealPaymentsReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
 		return err
 	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.PaymentTransferred (big.Int) (struct)
+
+	{
+
+		if err := t.PaymentTransferred.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.PaymentTransferred: %w", err)
+		}
+
+	}
 	return nil
 }
 
-func (t *ClientDealProposal) UnmarshalCBOR(r io.Reader) error {
-	*t = ClientDealProposal{}
+var lengthBufClearDataCapMismatchesParams = []byte{129}
+
+func (t *ClearDataCapMismatchesParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufClearDataCapMismatchesParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.DealIDs ([]abi.DealID) (slice)
+	if len(t.DealIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.DealIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.DealIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.DealIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ClearDataCapMismatchesParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ClearDataCapMismatchesParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -767,27 +2765,42 @@ func (t *ClientDealProposal) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 2 {
+	if extra != 1 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Proposal (market.DealProposal) (struct)
+	// t.DealIDs ([]abi.DealID) (slice)
 
-	{
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
 
-		if err := t.Proposal.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Proposal: %w", err)
-		}
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.DealIDs: array too large (%d)", extra)
+	}
 
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
 	}
-	// t.ClientSignature (crypto.Signature) (struct)
 
-	{
+	if extra > 0 {
+		t.DealIDs = make([]abi.DealID, extra)
+	}
 
-		if err := t.ClientSignature.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.ClientSignature: %w", err)
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.DealIDs slice: %w", err)
 		}
 
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.DealIDs was not a uint, instead got %d", maj)
+		}
+
+		t.DealIDs[i] = abi.DealID(val)
 	}
+
 	return nil
 }
@@ -60,6 +60,15 @@ func NewLabelFromBytes(b []byte) (DealLabel, error) {
 	}, nil
 }
 
+// NewLabelFromCID returns a string label holding the canonical string encoding of a CID,
+// the conventional form for a label referencing the deal's payload.
+func NewLabelFromCID(c cid.Cid) (DealLabel, error) {
+	if !c.Defined() {
+		return EmptyDealLabel, xerrors.Errorf("cannot make a label from an undefined cid")
+	}
+	return NewLabelFromString(c.String())
+}
+
 func (label DealLabel) IsString() bool {
 	return !label.notString
 }
@@ -83,6 +92,38 @@ func (label DealLabel) ToBytes() ([]byte, error) {
 	return label.bs, nil
 }
 
+// IsCID returns whether the label is a string encoding a CID.
+func (label DealLabel) IsCID() bool {
+	_, err := label.ToCID()
+	return err == nil
+}
+
+// ToCID decodes the CID a string label encodes.
+// Raw byte labels and strings that are not CIDs are rejected.
+func (label DealLabel) ToCID() (cid.Cid, error) {
+	str, err := label.ToString()
+	if err != nil {
+		return cid.Undef, err
+	}
+	c, err := cid.Decode(str)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("label is not a cid: %w", err)
+	}
+	return c, nil
+}
+
+// Validate checks the label respects the limits imposed on labels constructed through
+// NewLabelFromString or NewLabelFromBytes.
+func (label DealLabel) Validate() error {
+	if label.Length() > DealMaxLabelSize {
+		return xerrors.Errorf("deal label can be at most %d bytes, is %d", DealMaxLabelSize, label.Length())
+	}
+	if label.IsString() && !utf8.Valid(label.bs) {
+		return xerrors.Errorf("deal label string is invalid utf8")
+	}
+	return nil
+}
+
 func (label DealLabel) Length() int {
 	return len(label.bs)
 }
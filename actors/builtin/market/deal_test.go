@@ -209,6 +209,37 @@ func TestDealLabelFromCBOR(t *testing.T) {
 	assert.Contains(t, err.Error(), "unexpected major tag")
 }
 
+func TestDealLabelCID(t *testing.T) {
+	payload := tutil.MakeCID("payload", nil)
+
+	// cid label is a string label of the cid's string encoding
+	label, err := market.NewLabelFromCID(payload)
+	require.NoError(t, err)
+	assert.True(t, label.IsString())
+	assert.True(t, label.IsCID())
+	str, err := label.ToString()
+	require.NoError(t, err)
+	assert.Equal(t, payload.String(), str)
+	c, err := label.ToCID()
+	require.NoError(t, err)
+	assert.Equal(t, payload, c)
+	assert.NoError(t, label.Validate())
+
+	// undefined cid is rejected
+	_, err = market.NewLabelFromCID(cid.Undef)
+	assert.Error(t, err)
+
+	// plain strings and raw bytes are not cids
+	label, err = market.NewLabelFromString("not a cid")
+	require.NoError(t, err)
+	assert.False(t, label.IsCID())
+	_, err = label.ToCID()
+	assert.Error(t, err)
+	label, err = market.NewLabelFromBytes(payload.Bytes())
+	require.NoError(t, err)
+	assert.False(t, label.IsCID())
+}
+
 func TestSerializeProposal(t *testing.T) {
 	// empty proposal serializes as normal
 	empty := market.DealProposal{
@@ -1,6 +1,8 @@
 package market
 
 import (
+	"bytes"
+	"errors"
 	"sort"
 
 	addr "github.com/filecoin-project/go-address"
@@ -41,6 +43,19 @@ func (a Actor) Exports() []interface{} {
 		7:                         a.OnMinerSectorsTerminate,
 		8:                         a.ComputeDataCommitment,
 		9:                         a.CronTick,
+		10:                        a.DealCollateralBounds,
+		11:                        a.DealDurationHistogram,
+		12:                        a.DataCapReconciliation,
+		13:                        a.ComputeDealProposalCid,
+		14:                        a.AuthorizeClientAgent,
+		15:                        a.RevokeClientAgent,
+		16:                        a.EscrowBreakdown,
+		17:                        a.BatchActivateDeals,
+		18:                        a.UpdateDealDurationBounds,
+		19:                        a.SettleDealPayments,
+		20:                        a.PublishStorageDealsWithOptions,
+		21:                        a.ClearDataCapMismatches,
+		22:                        a.LastCronTick,
 	}
 }
 
@@ -152,15 +167,31 @@ type PublishStorageDealsReturn = market6.PublishStorageDealsReturn
 
 // Publish a new set of storage deals (not yet included in a sector).
 func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams) *PublishStorageDealsReturn {
+	return publishStorageDeals(rt, params.Deals, false)
+}
+
+type PublishStorageDealsWithOptionsParams struct {
+	Deals []ClientDealProposal
+	// When set, the collateral and payment of each published deal are withdrawn from escrow
+	// to the provider's owner when the deal completes, rather than remaining in escrow.
+	AutoWithdraw bool
+}
+
+// Publish a new set of storage deals, as for PublishStorageDeals, with options applying to each of the deals.
+func (a Actor) PublishStorageDealsWithOptions(rt Runtime, params *PublishStorageDealsWithOptionsParams) *PublishStorageDealsReturn {
+	return publishStorageDeals(rt, params.Deals, params.AutoWithdraw)
+}
+
+func publishStorageDeals(rt Runtime, deals []ClientDealProposal, autoWithdraw bool) *PublishStorageDealsReturn {
 	// Deal message must have a From field identical to the provider of all the deals.
 	// This allows us to retain and verify only the client's signature in each deal proposal itself.
 	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
-	if len(params.Deals) == 0 {
+	if len(deals) == 0 {
 		rt.Abortf(exitcode.ErrIllegalArgument, "empty deals parameter")
 	}
 
 	// All deals should have the same provider so get worker once
-	providerRaw := params.Deals[0].Proposal.Provider
+	providerRaw := deals[0].Proposal.Provider
 	provider, ok := rt.ResolveAddress(providerRaw)
 	if !ok {
 		rt.Abortf(exitcode.ErrNotFound, "failed to resolve provider address %v", providerRaw)
@@ -184,7 +215,7 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	if !callerOk {
 		rt.Abortf(exitcode.ErrForbidden, "caller %v is not worker or control address of provider %v", caller, provider)
 	}
-	resolvedAddrs := make(map[addr.Address]addr.Address, len(params.Deals))
+	resolvedAddrs := make(map[addr.Address]addr.Address, len(deals))
 	baselinePower := requestCurrentBaselinePower(rt)
 	networkRawPower, networkQAPower := requestCurrentNetworkPower(rt)
 
@@ -192,20 +223,42 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	var st State
 	proposalCidLookup := make(map[cid.Cid]struct{})
 	validProposalCids := make([]cid.Cid, 0)
-	validDeals := make([]ClientDealProposal, 0, len(params.Deals))
+	validDeals := make([]ClientDealProposal, 0, len(deals))
+	validDataCapConsumed := make([]abi.StoragePower, 0, len(deals))
 	totalClientLockup := make(map[addr.Address]abi.TokenAmount)
 	totalProviderLockup := abi.NewTokenAmount(0)
 
 	validInputBf := bitfield.New()
+	deferredFrom := len(deals)
 	rt.StateReadonly(&st)
 	msm, err := st.mutator(adt.AsStore(rt)).withPendingProposals(ReadOnlyPermission).
-		withEscrowTable(ReadOnlyPermission).withLockedTable(ReadOnlyPermission).build()
+		withEscrowTable(ReadOnlyPermission).withLockedTable(ReadOnlyPermission).
+		withClientAgents(ReadOnlyPermission).build()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
-	for di, deal := range params.Deals {
+	clientAgents := make(map[addr.Address][]addr.Address)
+	for di, deal := range deals {
+		/*
+			defer deals beyond the provider's cap for a single message
+		*/
+		if len(validDeals) >= MaxDealsPerProviderPerPublish {
+			deferredFrom = di
+			break
+		}
+
 		/*
 			drop malformed deals
 		*/
-		if err := validateDeal(rt, deal, networkRawPower, networkQAPower, baselinePower); err != nil {
+		client, clientResolved := rt.ResolveAddress(deal.Proposal.Client)
+		var agents []addr.Address
+		if clientResolved {
+			var loaded bool
+			if agents, loaded = clientAgents[client]; !loaded {
+				agents, err = msm.loadClientAgents(client)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load client agents")
+				clientAgents[client] = agents
+			}
+		}
+		if err := validateDeal(rt, deal, agents, st.DealMinDuration, st.DealMaxDuration, networkRawPower, networkQAPower, baselinePower); err != nil {
 			rt.Log(rtt.INFO, "invalid deal %d: %s", di, err)
 			continue
 		}
@@ -213,8 +266,7 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 			rt.Log(rtt.INFO, "invalid deal %d: cannot publish deals from multiple providers in one batch", di)
 			continue
 		}
-		client, ok := rt.ResolveAddress(deal.Proposal.Client)
-		if !ok {
+		if !clientResolved {
 			rt.Log(rtt.INFO, "invalid deal %d: failed to resolve proposal.Client address %v for deal ", di, deal.Proposal.Client)
 			continue
 		}
@@ -266,7 +318,9 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 			check VerifiedClient allowed cap and deduct PieceSize from cap
 			drop deals with a DealSize that cannot be fully covered by VerifiedClient's available DataCap
 		*/
+		dataCapConsumed := big.Zero()
 		if deal.Proposal.VerifiedDeal {
+			var useBytesRet verifreg.UseBytesReturn
 			code := rt.Send(
 				builtin.VerifiedRegistryActorAddr,
 				builtin.MethodsVerifiedRegistry.UseBytes,
@@ -275,18 +329,23 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 					DealSize: big.NewIntUnsigned(uint64(deal.Proposal.PieceSize)),
 				},
 				abi.NewTokenAmount(0),
-				&builtin.Discard{},
+				&useBytesRet,
 			)
 			if code.IsError() {
 				rt.Log(rtt.INFO, "invalid deal %d: failed to acquire datacap exitcode: %d", di, code)
 				continue
 			}
+			dataCapConsumed = useBytesRet.Consumed
+			if useBytesRet.ClientRemoved {
+				rt.Log(rtt.INFO, "verified client %s removed after deal %d consumed %v of its DataCap", client, di, dataCapConsumed)
+			}
 		}
 
 		// update valid deal state
 		proposalCidLookup[pcid] = struct{}{}
 		validProposalCids = append(validProposalCids, pcid)
 		validDeals = append(validDeals, deal)
+		validDataCapConsumed = append(validDataCapConsumed, dataCapConsumed)
 		validInputBf.Set(uint64(di))
 	}
 
@@ -302,7 +361,8 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	rt.StateTransaction(&st, func() {
 		msm, err := st.mutator(adt.AsStore(rt)).withPendingProposals(WritePermission).
 			withDealProposals(WritePermission).withDealsByEpoch(WritePermission).withEscrowTable(WritePermission).
-			withLockedTable(WritePermission).build()
+			withLockedTable(WritePermission).withDataCapLedger(WritePermission).
+			withAutoWithdrawDeals(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
 		// All storage dealProposals will be added in an atomic transaction; this operation will be unrolled if any of them fails.
@@ -327,12 +387,31 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 			err = msm.dealsByEpoch.Put(processEpoch, id)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal ops by epoch")
 
+			if validDeal.Proposal.VerifiedDeal {
+				err = msm.dataCapLedger.Put(abi.UIntKey(uint64(id)), &DataCapLedgerEntry{
+					Client:       validDeal.Proposal.Client,
+					Consumed:     validDataCapConsumed[vdi],
+					Restored:     big.Zero(),
+					RestoreEpoch: EpochUndefined,
+				})
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record datacap consumed by deal %d", id)
+			}
+
+			if autoWithdraw {
+				err = msm.autoWithdrawDeals.Put(abi.UIntKey(uint64(id)))
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set auto-withdraw deal %d", id)
+			}
+
 			newDealIds = append(newDealIds, id)
 		}
 		err = msm.commitState()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
 	})
 
+	if deferredFrom < len(deals) {
+		rt.Log(rtt.INFO, "deferred %d deals from index %d beyond the limit of %d per provider",
+			len(deals)-deferredFrom, deferredFrom, MaxDealsPerProviderPerPublish)
+	}
 	return &PublishStorageDealsReturn{
 		IDs:        newDealIds,
 		ValidDeals: validInputBf,
@@ -386,7 +465,16 @@ func (a Actor) VerifyDealsForActivation(rt Runtime, params *VerifyDealsForActiva
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
 
 	weights := make([]SectorWeights, len(params.Sectors))
+	sectorOfDeal := make(map[abi.DealID]int)
 	for i, sector := range params.Sectors {
+		// A deal's space must be counted towards the weight of at most one sector.
+		for _, dealID := range sector.DealIDs {
+			if prev, seen := sectorOfDeal[dealID]; seen && prev != i {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal ID %d present in sectors %d and %d", dealID, prev, i)
+			}
+			sectorOfDeal[dealID] = i
+		}
+
 		// Pass the current epoch as the activation epoch for validation.
 		// The sector activation epoch isn't yet known, but it's still more helpful to fail now if the deal
 		// is so late that a sector activating now couldn't include it.
@@ -423,48 +511,69 @@ func (a Actor) ActivateDeals(rt Runtime, params *ActivateDealsParams) *abi.Empty
 
 	// Update deal dealStates.
 	rt.StateTransaction(&st, func() {
-		_, _, _, err := ValidateDealsForActivation(&st, store, params.DealIDs, minerAddr, params.SectorExpiry, currEpoch)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to validate dealProposals for activation")
-
 		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
-			withPendingProposals(ReadOnlyPermission).withDealProposals(ReadOnlyPermission).build()
+			withPendingProposals(ReadOnlyPermission).withDealProposals(ReadOnlyPermission).
+			withDataCapLedger(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
-		for _, dealID := range params.DealIDs {
-			// This construction could be replaced with a single "update deal state" state method, possibly batched
-			// over all deal ids at once.
-			_, found, err := msm.dealStates.Get(dealID)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get state for dealId %d", dealID)
-			if found {
-				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d already included in another sector", dealID)
-			}
+		err = activateSectorDeals(&st, store, msm, params.DealIDs, minerAddr, params.SectorExpiry, currEpoch)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to activate deals")
 
-			proposal, err := getDealProposal(msm.dealProposals, dealID)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get dealId %d", dealID)
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
 
-			propc, err := proposal.Cid()
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate proposal CID")
+	return nil
+}
 
-			has, err := msm.pendingDeals.Has(abi.CidKey(propc))
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get pending proposal %v", propc)
+type BatchActivateDealsParams struct {
+	Sectors []SectorDeals
+}
 
-			if !has {
-				rt.Abortf(exitcode.ErrIllegalState, "tried to activate deal that was not in the pending set (%s)", propc)
-			}
+type BatchActivateDealsReturn struct {
+	// Indices of the sectors, in the order given, whose deals were activated.
+	Activated bitfield.BitField
+}
 
-			err = msm.dealStates.Set(dealID, &DealState{
-				SectorStartEpoch: currEpoch,
-				LastUpdatedEpoch: EpochUndefined,
-				SlashEpoch:       EpochUndefined,
-			})
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal state %d", dealID)
+// Activates the deals for each of a number of sectors being ProveCommitted, as for ActivateDeals.
+// The deals of each sector are validated independently: if any deal of a sector is invalid, none of
+// that sector's deals are activated, but the deals of other sectors may be.
+func (a Actor) BatchActivateDeals(rt Runtime, params *BatchActivateDealsParams) *BatchActivateDealsReturn {
+	rt.ValidateImmediateCallerType(builtin.StorageMinerActorCodeID)
+	minerAddr := rt.Caller()
+	currEpoch := rt.CurrEpoch()
+
+	if len(params.Sectors) > BatchActivateDealsMaxSectors {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors %d, max %d", len(params.Sectors), BatchActivateDealsMaxSectors)
+	}
+
+	var st State
+	store := adt.AsStore(rt)
+	activated := bitfield.New()
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(store).withDealStates(WritePermission).
+			withPendingProposals(ReadOnlyPermission).withDealProposals(ReadOnlyPermission).
+			withDataCapLedger(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for i, sector := range params.Sectors {
+			err = activateSectorDeals(&st, store, msm, sector.DealIDs, minerAddr, sector.SectorExpiry, currEpoch)
+			// Invalid deals are detected before any state is written, so the sector can be skipped.
+			// Any other error indicates a broken invariant and aborts the batch.
+			switch exitcode.Unwrap(err, exitcode.Ok) {
+			case exitcode.ErrIllegalArgument, exitcode.ErrNotFound, exitcode.ErrForbidden:
+				rt.Log(rtt.INFO, "failed to activate deals for sector %d: %s", i, err)
+				continue
+			}
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to activate deals for sector %d", i)
+			activated.Set(uint64(i))
 		}
 
 		err = msm.commitState()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
 	})
 
-	return nil
+	return &BatchActivateDealsReturn{Activated: activated}
 }
 
 //type SectorDataSpec struct {
@@ -579,8 +688,14 @@ func (a Actor) OnMinerSectorsTerminate(rt Runtime, params *OnMinerSectorsTermina
 func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.CronActorAddr)
 	amountSlashed := big.Zero()
+	paymentTransferred := big.Zero()
+	processed := uint64(0)
 
 	var timedOutVerifiedDeals []*DealProposal
+	var timedOutVerifiedDealIDs []abi.DealID
+	// Amounts extracted from escrow for auto-withdrawal, in order of first withdrawal per provider.
+	var autoWithdrawProviders []addr.Address
+	autoWithdrawals := make(map[addr.Address]abi.TokenAmount)
 
 	var st State
 	rt.StateTransaction(&st, func() {
@@ -588,11 +703,24 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 
 		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
 			withLockedTable(WritePermission).withEscrowTable(WritePermission).withDealsByEpoch(WritePermission).
-			withDealProposals(WritePermission).withPendingProposals(WritePermission).build()
+			withDealProposals(WritePermission).withPendingProposals(WritePermission).
+			withAutoWithdrawDeals(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
-		for i := st.LastCron + 1; i <= rt.CurrEpoch(); i++ {
+		// At most MaxDealOpsPerCronTick deal operations are processed. LastCron records the last epoch for which
+		// all operations have been processed, so subsequent ticks resume with any operations remaining.
+		stopErr := errors.New("deal op limit reached")
+		for i := st.LastCron + 1; i <= rt.CurrEpoch() && processed < MaxDealOpsPerCronTick; i++ {
+			var processedIDs []abi.DealID
+			exhausted := false
 			err = msm.dealsByEpoch.ForEach(i, func(dealID abi.DealID) error {
+				if processed >= MaxDealOpsPerCronTick {
+					exhausted = true
+					return stopErr
+				}
+				processed++
+				processedIDs = append(processedIDs, dealID)
+
 				deal, err := getDealProposal(msm.dealProposals, dealID)
 				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get dealId %d", dealID)
 
@@ -614,6 +742,7 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					}
 					if deal.VerifiedDeal {
 						timedOutVerifiedDeals = append(timedOutVerifiedDeals, deal)
+						timedOutVerifiedDealIDs = append(timedOutVerifiedDealIDs, dealID)
 					}
 
 					// Delete the proposal (but not state, which doesn't exist).
@@ -622,6 +751,9 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 
 					err = msm.pendingDeals.Delete(abi.CidKey(dcid))
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete pending proposal %d (%v)", dealID, dcid)
+
+					_, err = msm.autoWithdrawDeals.TryDelete(abi.UIntKey(uint64(dealID)))
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete auto-withdraw deal %d", dealID)
 					return nil
 				}
 
@@ -631,13 +763,29 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					builtin.RequireNoErr(rt, pdErr, exitcode.ErrIllegalState, "failed to delete pending proposal %v", dcid)
 				}
 
-				slashAmount, nextEpoch, removeDeal := msm.updatePendingDealState(rt, state, deal, rt.CurrEpoch())
+				slashAmount, payment, nextEpoch, removeDeal := msm.updatePendingDealState(rt, state, deal, rt.CurrEpoch())
 				builtin.RequireState(rt, slashAmount.GreaterThanEqual(big.Zero()), "computed negative slash amount %v for deal %d", slashAmount, dealID)
+				paymentTransferred = big.Add(paymentTransferred, payment)
 
 				if removeDeal {
 					builtin.RequireState(rt, nextEpoch == EpochUndefined, "removed deal %d should have no scheduled epoch (got %d)", dealID, nextEpoch)
 					amountSlashed = big.Add(amountSlashed, slashAmount)
 
+					if state.SlashEpoch == EpochUndefined {
+						withdrawn, err := msm.processDealAutoWithdraw(dealID, deal)
+						builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to auto-withdraw deal %d", dealID)
+						if withdrawn.GreaterThan(big.Zero()) {
+							if _, ok := autoWithdrawals[deal.Provider]; !ok {
+								autoWithdrawProviders = append(autoWithdrawProviders, deal.Provider)
+								autoWithdrawals[deal.Provider] = big.Zero()
+							}
+							autoWithdrawals[deal.Provider] = big.Add(autoWithdrawals[deal.Provider], withdrawn)
+						}
+					} else {
+						_, err = msm.autoWithdrawDeals.TryDelete(abi.UIntKey(uint64(dealID)))
+						builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete auto-withdraw deal %d", dealID)
+					}
+
 					// Delete proposal and state simultaneously.
 					err = msm.dealStates.Delete(dealID)
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal state %d", dealID)
@@ -657,10 +805,20 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 
 				return nil
 			})
+			if err == stopErr {
+				err = nil
+			}
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate deal ops")
 
+			if exhausted {
+				err = msm.dealsByEpoch.RemoveMany(i, processedIDs)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal ops for epoch %v", i)
+				break
+			}
+
 			err = msm.dealsByEpoch.RemoveAll(i)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal ops for epoch %v", i)
+			st.LastCron = i
 		}
 
 		// Iterate changes in sorted order to ensure that loads/stores
@@ -678,13 +836,21 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to reinsert deal IDs for epoch %v", epoch)
 		}
 
-		st.LastCron = rt.CurrEpoch()
+		st.LastCronTick = CronTickSummary{
+			Epoch:              rt.CurrEpoch(),
+			AmountSlashed:      amountSlashed,
+			PaymentTransferred: paymentTransferred,
+			DealsProcessed:     processed,
+		}
 
 		err = msm.commitState()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
 	})
 
-	for _, d := range timedOutVerifiedDeals {
+	restored := make([]abi.StoragePower, len(timedOutVerifiedDeals))
+	for i, d := range timedOutVerifiedDeals {
+		restored[i] = big.Zero()
+		var restoreBytesRet verifreg.RestoreBytesReturn
 		code := rt.Send(
 			builtin.VerifiedRegistryActorAddr,
 			builtin.MethodsVerifiedRegistry.RestoreBytes,
@@ -693,15 +859,36 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 				DealSize: big.NewIntUnsigned(uint64(d.PieceSize)),
 			},
 			abi.NewTokenAmount(0),
-			&builtin.Discard{},
+			&restoreBytesRet,
 		)
 
 		if !code.IsSuccess() {
 			rt.Log(rtt.ERROR, "failed to send RestoreBytes call to the VerifReg actor for timed-out verified deal, client: %s, dealSize: %v, "+
 				"provider: %v, got code %v", d.Client, d.PieceSize, d.Provider, code)
+		} else {
+			restored[i] = restoreBytesRet.Restored
 		}
 	}
 
+	if len(timedOutVerifiedDeals) > 0 {
+		rt.StateTransaction(&st, func() {
+			msm, err := st.mutator(adt.AsStore(rt)).withDataCapLedger(WritePermission).build()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+			for i, dealID := range timedOutVerifiedDealIDs {
+				err = msm.recordDataCapRestored(dealID, restored[i], rt.CurrEpoch())
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record datacap restored for deal %d", dealID)
+			}
+
+			err = msm.commitState()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+		})
+	}
+
+	for _, provider := range autoWithdrawProviders {
+		sendAutoWithdrawal(rt, provider, autoWithdrawals[provider])
+	}
+
 	if !amountSlashed.IsZero() {
 		e := rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, amountSlashed, &builtin.Discard{})
 		builtin.RequireSuccess(rt, e, "expected send to burnt funds actor to succeed")
@@ -710,6 +897,480 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 	return nil
 }
 
+// Returns the totals of the deal operations processed by the most recent CronTick.
+func (a Actor) LastCronTick(rt Runtime, _ *abi.EmptyValue) *CronTickSummary {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	return &st.LastCronTick
+}
+
+type DealCollateralBoundsParams struct {
+	PieceSize    abi.PaddedPieceSize
+	VerifiedDeal bool
+	Duration     abi.ChainEpoch
+}
+
+type DealCollateralBoundsReturn struct {
+	MinProviderCollateral abi.TokenAmount
+	MaxProviderCollateral abi.TokenAmount
+	MinClientCollateral   abi.TokenAmount
+	MaxClientCollateral   abi.TokenAmount
+}
+
+// Returns the bounds on provider and client collateral that PublishStorageDeals would currently accept
+// for a deal of the given size and duration.
+// The provider bounds depend on network power and circulating supply, so may change from epoch to epoch.
+func (a Actor) DealCollateralBounds(rt Runtime, params *DealCollateralBoundsParams) *DealCollateralBoundsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	if err := params.PieceSize.Validate(); err != nil {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid piece size %d: %s", params.PieceSize, err)
+	}
+	builtin.RequireParam(rt, params.Duration > 0, "deal duration %d must be positive", params.Duration)
+
+	baselinePower := requestCurrentBaselinePower(rt)
+	networkRawPower, networkQAPower := requestCurrentNetworkPower(rt)
+
+	minProviderCollateral, maxProviderCollateral := DealProviderCollateralBounds(params.PieceSize, params.VerifiedDeal,
+		networkRawPower, networkQAPower, baselinePower, rt.TotalFilCircSupply())
+	minClientCollateral, maxClientCollateral := DealClientCollateralBounds(params.PieceSize, params.Duration)
+	return &DealCollateralBoundsReturn{
+		MinProviderCollateral: minProviderCollateral,
+		MaxProviderCollateral: maxProviderCollateral,
+		MinClientCollateral:   minClientCollateral,
+		MaxClientCollateral:   maxClientCollateral,
+	}
+}
+
+type DealDurationHistogramParams struct {
+	// Strictly ascending exclusive upper bounds on remaining deal duration, one per bucket.
+	// Deals with remaining duration at or beyond the last bound are counted in a final overflow bucket.
+	BucketBounds []abi.ChainEpoch
+	// The deal ID from which to start (or resume) the scan.
+	StartDealID abi.DealID
+	// The maximum number of deal IDs to examine. Zero or values over DealDurationHistogramMaxScan
+	// are treated as DealDurationHistogramMaxScan.
+	Limit uint64
+}
+
+type DealDurationHistogramReturn struct {
+	// Count of active deals in each bucket, with len(BucketBounds)+1 entries.
+	Counts []uint64
+	// The deal ID at which to resume the scan, equal to the next unallocated deal ID once done.
+	NextDealID abi.DealID
+	// Whether the scan has covered all deals.
+	Done bool
+}
+
+// Buckets active deals by remaining duration, examining at most a bounded number of deal IDs.
+// Callers page through the full deal set by passing each call's NextDealID to the next and summing counts.
+// A deal is active if it has been activated, not slashed, and has not reached its end epoch.
+func (a Actor) DealDurationHistogram(rt Runtime, params *DealDurationHistogramParams) *DealDurationHistogramReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	builtin.RequireParam(rt, len(params.BucketBounds) > 0, "must specify at least one bucket bound")
+	builtin.RequireParam(rt, len(params.BucketBounds) <= DealDurationHistogramMaxBuckets,
+		"bucket count %d exceeds maximum of %d", len(params.BucketBounds), DealDurationHistogramMaxBuckets)
+	for i, bound := range params.BucketBounds {
+		builtin.RequireParam(rt, bound > 0, "bucket bound %d must be positive", bound)
+		if i > 0 {
+			builtin.RequireParam(rt, bound > params.BucketBounds[i-1], "bucket bounds must be strictly ascending, %d follows %d",
+				bound, params.BucketBounds[i-1])
+		}
+	}
+	limit := params.Limit
+	if limit == 0 || limit > DealDurationHistogramMaxScan {
+		limit = DealDurationHistogramMaxScan
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+	proposals, err := AsDealProposalArray(store, st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	states, err := AsDealStateArray(store, st.States)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal states")
+
+	currEpoch := rt.CurrEpoch()
+	counts := make([]uint64, len(params.BucketBounds)+1)
+	dealID := params.StartDealID
+	for scanned := uint64(0); dealID < st.NextID && scanned < limit; scanned++ {
+		state, found, err := states.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal state %d", dealID)
+		if found && state.SectorStartEpoch != EpochUndefined && state.SlashEpoch == EpochUndefined {
+			proposal, found, err := proposals.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", dealID)
+			if !found {
+				rt.Abortf(exitcode.ErrIllegalState, "no proposal for active deal %d", dealID)
+			}
+			if remaining := proposal.EndEpoch - currEpoch; remaining > 0 {
+				bucket := sort.Search(len(params.BucketBounds), func(i int) bool {
+					return remaining < params.BucketBounds[i]
+				})
+				counts[bucket]++
+			}
+		}
+		dealID++
+	}
+
+	return &DealDurationHistogramReturn{
+		Counts:     counts,
+		NextDealID: dealID,
+		Done:       dealID >= st.NextID,
+	}
+}
+
+type EscrowBreakdownParams struct {
+	Address addr.Address
+	// The deal ID from which to start (or resume) the scan of deals.
+	StartDealID abi.DealID
+	// The maximum number of deal IDs to examine. Zero or values over EscrowBreakdownMaxScan
+	// are treated as EscrowBreakdownMaxScan.
+	Limit uint64
+}
+
+type EscrowBreakdownReturn struct {
+	// The address's escrow balance, the part of it locked, and the remainder available for withdrawal.
+	// These are the same for every page of a scan.
+	Total     abi.TokenAmount
+	Locked    abi.TokenAmount
+	Available abi.TokenAmount
+	// The storage fees the address has yet to pay as client of the deals examined.
+	LockedStorageFee abi.TokenAmount
+	// The collateral the address has locked as client or provider of the deals examined.
+	LockedCollateral abi.TokenAmount
+	// The deal ID at which to resume the scan, equal to the next unallocated deal ID once done.
+	NextDealID abi.DealID
+	// Whether the scan has covered all deals.
+	Done bool
+}
+
+// Returns an address's escrow balance, split into locked and available funds, and attributes the locked funds to
+// unpaid storage fees and deal collateral, examining at most a bounded number of deal IDs.
+// Callers page through the full deal set by passing each call's NextDealID to the next and summing the locked
+// storage fee and collateral, which once done sum to the locked balance.
+func (a Actor) EscrowBreakdown(rt Runtime, params *EscrowBreakdownParams) *EscrowBreakdownReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	nominal, ok := rt.ResolveAddress(params.Address)
+	if !ok {
+		rt.Abortf(exitcode.ErrNotFound, "failed to resolve address %v", params.Address)
+	}
+	limit := params.Limit
+	if limit == 0 || limit > EscrowBreakdownMaxScan {
+		limit = EscrowBreakdownMaxScan
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	msm, err := st.mutator(adt.AsStore(rt)).withEscrowTable(ReadOnlyPermission).
+		withLockedTable(ReadOnlyPermission).withDealProposals(ReadOnlyPermission).
+		withDealStates(ReadOnlyPermission).build()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+	total, err := msm.escrowTable.Get(nominal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get escrow balance for %v", nominal)
+	locked, err := msm.lockedTable.Get(nominal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get locked balance for %v", nominal)
+
+	ret := EscrowBreakdownReturn{
+		Total:            total,
+		Locked:           locked,
+		Available:        big.Sub(total, locked),
+		LockedStorageFee: big.Zero(),
+		LockedCollateral: big.Zero(),
+	}
+	dealID := params.StartDealID
+	for scanned := uint64(0); dealID < st.NextID && scanned < limit; scanned++ {
+		proposal, found, err := msm.dealProposals.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposal %d", dealID)
+		if found && proposal.Client == nominal {
+			// Storage fees are unlocked as they are paid, up to the deal's last update.
+			fee := proposal.TotalStorageFee()
+			state, found, err := msm.dealStates.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal state %d", dealID)
+			if found && state.LastUpdatedEpoch != EpochUndefined {
+				fee, err = dealGetPaymentRemaining(proposal, state.LastUpdatedEpoch)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute remaining payment for deal %d", dealID)
+			}
+			ret.LockedStorageFee = big.Add(ret.LockedStorageFee, fee)
+			ret.LockedCollateral = big.Add(ret.LockedCollateral, proposal.ClientCollateral)
+		}
+		if found && proposal.Provider == nominal {
+			ret.LockedCollateral = big.Add(ret.LockedCollateral, proposal.ProviderCollateral)
+		}
+		dealID++
+	}
+	ret.NextDealID = dealID
+	ret.Done = dealID >= st.NextID
+	return &ret
+}
+
+type DataCapMismatch struct {
+	DealID   abi.DealID
+	Client   addr.Address
+	Consumed abi.StoragePower
+	Restored abi.StoragePower
+	// The epoch at which the deal timed out and its DataCap restoration was requested.
+	RestoreEpoch abi.ChainEpoch
+}
+
+type DataCapReconciliationReturn struct {
+	// Timed-out verified deals for which the DataCap restored differs from that consumed, in deal ID order.
+	Mismatches []DataCapMismatch
+	// The number of verified deals whose consumed DataCap is still pending activation or timeout.
+	Pending uint64
+}
+
+// Reports timed-out verified deals whose DataCap was not fully restored to the client by the verified registry.
+// This iterates the whole DataCap ledger, which holds only pending verified deals and unreconciled mismatches.
+func (a Actor) DataCapReconciliation(rt Runtime, _ *abi.EmptyValue) *DataCapReconciliationReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	msm, err := st.mutator(adt.AsStore(rt)).withDataCapLedger(ReadOnlyPermission).build()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+	ret := DataCapReconciliationReturn{Mismatches: []DataCapMismatch{}}
+	var entry DataCapLedgerEntry
+	err = msm.dataCapLedger.ForEach(&entry, func(key string) error {
+		if entry.RestoreEpoch == EpochUndefined {
+			ret.Pending++
+			return nil
+		}
+		dealID, err := abi.ParseUIntKey(key)
+		if err != nil {
+			return xerrors.Errorf("invalid deal ID key %q: %w", key, err)
+		}
+		ret.Mismatches = append(ret.Mismatches, DataCapMismatch{
+			DealID:       abi.DealID(dealID),
+			Client:       entry.Client,
+			Consumed:     entry.Consumed,
+			Restored:     entry.Restored,
+			RestoreEpoch: entry.RestoreEpoch,
+		})
+		return nil
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate datacap ledger")
+
+	sort.Slice(ret.Mismatches, func(i, j int) bool { return ret.Mismatches[i].DealID < ret.Mismatches[j].DealID })
+	return &ret
+}
+
+type ClearDataCapMismatchesParams struct {
+	DealIDs []abi.DealID
+}
+
+// Removes DataCap mismatches from the ledger once the client has reconciled them with the verified registry.
+// Only the client of a timed-out deal may clear its entry; entries for deals still pending cannot be cleared.
+func (a Actor) ClearDataCapMismatches(rt Runtime, params *ClearDataCapMismatchesParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	caller := rt.Caller()
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDataCapLedger(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for _, dealID := range params.DealIDs {
+			// Aborting below discards the deletion along with the rest of the transaction.
+			raw, found, err := msm.dataCapLedger.TryDelete(abi.UIntKey(uint64(dealID)))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete datacap ledger entry for deal %d", dealID)
+			if !found {
+				rt.Abortf(exitcode.ErrNotFound, "no datacap ledger entry for deal %d", dealID)
+			}
+			var entry DataCapLedgerEntry
+			err = entry.UnmarshalCBOR(bytes.NewReader(raw))
+			builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to decode datacap ledger entry for deal %d", dealID)
+			if entry.Client != caller {
+				rt.Abortf(exitcode.ErrForbidden, "caller %v is not the client %v of deal %d", caller, entry.Client, dealID)
+			}
+			if entry.RestoreEpoch == EpochUndefined {
+				rt.Abortf(exitcode.ErrForbidden, "deal %d is pending and its datacap has not been restored", dealID)
+			}
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return nil
+}
+
+// Computes the CID of a deal proposal, as recorded in pending proposals when the deal is published.
+// The proposal is not validated and need not have been published.
+func (a Actor) ComputeDealProposalCid(rt Runtime, params *DealProposal) *cbg.CborCid {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	pcid, err := params.Cid()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to take cid of proposal")
+	ret := cbg.CborCid(pcid)
+	return &ret
+}
+
+type ClientAgentParams struct {
+	Agent addr.Address
+}
+
+// Authorizes an address to sign deal proposals on behalf of the calling client.
+// Deals so signed are published and paid for exactly as if signed by the client.
+func (a Actor) AuthorizeClientAgent(rt Runtime, params *ClientAgentParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	client := rt.Caller()
+	agent, ok := rt.ResolveAddress(params.Agent)
+	if !ok {
+		rt.Abortf(exitcode.ErrNotFound, "failed to resolve agent address %v", params.Agent)
+	}
+	builtin.RequireParam(rt, agent != client, "client %v cannot authorize itself", client)
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withClientAgents(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		agents, err := msm.loadClientAgents(client)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load client agents")
+		for _, existing := range agents {
+			builtin.RequireParam(rt, existing != agent, "agent %v already authorized by client %v", agent, client)
+		}
+		if len(agents) >= MaxClientAgents {
+			rt.Abortf(exitcode.ErrForbidden, "client %v has already authorized the maximum of %d agents", client, MaxClientAgents)
+		}
+
+		err = msm.storeClientAgents(client, append(agents, agent))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store client agents")
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return nil
+}
+
+// Revokes an agent's authorization to sign deal proposals on behalf of the calling client.
+// Deals already published are unaffected.
+func (a Actor) RevokeClientAgent(rt Runtime, params *ClientAgentParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	client := rt.Caller()
+	agent, ok := rt.ResolveAddress(params.Agent)
+	if !ok {
+		rt.Abortf(exitcode.ErrNotFound, "failed to resolve agent address %v", params.Agent)
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withClientAgents(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		agents, err := msm.loadClientAgents(client)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load client agents")
+		remaining := make([]addr.Address, 0, len(agents))
+		for _, existing := range agents {
+			if existing != agent {
+				remaining = append(remaining, existing)
+			}
+		}
+		if len(remaining) == len(agents) {
+			rt.Abortf(exitcode.ErrNotFound, "agent %v not authorized by client %v", agent, client)
+		}
+
+		err = msm.storeClientAgents(client, remaining)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store client agents")
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return nil
+}
+
+type UpdateDealDurationBoundsParams struct {
+	MinDuration abi.ChainEpoch
+	MaxDuration abi.ChainEpoch
+}
+
+// Sets the bounds (inclusive) on the duration of deals accepted for publication, so that networks other
+// than mainnet may tune them. Deals already published are unaffected.
+// This method may be invoked only by the system actor, on behalf of network governance.
+func (a Actor) UpdateDealDurationBounds(rt Runtime, params *UpdateDealDurationBoundsParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
+	builtin.RequireParam(rt, params.MinDuration > 0, "minimum deal duration %d must be positive", params.MinDuration)
+	builtin.RequireParam(rt, params.MinDuration <= params.MaxDuration, "minimum deal duration %d exceeds maximum %d",
+		params.MinDuration, params.MaxDuration)
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		rt.Log(rtt.INFO, "deal duration bounds changed from [%d, %d] to [%d, %d]",
+			st.DealMinDuration, st.DealMaxDuration, params.MinDuration, params.MaxDuration)
+		st.DealMinDuration = params.MinDuration
+		st.DealMaxDuration = params.MaxDuration
+	})
+	return nil
+}
+
+type SettleDealPaymentsParams struct {
+	Provider addr.Address
+	DealIDs  []abi.DealID
+}
+
+type SettleDealPaymentsReturn struct {
+	// The storage payments transferred from clients to the provider.
+	PaymentTransferred abi.TokenAmount
+}
+
+// Transfers the storage payments accrued up to the current epoch by a set of a provider's active deals from
+// the clients to the provider, without waiting for the deals' next scheduled cron update.
+// Collateral is unlocked and expired or terminated deals removed only by the cron update, as before.
+// This method may be invoked only by the provider's owner or worker, and aborts if any deal has another provider.
+func (a Actor) SettleDealPayments(rt Runtime, params *SettleDealPaymentsParams) *SettleDealPaymentsReturn {
+	provider, _, approvedCallers := escrowAddress(rt, params.Provider)
+	rt.ValidateImmediateCallerIs(approvedCallers...)
+
+	paymentTransferred := big.Zero()
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealProposals(ReadOnlyPermission).
+			withDealStates(WritePermission).withEscrowTable(WritePermission).withLockedTable(WritePermission).
+			withPendingProposals(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for _, dealID := range params.DealIDs {
+			deal, found, err := msm.dealProposals.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal proposal %d", dealID)
+			if !found {
+				rt.Abortf(exitcode.ErrNotFound, "no such deal %d", dealID)
+			}
+			if deal.Provider != provider {
+				rt.Abortf(exitcode.ErrForbidden, "deal %d has provider %v, not %v", dealID, deal.Provider, provider)
+			}
+
+			state, found, err := msm.dealStates.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal state %d", dealID)
+			if !found {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d has not been activated", dealID)
+			}
+
+			payment := msm.processDealPayment(rt, state, deal, rt.CurrEpoch())
+			if payment.IsZero() {
+				continue
+			}
+			paymentTransferred = big.Add(paymentTransferred, payment)
+
+			// As for the deal's first cron update, the pending proposal is removed on first payment.
+			if state.LastUpdatedEpoch == EpochUndefined {
+				dcid, err := deal.Cid()
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate CID for proposal %v", dealID)
+				err = msm.pendingDeals.Delete(abi.CidKey(dcid))
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete pending proposal %v", dcid)
+			}
+			state.LastUpdatedEpoch = rt.CurrEpoch()
+			err = msm.dealStates.Set(dealID, state)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal state %d", dealID)
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return &SettleDealPaymentsReturn{PaymentTransferred: paymentTransferred}
+}
+
 func GenRandNextEpoch(startEpoch abi.ChainEpoch, dealID abi.DealID) abi.ChainEpoch {
 	offset := abi.ChainEpoch(uint64(dealID) % uint64(DealUpdatesInterval))
 	q := builtin.NewQuantSpec(DealUpdatesInterval, 0)
@@ -738,6 +1399,65 @@ func ValidateDealsForActivation(
 	return validateAndComputeDealWeight(proposals, dealIDs, minerAddr, sectorExpiry, currEpoch)
 }
 
+// Validates that deals may be activated by a miner in a sector with the given expiration, and records them
+// as activated at the current epoch. Nothing is recorded if any deal is invalid, in which case the error
+// carries an exit code; an error without an exit code indicates a failure to access state.
+func activateSectorDeals(st *State, store adt.Store, msm *marketStateMutation, dealIDs []abi.DealID,
+	minerAddr addr.Address, sectorExpiry, currEpoch abi.ChainEpoch) error {
+	if _, _, _, err := ValidateDealsForActivation(st, store, dealIDs, minerAddr, sectorExpiry, currEpoch); err != nil {
+		return xerrors.Errorf("failed to validate dealProposals for activation: %w", err)
+	}
+
+	proposals := make([]*DealProposal, len(dealIDs))
+	for i, dealID := range dealIDs {
+		_, found, err := msm.dealStates.Get(dealID)
+		if err != nil {
+			return xerrors.Errorf("failed to get state for dealId %d: %w", dealID, err)
+		}
+		if found {
+			return exitcode.ErrIllegalArgument.Wrapf("deal %d already included in another sector", dealID)
+		}
+
+		proposal, err := getDealProposal(msm.dealProposals, dealID)
+		if err != nil {
+			return xerrors.Errorf("failed to get dealId %d: %w", dealID, err)
+		}
+
+		propc, err := proposal.Cid()
+		if err != nil {
+			return xerrors.Errorf("failed to calculate proposal CID: %w", err)
+		}
+
+		has, err := msm.pendingDeals.Has(abi.CidKey(propc))
+		if err != nil {
+			return xerrors.Errorf("failed to get pending proposal %v: %w", propc, err)
+		}
+		if !has {
+			return exitcode.ErrIllegalState.Wrapf("tried to activate deal that was not in the pending set (%s)", propc)
+		}
+		proposals[i] = proposal
+	}
+
+	for i, dealID := range dealIDs {
+		err := msm.dealStates.Set(dealID, &DealState{
+			SectorStartEpoch: currEpoch,
+			LastUpdatedEpoch: EpochUndefined,
+			SlashEpoch:       EpochUndefined,
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to set deal state %d: %w", dealID, err)
+		}
+
+		// The DataCap consumed by an activated verified deal will never be restored.
+		if proposals[i].VerifiedDeal {
+			if _, _, err = msm.dataCapLedger.TryDelete(abi.UIntKey(uint64(dealID))); err != nil {
+				return xerrors.Errorf("failed to delete datacap ledger entry for deal %d: %w", dealID, err)
+			}
+		}
+	}
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Checks
 ////////////////////////////////////////////////////////////////////////////////
@@ -792,15 +1512,16 @@ func validateDealCanActivate(proposal *DealProposal, minerAddr addr.Address, sec
 	return nil
 }
 
-func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQAPower, baselinePower abi.StoragePower) error {
-	if err := dealProposalIsInternallyValid(rt, deal); err != nil {
+func validateDeal(rt Runtime, deal ClientDealProposal, agents []addr.Address, minDuration, maxDuration abi.ChainEpoch,
+	networkRawPower, networkQAPower, baselinePower abi.StoragePower) error {
+	if err := dealProposalIsInternallyValid(rt, deal, agents); err != nil {
 		return xerrors.Errorf("Invalid deal proposal %w", err)
 	}
 
 	proposal := deal.Proposal
 
-	if proposal.Label.Length() > DealMaxLabelSize {
-		return xerrors.Errorf("deal label can be at most %d bytes, is %d", DealMaxLabelSize, proposal.Label.Length())
+	if err := proposal.Label.Validate(); err != nil {
+		return xerrors.Errorf("proposal label is invalid: %w", err)
 	}
 
 	if err := proposal.PieceSize.Validate(); err != nil {
@@ -823,7 +1544,6 @@ func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQ
 		return xerrors.Errorf("Deal start epoch has already elapsed")
 	}
 
-	minDuration, maxDuration := DealDurationBounds(proposal.PieceSize)
 	if proposal.Duration() < minDuration || proposal.Duration() > maxDuration {
 		return xerrors.Errorf("Deal duration out of bounds")
 	}
@@ -836,12 +1556,14 @@ func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQ
 	minProviderCollateral, maxProviderCollateral := DealProviderCollateralBounds(proposal.PieceSize, proposal.VerifiedDeal,
 		networkRawPower, networkQAPower, baselinePower, rt.TotalFilCircSupply())
 	if proposal.ProviderCollateral.LessThan(minProviderCollateral) || proposal.ProviderCollateral.GreaterThan(maxProviderCollateral) {
-		return xerrors.Errorf("Provider collateral out of bounds")
+		return xerrors.Errorf("Provider collateral %v out of bounds [%v, %v]", proposal.ProviderCollateral,
+			minProviderCollateral, maxProviderCollateral)
 	}
 
 	minClientCollateral, maxClientCollateral := DealClientCollateralBounds(proposal.PieceSize, proposal.Duration())
 	if proposal.ClientCollateral.LessThan(minClientCollateral) || proposal.ClientCollateral.GreaterThan(maxClientCollateral) {
-		return xerrors.Errorf("Client collateral out of bounds")
+		return xerrors.Errorf("Client collateral %v out of bounds [%v, %v]", proposal.ClientCollateral,
+			minClientCollateral, maxClientCollateral)
 	}
 	return nil
 }
@@ -873,6 +1595,33 @@ func escrowAddress(rt Runtime, address addr.Address) (nominal addr.Address, reci
 	return nominal, nominal, []addr.Address{nominal}
 }
 
+// Sends an amount already extracted from a provider's escrow to the provider's owner.
+// If the owner cannot be determined or the send fails, the amount is returned to the provider's escrow
+// rather than failing the cron tick.
+func sendAutoWithdrawal(rt Runtime, provider addr.Address, amount abi.TokenAmount) {
+	var addrs builtin.MinerAddrs
+	code := rt.Send(provider, builtin.MethodsMiner.ControlAddresses, nil, big.Zero(), &addrs)
+	if code.IsSuccess() {
+		code = rt.Send(addrs.Owner, builtin.MethodSend, nil, amount, &builtin.Discard{})
+	}
+	if code.IsSuccess() {
+		return
+	}
+
+	rt.Log(rtt.ERROR, "failed to auto-withdraw %v for provider %v, got code %v, returning funds to escrow", amount, provider, code)
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withEscrowTable(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		err = msm.escrowTable.Add(provider, amount)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to return auto-withdrawal to escrow")
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+}
+
 func getDealProposal(proposals *DealArray, dealID abi.DealID) (*DealProposal, error) {
 	proposal, found, err := proposals.Get(dealID)
 	if err != nil {
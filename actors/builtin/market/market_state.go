@@ -3,6 +3,7 @@ package market
 import (
 	"bytes"
 
+	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
@@ -52,7 +53,12 @@ type State struct {
 
 	// Metadata cached for efficient iteration over deals.
 	DealOpsByEpoch cid.Cid // SetMultimap, HAMT[epoch]Set
-	LastCron       abi.ChainEpoch
+	// Last epoch for which all deal ops have been processed. CronTick processes a bounded number of deal ops,
+	// so this may lag the current epoch. Ops for the following epoch may have been partially processed, in which
+	// case those processed have already been removed from DealOpsByEpoch and are not processed again.
+	LastCron abi.ChainEpoch
+	// Totals of the deal operations processed by the most recent CronTick.
+	LastCronTick CronTickSummary
 
 	// Total Client Collateral that is locked -> unlocked when deal is terminated
 	TotalClientLockedCollateral abi.TokenAmount
@@ -60,6 +66,51 @@ type State struct {
 	TotalProviderLockedCollateral abi.TokenAmount
 	// Total storage fee that is locked in escrow -> unlocked when payments are made
 	TotalClientStorageFee abi.TokenAmount
+
+	// AutoWithdrawDeals tracks deals whose provider requested, at publication, that the deal's
+	// collateral and payment be withdrawn to the provider's owner when the deal completes.
+	AutoWithdrawDeals cid.Cid // Set[DealID]
+
+	// DataCapLedger records the DataCap consumed from the client for each verified deal that has neither
+	// activated nor had its DataCap restored. The amount consumed, as reported by the verified registry,
+	// includes any remainder forfeited by the client when the deal exhausted its DataCap. When a deal times
+	// out, the entry records the amount restored by the verified registry; it is removed if that matches the
+	// amount consumed, and otherwise retained until the client clears it once the discrepancy is reconciled.
+	DataCapLedger cid.Cid // HAMT[DealID]DataCapLedgerEntry
+
+	// ClientAgents records, for each client that has authorized any, the addresses permitted to sign
+	// deal proposals on the client's behalf.
+	ClientAgents cid.Cid // HAMT[addr.Address]ClientAgents
+
+	// Bounds (inclusive) on the duration of deals accepted for publication.
+	// These are initialized to the network defaults and may be changed by governance.
+	DealMinDuration abi.ChainEpoch
+	DealMaxDuration abi.ChainEpoch
+}
+
+type CronTickSummary struct {
+	// The epoch of the CronTick, or EpochUndefined if none has run.
+	Epoch abi.ChainEpoch
+	// The provider collateral slashed from deals that timed out or were terminated, and burnt.
+	AmountSlashed abi.TokenAmount
+	// The storage payments transferred from clients to providers.
+	PaymentTransferred abi.TokenAmount
+	// The number of scheduled deal operations processed.
+	DealsProcessed uint64
+}
+
+type ClientAgents struct {
+	// ID addresses authorized to sign deal proposals for the client, in order of authorization.
+	Agents []addr.Address
+}
+
+type DataCapLedgerEntry struct {
+	Client   addr.Address
+	Consumed abi.StoragePower
+	Restored abi.StoragePower
+	// The epoch at which the deal timed out and restoration was requested, or EpochUndefined while
+	// the deal may still activate.
+	RestoreEpoch abi.ChainEpoch
 }
 
 func ConstructState(store adt.Store) (*State, error) {
@@ -72,7 +123,7 @@ func ConstructState(store adt.Store) (*State, error) {
 		return nil, xerrors.Errorf("failed to create empty states array: %w", err)
 	}
 
-	emptyPendingProposalsMapCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
+	emptyMapCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to create empty map: %w", err)
 	}
@@ -88,16 +139,26 @@ func ConstructState(store adt.Store) (*State, error) {
 	return &State{
 		Proposals:        emptyProposalsArrayCid,
 		States:           emptyStatesArrayCid,
-		PendingProposals: emptyPendingProposalsMapCid,
+		PendingProposals: emptyMapCid,
 		EscrowTable:      emptyBalanceTableCid,
 		LockedTable:      emptyBalanceTableCid,
 		NextID:           abi.DealID(0),
 		DealOpsByEpoch:   emptyDealOpsHamtCid,
 		LastCron:         abi.ChainEpoch(-1),
+		LastCronTick: CronTickSummary{
+			Epoch:              EpochUndefined,
+			AmountSlashed:      abi.NewTokenAmount(0),
+			PaymentTransferred: abi.NewTokenAmount(0),
+		},
 
 		TotalClientLockedCollateral:   abi.NewTokenAmount(0),
 		TotalProviderLockedCollateral: abi.NewTokenAmount(0),
 		TotalClientStorageFee:         abi.NewTokenAmount(0),
+		AutoWithdrawDeals:             emptyMapCid,
+		DataCapLedger:                 emptyMapCid,
+		ClientAgents:                  emptyMapCid,
+		DealMinDuration:               DealMinDuration,
+		DealMaxDuration:               DealMaxDuration,
 	}, nil
 }
 
@@ -105,8 +166,9 @@ func ConstructState(store adt.Store) (*State, error) {
 // Deal state operations
 ////////////////////////////////////////////////////////////////////////////////
 
-func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealState, deal *DealProposal, epoch abi.ChainEpoch) (amountSlashed abi.TokenAmount, nextEpoch abi.ChainEpoch, removeDeal bool) {
+func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealState, deal *DealProposal, epoch abi.ChainEpoch) (amountSlashed, payment abi.TokenAmount, nextEpoch abi.ChainEpoch, removeDeal bool) {
 	amountSlashed = abi.NewTokenAmount(0)
+	payment = abi.NewTokenAmount(0)
 
 	everUpdated := state.LastUpdatedEpoch != EpochUndefined
 	everSlashed := state.SlashEpoch != EpochUndefined
@@ -116,36 +178,10 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 	// This would be the case that the first callback somehow triggers before it is scheduled to
 	// This is expected not to be able to happen
 	if deal.StartEpoch > epoch {
-		return amountSlashed, EpochUndefined, false
-	}
-
-	paymentEndEpoch := deal.EndEpoch
-	if everSlashed {
-		builtin.RequireState(rt, epoch >= state.SlashEpoch, "current epoch less than deal slash epoch %d", state.SlashEpoch)
-		builtin.RequireState(rt, state.SlashEpoch <= deal.EndEpoch, "deal slash epoch %d after deal end %d", state.SlashEpoch, deal.EndEpoch)
-		paymentEndEpoch = state.SlashEpoch
-	} else if epoch < paymentEndEpoch {
-		paymentEndEpoch = epoch
+		return amountSlashed, payment, EpochUndefined, false
 	}
 
-	paymentStartEpoch := deal.StartEpoch
-	if everUpdated && state.LastUpdatedEpoch > paymentStartEpoch {
-		paymentStartEpoch = state.LastUpdatedEpoch
-	}
-
-	numEpochsElapsed := paymentEndEpoch - paymentStartEpoch
-
-	{
-		// Process deal payment for the elapsed epochs.
-		totalPayment := big.Mul(big.NewInt(int64(numEpochsElapsed)), deal.StoragePricePerEpoch)
-
-		// the transfer amount can be less than or equal to zero if a deal is slashed before or at the deal's start epoch.
-		if totalPayment.GreaterThan(big.Zero()) {
-			err := m.transferBalance(deal.Client, deal.Provider, totalPayment)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to transfer %v from %v to %v",
-				totalPayment, deal.Client, deal.Provider)
-		}
-	}
+	payment = m.processDealPayment(rt, state, deal, epoch)
 
 	if everSlashed {
 		// unlock client collateral and locked storage fee
@@ -164,19 +200,49 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 		amountSlashed = deal.ProviderCollateral
 		err = m.slashBalance(deal.Provider, amountSlashed, ProviderCollateral)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "slashing balance")
-		return amountSlashed, EpochUndefined, true
+		return amountSlashed, payment, EpochUndefined, true
 	}
 
 	if epoch >= deal.EndEpoch {
 		m.processDealExpired(rt, deal, state)
-		return amountSlashed, EpochUndefined, true
+		return amountSlashed, payment, EpochUndefined, true
 	}
 
 	// We're explicitly not inspecting the end epoch and may process a deal's expiration late, in order to prevent an outsider
 	// from loading a cron tick by activating too many deals with the same end epoch.
 	nextEpoch = epoch + DealUpdatesInterval
 
-	return amountSlashed, nextEpoch, false
+	return amountSlashed, payment, nextEpoch, false
+}
+
+// Transfers the storage payment for the epochs of a deal elapsed since its start or last update, up to the
+// earliest of the given epoch, the deal's slash epoch and its end epoch. Returns the amount transferred.
+func (m *marketStateMutation) processDealPayment(rt Runtime, state *DealState, deal *DealProposal, epoch abi.ChainEpoch) abi.TokenAmount {
+	paymentEndEpoch := deal.EndEpoch
+	if state.SlashEpoch != EpochUndefined {
+		builtin.RequireState(rt, epoch >= state.SlashEpoch, "current epoch less than deal slash epoch %d", state.SlashEpoch)
+		builtin.RequireState(rt, state.SlashEpoch <= deal.EndEpoch, "deal slash epoch %d after deal end %d", state.SlashEpoch, deal.EndEpoch)
+		paymentEndEpoch = state.SlashEpoch
+	} else if epoch < paymentEndEpoch {
+		paymentEndEpoch = epoch
+	}
+
+	paymentStartEpoch := deal.StartEpoch
+	if state.LastUpdatedEpoch != EpochUndefined && state.LastUpdatedEpoch > paymentStartEpoch {
+		paymentStartEpoch = state.LastUpdatedEpoch
+	}
+
+	numEpochsElapsed := paymentEndEpoch - paymentStartEpoch
+	totalPayment := big.Mul(big.NewInt(int64(numEpochsElapsed)), deal.StoragePricePerEpoch)
+
+	// the transfer amount can be less than or equal to zero if a deal is slashed before or at the deal's start epoch.
+	if totalPayment.LessThanEqual(big.Zero()) {
+		return big.Zero()
+	}
+	err := m.transferBalance(deal.Client, deal.Provider, totalPayment)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to transfer %v from %v to %v",
+		totalPayment, deal.Client, deal.Provider)
+	return totalPayment
 }
 
 // Deal start deadline elapsed without appearing in a proven sector.
@@ -216,6 +282,72 @@ func (m *marketStateMutation) processDealExpired(rt Runtime, deal *DealProposal,
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed unlocking deal client balance")
 }
 
+// Extracts the collateral and payment of a completed deal from the provider's escrow, if the provider
+// requested auto-withdrawal of the deal. The amount is limited to the provider's unlocked balance, since
+// payments may have been withdrawn explicitly in the meantime.
+// Returns the amount extracted, to be sent to the provider's owner.
+func (m *marketStateMutation) processDealAutoWithdraw(dealID abi.DealID, deal *DealProposal) (abi.TokenAmount, error) {
+	found, err := m.autoWithdrawDeals.TryDelete(abi.UIntKey(uint64(dealID)))
+	if err != nil {
+		return big.Zero(), xerrors.Errorf("failed to delete auto-withdraw deal %d: %w", dealID, err)
+	}
+	if !found {
+		return big.Zero(), nil
+	}
+
+	locked, err := m.lockedTable.Get(deal.Provider)
+	if err != nil {
+		return big.Zero(), xerrors.Errorf("failed to get locked balance for %v: %w", deal.Provider, err)
+	}
+	return m.escrowTable.SubtractWithMinimum(deal.Provider, big.Add(deal.ProviderCollateral, deal.TotalStorageFee()), locked)
+}
+
+// Records the outcome of restoring a timed-out verified deal's DataCap, removing the ledger entry if the
+// amount restored matches the amount consumed. Deals with no ledger entry are ignored.
+func (m *marketStateMutation) recordDataCapRestored(dealID abi.DealID, restored abi.StoragePower, epoch abi.ChainEpoch) error {
+	raw, found, err := m.dataCapLedger.TryDelete(abi.UIntKey(uint64(dealID)))
+	if err != nil {
+		return xerrors.Errorf("failed to delete datacap ledger entry for deal %d: %w", dealID, err)
+	}
+	if !found {
+		return nil
+	}
+	var entry DataCapLedgerEntry
+	if err := entry.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+		return xerrors.Errorf("failed to decode datacap ledger entry for deal %d: %w", dealID, err)
+	}
+	if restored.Equals(entry.Consumed) {
+		return nil
+	}
+	// The discrepancy is retained until the client clears it.
+	entry.Restored = restored
+	entry.RestoreEpoch = epoch
+	return m.dataCapLedger.Put(abi.UIntKey(uint64(dealID)), &entry)
+}
+
+// Returns the agents authorized to sign deal proposals for a client, which may be empty.
+func (m *marketStateMutation) loadClientAgents(client addr.Address) ([]addr.Address, error) {
+	var agents ClientAgents
+	if _, err := m.clientAgents.Get(abi.AddrKey(client), &agents); err != nil {
+		return nil, xerrors.Errorf("failed to load agents for client %v: %w", client, err)
+	}
+	return agents.Agents, nil
+}
+
+// Records the agents authorized to sign deal proposals for a client, removing the entry if there are none.
+func (m *marketStateMutation) storeClientAgents(client addr.Address, agents []addr.Address) error {
+	if len(agents) == 0 {
+		if _, _, err := m.clientAgents.TryDelete(abi.AddrKey(client)); err != nil {
+			return xerrors.Errorf("failed to delete agents for client %v: %w", client, err)
+		}
+		return nil
+	}
+	if err := m.clientAgents.Put(abi.AddrKey(client), &ClientAgents{Agents: agents}); err != nil {
+		return xerrors.Errorf("failed to store agents for client %v: %w", client, err)
+	}
+	return nil
+}
+
 func (m *marketStateMutation) generateStorageDealID() abi.DealID {
 	ret := m.nextDealId
 	m.nextDealId = m.nextDealId + abi.DealID(1)
@@ -226,7 +358,8 @@ func (m *marketStateMutation) generateStorageDealID() abi.DealID {
 // State utility functions
 ////////////////////////////////////////////////////////////////////////////////
 
-func dealProposalIsInternallyValid(rt Runtime, proposal ClientDealProposal) error {
+// The proposal must be signed by the client, or by one of the agents the client has authorized.
+func dealProposalIsInternallyValid(rt Runtime, proposal ClientDealProposal, agents []addr.Address) error {
 	// Note: we do not verify the provider signature here, since this is implicit in the
 	// authenticity of the on-chain message publishing the deal.
 	buf := bytes.Buffer{}
@@ -235,10 +368,15 @@ func dealProposalIsInternallyValid(rt Runtime, proposal ClientDealProposal) erro
 		return xerrors.Errorf("proposal signature verification failed to marshal proposal: %w", err)
 	}
 	err = rt.VerifySignature(proposal.ClientSignature, proposal.Proposal.Client, buf.Bytes())
-	if err != nil {
-		return xerrors.Errorf("signature proposal invalid: %w", err)
+	if err == nil {
+		return nil
 	}
-	return nil
+	for _, agent := range agents {
+		if rt.VerifySignature(proposal.ClientSignature, agent, buf.Bytes()) == nil {
+			return nil
+		}
+	}
+	return xerrors.Errorf("signature proposal invalid: %w", err)
 }
 
 func dealGetPaymentRemaining(deal *DealProposal, slashEpoch abi.ChainEpoch) (abi.TokenAmount, error) {
@@ -290,6 +428,15 @@ type marketStateMutation struct {
 	dpePermit    MarketStateMutationPermission
 	dealsByEpoch *SetMultimap
 
+	autoWithdrawPermit MarketStateMutationPermission
+	autoWithdrawDeals  *adt.Set
+
+	dataCapLedgerPermit MarketStateMutationPermission
+	dataCapLedger       *adt.Map
+
+	clientAgentsPermit MarketStateMutationPermission
+	clientAgents       *adt.Map
+
 	lockedPermit                  MarketStateMutationPermission
 	lockedTable                   *adt.BalanceTable
 	totalClientLockedCollateral   abi.TokenAmount
@@ -355,6 +502,30 @@ func (m *marketStateMutation) build() (*marketStateMutation, error) {
 		m.dealsByEpoch = dbe
 	}
 
+	if m.autoWithdrawPermit != Invalid {
+		aw, err := adt.AsSet(m.store, m.st.AutoWithdrawDeals, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load auto-withdraw deals: %w", err)
+		}
+		m.autoWithdrawDeals = aw
+	}
+
+	if m.dataCapLedgerPermit != Invalid {
+		ledger, err := adt.AsMap(m.store, m.st.DataCapLedger, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load datacap ledger: %w", err)
+		}
+		m.dataCapLedger = ledger
+	}
+
+	if m.clientAgentsPermit != Invalid {
+		agents, err := adt.AsMap(m.store, m.st.ClientAgents, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load client agents: %w", err)
+		}
+		m.clientAgents = agents
+	}
+
 	m.nextDealId = m.st.NextID
 
 	return m, nil
@@ -390,6 +561,21 @@ func (m *marketStateMutation) withDealsByEpoch(permit MarketStateMutationPermiss
 	return m
 }
 
+func (m *marketStateMutation) withAutoWithdrawDeals(permit MarketStateMutationPermission) *marketStateMutation {
+	m.autoWithdrawPermit = permit
+	return m
+}
+
+func (m *marketStateMutation) withDataCapLedger(permit MarketStateMutationPermission) *marketStateMutation {
+	m.dataCapLedgerPermit = permit
+	return m
+}
+
+func (m *marketStateMutation) withClientAgents(permit MarketStateMutationPermission) *marketStateMutation {
+	m.clientAgentsPermit = permit
+	return m
+}
+
 func (m *marketStateMutation) commitState() error {
 	var err error
 	if m.proposalPermit == WritePermission {
@@ -431,6 +617,24 @@ func (m *marketStateMutation) commitState() error {
 		}
 	}
 
+	if m.autoWithdrawPermit == WritePermission {
+		if m.st.AutoWithdrawDeals, err = m.autoWithdrawDeals.Root(); err != nil {
+			return xerrors.Errorf("failed to flush auto-withdraw deals: %w", err)
+		}
+	}
+
+	if m.dataCapLedgerPermit == WritePermission {
+		if m.st.DataCapLedger, err = m.dataCapLedger.Root(); err != nil {
+			return xerrors.Errorf("failed to flush datacap ledger: %w", err)
+		}
+	}
+
+	if m.clientAgentsPermit == WritePermission {
+		if m.st.ClientAgents, err = m.clientAgents.Root(); err != nil {
+			return xerrors.Errorf("failed to flush client agents: %w", err)
+		}
+	}
+
 	m.st.NextID = m.nextDealId
 	return nil
 }
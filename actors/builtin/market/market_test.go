@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	address "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/cbor"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
 )
 
 func mustCbor(o cbor.Marshaler) []byte {
@@ -108,6 +110,11 @@ func TestMarketActor(t *testing.T) {
 		assert.Equal(t, abi.DealID(0), state.NextID)
 		assert.Equal(t, emptyMultiMap, state.DealOpsByEpoch)
 		assert.Equal(t, abi.ChainEpoch(-1), state.LastCron)
+		assert.Equal(t, emptyMap, state.AutoWithdrawDeals)
+		assert.Equal(t, emptyMap, state.DataCapLedger)
+		assert.Equal(t, emptyMap, state.ClientAgents)
+		assert.Equal(t, market.DealMinDuration, state.DealMinDuration)
+		assert.Equal(t, market.DealMaxDuration, state.DealMaxDuration)
 	})
 
 	t.Run("AddBalance", func(t *testing.T) {
@@ -580,7 +587,8 @@ func TestPublishStorageDeals(t *testing.T) {
 			Address:  clientResolved,
 			DealSize: big.NewIntUnsigned(uint64(deal.PieceSize)),
 		}
-		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.UseBytes, param, abi.NewTokenAmount(0), nil, exitcode.Ok)
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.UseBytes, param, abi.NewTokenAmount(0),
+			&verifreg.UseBytesReturn{RemainingCap: big.Zero(), Consumed: param.DealSize}, exitcode.Ok)
 
 		deal2 := deal
 		deal2.Client = clientResolved
@@ -886,6 +894,32 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 				},
 				exitCode: exitcode.ErrIllegalArgument,
 			},
+			"label exceeding max size": {
+				setup: func(rt *mock.Runtime, a *marketActorTestHarness, d *market.DealProposal) {
+					a.addParticipantFunds(rt, client, d.ClientBalanceRequirement())
+					a.addProviderFunds(rt, d.ProviderCollateral, mAddrs)
+					// Decoding does not bound the label's size, so an oversized label can reach the actor.
+					buf := bytes.Buffer{}
+					require.NoError(t, cbg.WriteMajorTypeHeader(&buf, cbg.MajByteString, market.DealMaxLabelSize+1))
+					buf.Write(make([]byte, market.DealMaxLabelSize+1))
+					require.NoError(t, d.Label.UnmarshalCBOR(&buf))
+				},
+				exitCode: exitcode.ErrIllegalArgument,
+			},
+			"label with invalid utf8": {
+				setup: func(rt *mock.Runtime, a *marketActorTestHarness, d *market.DealProposal) {
+					a.addParticipantFunds(rt, client, d.ClientBalanceRequirement())
+					a.addProviderFunds(rt, d.ProviderCollateral, mAddrs)
+					// Decoding rejects the string but leaves it in the label, as if the label had reached the actor.
+					invalid := []byte{0xff, 0xfe}
+					buf := bytes.Buffer{}
+					require.NoError(t, cbg.WriteMajorTypeHeader(&buf, cbg.MajTextString, uint64(len(invalid))))
+					buf.Write(invalid)
+					require.Error(t, d.Label.UnmarshalCBOR(&buf))
+					require.True(t, d.Label.IsString())
+				},
+				exitCode: exitcode.ErrIllegalArgument,
+			},
 			"piece size is not a power of 2": {
 				setup: func(_ *mock.Runtime, _ *marketActorTestHarness, d *market.DealProposal) {
 					d.PieceSize = abi.PaddedPieceSize(254)
@@ -1259,6 +1293,105 @@ func TestActivateDealFailures(t *testing.T) {
 
 }
 
+func TestBatchActivateDeals(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(10)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := endEpoch + 100
+
+	t.Run("activates valid sectors and skips invalid ones", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		dealId2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+		dealId3 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+2)
+		dealId4 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+3)
+		dealId5 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+4)
+		dealId6 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+5)
+		otherProvider := tutil.NewIDAddr(t, 401)
+		otherDeal := actor.generateAndPublishDeal(rt, client, &minerAddrs{owner, worker, otherProvider, nil}, startEpoch, endEpoch)
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealId1)
+
+		ret := actor.batchActivateDeals(rt, provider,
+			// valid
+			market.SectorDeals{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId2, dealId3}},
+			// deal already activated
+			market.SectorDeals{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId4, dealId1}},
+			// deal of another provider
+			market.SectorDeals{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{otherDeal}},
+			// deal ends after the sector expires
+			market.SectorDeals{SectorExpiry: endEpoch, DealIDs: []abi.DealID{dealId5}},
+			// valid, with no deals
+			market.SectorDeals{SectorExpiry: sectorExpiry},
+			// valid
+			market.SectorDeals{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId6}},
+			// deal activated by an earlier sector in the batch
+			market.SectorDeals{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId6}},
+			// deal does not exist
+			market.SectorDeals{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{abi.DealID(42)}},
+		)
+		activated, err := ret.Activated.All(market.BatchActivateDealsMaxSectors)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{0, 4, 5}, activated)
+
+		for _, d := range []abi.DealID{dealId1, dealId2, dealId3, dealId6} {
+			assert.EqualValues(t, currentEpoch, actor.getDealState(rt, d).SectorStartEpoch)
+		}
+		actor.assertDealsNotActivated(rt, currentEpoch, dealId4, dealId5, otherDeal)
+		actor.checkState(rt)
+	})
+
+	t.Run("aborts when a deal is missing from the pending set", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		dealId2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+		actor.deletePendingProposal(rt, dealId2)
+
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		params := &market.BatchActivateDealsParams{Sectors: []market.SectorDeals{
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId1}},
+			{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId2}},
+		}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalState, "not in the pending set", func() {
+			rt.Call(actor.BatchActivateDeals, params)
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails when caller is not a StorageMinerActor", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.SetCaller(provider, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.BatchActivateDeals, &market.BatchActivateDealsParams{})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails with too many sectors", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		params := &market.BatchActivateDealsParams{Sectors: make([]market.SectorDeals, market.BatchActivateDealsMaxSectors+1)}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too many sectors", func() {
+			rt.Call(actor.BatchActivateDeals, params)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
 func TestOnMinerSectorsTerminate(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -1847,6 +1980,10 @@ func TestCronTickTimedoutDeals(t *testing.T) {
 		rt.SetEpoch(processEpoch(t, dealId, startEpoch))
 		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, d.ProviderCollateral, nil, exitcode.Ok)
 		actor.cronTick(rt)
+		ret := actor.lastCronTick(rt)
+		assert.Equal(t, d.ProviderCollateral, ret.AmountSlashed)
+		assert.Equal(t, big.Zero(), ret.PaymentTransferred)
+		assert.Equal(t, uint64(1), ret.DealsProcessed)
 
 		require.Equal(t, cEscrow, actor.getEscrowBalance(rt, client))
 		require.Equal(t, big.Zero(), actor.getLockedBalance(rt, client))
@@ -1899,8 +2036,8 @@ func TestCronTickTimedoutDeals(t *testing.T) {
 
 		//  publishing verified deals
 		rt.SetCaller(worker, builtin.AccountActorCodeID)
-		dealIds := actor.publishDeals(rt, mAddrs, publishDealReq{deal1},
-			publishDealReq{deal2}, publishDealReq{deal3})
+		dealIds := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal1},
+			publishDealReq{deal: deal2}, publishDealReq{deal: deal3})
 
 		// do a cron tick for it -> all should time out and get slashed
 		// ONLY deal1 and deal2 should be sent to the Registry actor
@@ -1917,9 +2054,9 @@ func TestCronTickTimedoutDeals(t *testing.T) {
 		}
 
 		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, param1,
-			abi.NewTokenAmount(0), nil, exitcode.Ok)
+			abi.NewTokenAmount(0), &verifreg.RestoreBytesReturn{Restored: param1.DealSize}, exitcode.Ok)
 		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, param2,
-			abi.NewTokenAmount(0), nil, exitcode.Ok)
+			abi.NewTokenAmount(0), &verifreg.RestoreBytesReturn{Restored: param2.DealSize}, exitcode.Ok)
 
 		expectedBurn := big.Mul(big.NewInt(3), deal1.ProviderCollateral)
 		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedBurn, nil, exitcode.Ok)
@@ -1936,6 +2073,134 @@ func TestCronTickTimedoutDeals(t *testing.T) {
 	})
 }
 
+func TestLastCronTick(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+
+	rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+	assert.Equal(t, market.EpochUndefined, actor.lastCronTick(rt).Epoch)
+
+	activeID := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+	active := actor.getDealProposal(rt, activeID)
+	timedOutID := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+	timedOut := actor.getDealProposal(rt, timedOutID)
+
+	// Tick once both deals are due, so the active deal is paid and the unactivated one times out.
+	epoch := processEpoch(t, activeID, startEpoch)
+	if e := processEpoch(t, timedOutID, startEpoch); e > epoch {
+		epoch = e
+	}
+	rt.SetEpoch(epoch)
+	cEscrow := actor.getEscrowBalance(rt, client)
+	pEscrow := actor.getEscrowBalance(rt, provider)
+
+	rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, timedOut.ProviderCollateral, nil, exitcode.Ok)
+	actor.cronTick(rt)
+	ret := actor.lastCronTick(rt)
+	assert.Equal(t, epoch, ret.Epoch)
+	assert.Equal(t, uint64(2), ret.DealsProcessed)
+	assert.Equal(t, timedOut.ProviderCollateral, ret.AmountSlashed)
+	assert.Equal(t, big.Mul(big.NewInt(int64(epoch-startEpoch)), active.StoragePricePerEpoch), ret.PaymentTransferred)
+	assert.True(t, ret.PaymentTransferred.GreaterThan(big.Zero()))
+
+	// The reported totals account for the escrow balance changes.
+	assert.Equal(t, big.Sub(cEscrow, ret.PaymentTransferred), actor.getEscrowBalance(rt, client))
+	assert.Equal(t, big.Sub(big.Add(pEscrow, ret.PaymentTransferred), ret.AmountSlashed), actor.getEscrowBalance(rt, provider))
+	actor.checkState(rt)
+}
+
+func TestCronTickBoundedProcessing(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+
+	t.Run("backlog of deal ops settles across multiple ticks", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+
+		// Publish more deals than a single tick processes, none of which are activated.
+		dealCount := market.MaxDealOpsPerCronTick + 3
+		var deals []market.DealProposal
+		clientFunds := big.Zero()
+		for i := 0; i < dealCount; i++ {
+			deal := generateDealProposal(client, provider, startEpoch, endEpoch+abi.ChainEpoch(i))
+			deals = append(deals, deal)
+			clientFunds = big.Add(clientFunds, deal.ClientBalanceRequirement())
+		}
+		collateral := deals[0].ProviderCollateral
+		actor.addProviderFunds(rt, big.Mul(collateral, big.NewInt(int64(dealCount))), mAddrs)
+		actor.addParticipantFunds(rt, client, clientFunds)
+
+		var dealIds []abi.DealID
+		batchSize := market.MaxDealsPerProviderPerPublish
+		for i := 0; i < dealCount; i += batchSize {
+			var reqs []publishDealReq
+			for j := i; j < i+batchSize && j < dealCount; j++ {
+				reqs = append(reqs, publishDealReq{deal: deals[j]})
+			}
+			rt.SetCaller(worker, builtin.AccountActorCodeID)
+			dealIds = append(dealIds, actor.publishDeals(rt, mAddrs, reqs...)...)
+		}
+
+		// All deals time out by the end of the first update interval.
+		current := startEpoch + market.DealUpdatesInterval
+		rt.SetEpoch(current)
+
+		lastCron := func() abi.ChainEpoch {
+			var st market.State
+			rt.GetState(&st)
+			return st.LastCron
+		}
+		remaining := func() int {
+			var st market.State
+			rt.GetState(&st)
+			proposals, err := market.AsDealProposalArray(adt.AsStore(rt), st.Proposals)
+			require.NoError(t, err)
+			count := 0
+			for _, id := range dealIds {
+				_, found, err := proposals.Get(id)
+				require.NoError(t, err)
+				if found {
+					count++
+				}
+			}
+			return count
+		}
+
+		// The first tick stops once the limit is reached, part-way through the scheduled epochs.
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil,
+			big.Mul(collateral, big.NewInt(market.MaxDealOpsPerCronTick)), nil, exitcode.Ok)
+		actor.cronTick(rt)
+		ret := actor.lastCronTick(rt)
+		assert.Equal(t, uint64(market.MaxDealOpsPerCronTick), ret.DealsProcessed)
+		assert.Equal(t, 3, remaining())
+		assert.Less(t, int64(lastCron()), int64(current))
+		actor.checkState(rt)
+
+		// The next tick resumes where the first stopped, processing each remaining deal once.
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, big.Mul(collateral, big.NewInt(3)), nil, exitcode.Ok)
+		actor.cronTick(rt)
+		ret = actor.lastCronTick(rt)
+		assert.Equal(t, uint64(3), ret.DealsProcessed)
+		assert.Equal(t, 0, remaining())
+		assert.Equal(t, current, lastCron())
+		actor.checkState(rt)
+
+		// Nothing remains to be processed.
+		actor.cronTickNoChange(rt, client, provider)
+	})
+}
+
 func TestCronTickDealExpiry(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -2090,6 +2355,179 @@ func TestCronTickDealExpiry(t *testing.T) {
 	})
 }
 
+func TestCronTickDealAutoWithdraw(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 400
+
+	publishAndActivate := func(rt *mock.Runtime, actor *marketActorTestHarness, autoWithdraw bool) abi.DealID {
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		rt.SetCaller(mAddrs.worker, builtin.AccountActorCodeID)
+		dealIds := actor.publishDealsWithOptions(rt, mAddrs, &market.PublishStorageDealsWithOptionsParams{AutoWithdraw: autoWithdraw},
+			publishDealReq{deal: deal})
+		actor.activateDeals(rt, sectorExpiry, provider, 0, dealIds[0])
+		return dealIds[0]
+	}
+
+	t.Run("completed deal without auto-withdraw leaves funds in provider escrow", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := publishAndActivate(rt, actor, false)
+		deal := actor.getDealProposal(rt, dealId)
+		pEscrow := actor.getEscrowBalance(rt, provider)
+
+		rt.SetEpoch(endEpoch + 100)
+		actor.cronTick(rt)
+
+		assert.Equal(t, big.Add(pEscrow, deal.TotalStorageFee()), actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, provider))
+		actor.assertDealDeleted(rt, dealId, deal)
+		actor.checkState(rt)
+	})
+
+	t.Run("completed deal with auto-withdraw sends collateral and payment to owner", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := publishAndActivate(rt, actor, true)
+		deal := actor.getDealProposal(rt, dealId)
+		pEscrow := actor.getEscrowBalance(rt, provider)
+
+		rt.SetEpoch(endEpoch + 100)
+		withdrawn := big.Add(deal.ProviderCollateral, deal.TotalStorageFee())
+		expectGetControlAddresses(rt, provider, owner, worker)
+		rt.ExpectSend(owner, builtin.MethodSend, nil, withdrawn, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		rt.SetBalance(big.Sub(rt.Balance(), withdrawn))
+
+		assert.True(t, big.Sub(pEscrow, deal.ProviderCollateral).Equals(actor.getEscrowBalance(rt, provider)))
+		actor.assertDealDeleted(rt, dealId, deal)
+		actor.checkState(rt)
+	})
+
+	t.Run("only deals published with auto-withdraw are withdrawn", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		autoDealId := publishAndActivate(rt, actor, true)
+		autoDeal := actor.getDealProposal(rt, autoDealId)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+1)
+		rt.SetCaller(mAddrs.worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+		actor.activateDeals(rt, sectorExpiry, provider, 0, dealId)
+		pEscrow := actor.getEscrowBalance(rt, provider)
+
+		rt.SetEpoch(endEpoch + 100)
+		withdrawn := big.Add(autoDeal.ProviderCollateral, autoDeal.TotalStorageFee())
+		expectGetControlAddresses(rt, provider, owner, worker)
+		rt.ExpectSend(owner, builtin.MethodSend, nil, withdrawn, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		rt.SetBalance(big.Sub(rt.Balance(), withdrawn))
+
+		// The deal published without auto-withdraw leaves its payment in escrow.
+		expected := big.Sub(big.Add(pEscrow, deal.TotalStorageFee()), autoDeal.ProviderCollateral)
+		assert.Equal(t, expected, actor.getEscrowBalance(rt, provider))
+		actor.assertDealDeleted(rt, autoDealId, autoDeal)
+		actor.assertDealDeleted(rt, dealId, &deal)
+		actor.checkState(rt)
+	})
+
+	t.Run("auto-withdraw of a timed out deal is cleared", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		rt.SetCaller(mAddrs.worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDealsWithOptions(rt, mAddrs, &market.PublishStorageDealsWithOptionsParams{AutoWithdraw: true},
+			publishDealReq{deal: deal})[0]
+
+		rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		expectedBurn := market.CollateralPenaltyForDealActivationMissed(deal.ProviderCollateral)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedBurn, nil, exitcode.Ok)
+		actor.cronTick(rt)
+
+		actor.assertDealDeleted(rt, dealId, &deal)
+		var st market.State
+		rt.GetState(&st)
+		autoWithdrawDeals, err := adt.AsSet(adt.AsStore(rt), st.AutoWithdrawDeals, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		found, err := autoWithdrawDeals.Has(abi.UIntKey(uint64(dealId)))
+		require.NoError(t, err)
+		assert.False(t, found)
+		actor.checkState(rt)
+	})
+
+	t.Run("auto-withdraw is limited to funds remaining in escrow", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := publishAndActivate(rt, actor, true)
+		deal := actor.getDealProposal(rt, dealId)
+
+		// Payment is made part way through the deal, and then withdrawn by the provider.
+		current := rt.SetEpoch(processEpoch(t, dealId, startEpoch) + market.DealUpdatesInterval)
+		actor.cronTick(rt)
+		paid := big.Mul(big.NewInt(int64(current-startEpoch)), deal.StoragePricePerEpoch)
+		actor.withdrawProviderBalance(rt, paid, paid, mAddrs)
+		rt.SetBalance(big.Sub(rt.Balance(), paid))
+
+		rt.SetEpoch(endEpoch + 100)
+		withdrawn := big.Sub(big.Add(deal.ProviderCollateral, deal.TotalStorageFee()), paid)
+		expectGetControlAddresses(rt, provider, owner, worker)
+		rt.ExpectSend(owner, builtin.MethodSend, nil, withdrawn, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		rt.SetBalance(big.Sub(rt.Balance(), withdrawn))
+
+		assert.True(t, big.Zero().Equals(actor.getEscrowBalance(rt, provider)))
+		actor.checkState(rt)
+	})
+
+	t.Run("failed auto-withdraw returns funds to escrow", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := publishAndActivate(rt, actor, true)
+		deal := actor.getDealProposal(rt, dealId)
+		pEscrow := actor.getEscrowBalance(rt, provider)
+
+		rt.SetEpoch(endEpoch + 100)
+		withdrawn := big.Add(deal.ProviderCollateral, deal.TotalStorageFee())
+		expectGetControlAddresses(rt, provider, owner, worker)
+		rt.ExpectSend(owner, builtin.MethodSend, nil, withdrawn, nil, exitcode.ErrForbidden)
+		actor.cronTick(rt)
+
+		assert.Equal(t, big.Add(pEscrow, deal.TotalStorageFee()), actor.getEscrowBalance(rt, provider))
+		actor.assertDealDeleted(rt, dealId, deal)
+		actor.checkState(rt)
+	})
+
+	t.Run("failed control addresses lookup returns funds to escrow without aborting cron", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := publishAndActivate(rt, actor, true)
+		deal := actor.getDealProposal(rt, dealId)
+		pEscrow := actor.getEscrowBalance(rt, provider)
+
+		rt.SetEpoch(endEpoch + 100)
+		rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, big.Zero(), &miner.GetControlAddressesReturn{Owner: owner, Worker: worker}, exitcode.ErrForbidden)
+		actor.cronTick(rt)
+
+		assert.Equal(t, big.Add(pEscrow, deal.TotalStorageFee()), actor.getEscrowBalance(rt, provider))
+		actor.assertDealDeleted(rt, dealId, deal)
+		actor.checkState(rt)
+	})
+
+	t.Run("slashed deal is not auto-withdrawn", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := publishAndActivate(rt, actor, true)
+		deal := actor.getDealProposal(rt, dealId)
+
+		rt.SetEpoch(startEpoch + 10)
+		actor.terminateDeals(rt, provider, dealId)
+		rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, deal.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		rt.SetBalance(big.Sub(rt.Balance(), deal.ProviderCollateral))
+
+		actor.assertDealDeleted(rt, dealId, deal)
+		actor.checkState(rt)
+	})
+}
+
 func TestCronTickDealSlashing(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -2426,26 +2864,115 @@ func TestMarketActorDeals(t *testing.T) {
 	actor.checkState(rt)
 }
 
-func TestMaxDealLabelSize(t *testing.T) {
+func TestPublishStorageDealsProviderCap(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
 	worker := tutil.NewIDAddr(t, 103)
 	client := tutil.NewIDAddr(t, 104)
-	minerAddrs := &minerAddrs{owner, worker, provider, nil}
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+	startEpoch := abi.ChainEpoch(10)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
 
-	var st market.State
+	// Generates distinct deals and adds the funds to publish them.
+	generateDeals := func(rt *mock.Runtime, actor *marketActorTestHarness, count int) []market.DealProposal {
+		var deals []market.DealProposal
+		clientFunds := big.Zero()
+		providerFunds := big.Zero()
+		for i := 0; i < count; i++ {
+			deal := generateDealProposal(client, provider, startEpoch, endEpoch+abi.ChainEpoch(i))
+			deals = append(deals, deal)
+			clientFunds = big.Add(clientFunds, deal.ClientBalanceRequirement())
+			providerFunds = big.Add(providerFunds, deal.ProviderCollateral)
+		}
+		actor.addProviderFunds(rt, providerFunds, mAddrs)
+		actor.addParticipantFunds(rt, client, clientFunds)
+		return deals
+	}
+	// Publishes the deals, expecting signature checks for only the first `examined` of them.
+	publish := func(rt *mock.Runtime, actor *marketActorTestHarness, examined int, deals ...market.DealProposal) *market.PublishStorageDealsReturn {
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+		expectQueryNetworkInfo(rt, actor)
+		for _, d := range deals[:examined] {
+			d := d
+			rt.ExpectVerifySignature(crypto.Signature{}, d.Client, mustCbor(&d), nil)
+		}
+		ret := rt.Call(actor.PublishStorageDeals, mkPublishStorageParams(deals...)).(*market.PublishStorageDealsReturn)
+		rt.Verify()
+		return ret
+	}
+	count := func(bf bitfield.BitField) int {
+		n, err := bf.Count()
+		require.NoError(t, err)
+		return int(n)
+	}
 
-	// Test adding provider funds from both worker and owner address
-	rt, actor := basicMarketSetup(t, owner, provider, worker, client)
-	actor.addProviderFunds(rt, abi.NewTokenAmount(20000000), minerAddrs)
-	rt.GetState(&st)
-	assert.Equal(t, abi.NewTokenAmount(20000000), actor.getEscrowBalance(rt, provider))
+	t.Run("deals up to the cap are published", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deals := generateDeals(rt, actor, market.MaxDealsPerProviderPerPublish)
 
-	actor.addParticipantFunds(rt, client, abi.NewTokenAmount(20000000))
+		ret := publish(rt, actor, len(deals), deals...)
+		assert.Len(t, ret.IDs, market.MaxDealsPerProviderPerPublish)
+		assert.Equal(t, market.MaxDealsPerProviderPerPublish, count(ret.ValidDeals))
+		actor.checkState(rt)
+	})
 
-	dealProposal := generateDealProposal(client, provider, abi.ChainEpoch(1), abi.ChainEpoch(200*builtin.EpochsInDay))
-	bs := make([]byte, market.DealMaxLabelSize)
-	for i := 0; i < len(bs); i++ {
+	t.Run("deals beyond the cap are deferred", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deals := generateDeals(rt, actor, market.MaxDealsPerProviderPerPublish+2)
+
+		ret := publish(rt, actor, market.MaxDealsPerProviderPerPublish, deals...)
+		assert.Len(t, ret.IDs, market.MaxDealsPerProviderPerPublish)
+		assert.Equal(t, market.MaxDealsPerProviderPerPublish, count(ret.ValidDeals))
+		last, err := ret.ValidDeals.Last()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(market.MaxDealsPerProviderPerPublish-1), last)
+		rt.ExpectLogsContain(fmt.Sprintf("deferred 2 deals from index %d beyond the limit of %d per provider",
+			market.MaxDealsPerProviderPerPublish, market.MaxDealsPerProviderPerPublish))
+
+		// The deferred deals may be published by a subsequent message.
+		ret = publish(rt, actor, 2, deals[market.MaxDealsPerProviderPerPublish:]...)
+		assert.Len(t, ret.IDs, 2)
+		actor.checkState(rt)
+	})
+
+	t.Run("invalid deals do not count towards the cap", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		invalid := generateDealProposal(client, provider, startEpoch, endEpoch)
+		invalid.StoragePricePerEpoch = abi.NewTokenAmount(-1)
+		deals := append([]market.DealProposal{invalid}, generateDeals(rt, actor, market.MaxDealsPerProviderPerPublish)...)
+
+		ret := publish(rt, actor, len(deals), deals...)
+		assert.Len(t, ret.IDs, market.MaxDealsPerProviderPerPublish)
+		assert.Equal(t, market.MaxDealsPerProviderPerPublish, count(ret.ValidDeals))
+		isSet, err := ret.ValidDeals.IsSet(0)
+		require.NoError(t, err)
+		assert.False(t, isSet)
+		actor.checkState(rt)
+	})
+}
+
+func TestMaxDealLabelSize(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	minerAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	var st market.State
+
+	// Test adding provider funds from both worker and owner address
+	rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+	actor.addProviderFunds(rt, abi.NewTokenAmount(20000000), minerAddrs)
+	rt.GetState(&st)
+	assert.Equal(t, abi.NewTokenAmount(20000000), actor.getEscrowBalance(rt, provider))
+
+	actor.addParticipantFunds(rt, client, abi.NewTokenAmount(20000000))
+
+	dealProposal := generateDealProposal(client, provider, abi.ChainEpoch(1), abi.ChainEpoch(200*builtin.EpochsInDay))
+	bs := make([]byte, market.DealMaxLabelSize)
+	for i := 0; i < len(bs); i++ {
 		bs[i] = 's' // 00 is also utf8 but set to something obvious for clarity
 	}
 	label, err := market.NewLabelFromString(string(bs))
@@ -2458,6 +2985,15 @@ func TestMaxDealLabelSize(t *testing.T) {
 		actor.publishDeals(rt, minerAddrs, publishDealReq{deal: dealProposal})
 	}
 
+	// A label referencing a CID should work.
+	{
+		label, err := market.NewLabelFromCID(tutil.MakeCID("payload", nil))
+		require.NoError(t, err)
+		dealProposal.Label = label
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		actor.publishDeals(rt, minerAddrs, publishDealReq{deal: dealProposal})
+	}
+
 	// using label type prevents even getting a handle to a label exceeding max size
 	bs = append(bs, 'b')
 	assert.Equal(t, market.DealMaxLabelSize+1, len(bs))
@@ -2466,6 +3002,447 @@ func TestMaxDealLabelSize(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDealCollateralBounds(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	duration := abi.ChainEpoch(200 * builtin.EpochsInDay)
+
+	t.Run("bounds match publish validation for small and large deals", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCirculatingSupply(big.Mul(big.NewInt(1e9), big.NewInt(1e18)))
+
+		small := actor.dealCollateralBounds(rt, abi.PaddedPieceSize(1<<20), false, duration)
+		large := actor.dealCollateralBounds(rt, abi.PaddedPieceSize(32<<30), false, duration)
+
+		for _, c := range []struct {
+			size   abi.PaddedPieceSize
+			bounds *market.DealCollateralBoundsReturn
+		}{{1 << 20, small}, {32 << 30, large}} {
+			minP, maxP := market.DealProviderCollateralBounds(c.size, false, big.Zero(), actor.networkQAPower,
+				actor.networkBaselinePower, rt.TotalFilCircSupply())
+			minC, maxC := market.DealClientCollateralBounds(c.size, duration)
+			assert.Equal(t, minP, c.bounds.MinProviderCollateral)
+			assert.Equal(t, maxP, c.bounds.MaxProviderCollateral)
+			assert.Equal(t, minC, c.bounds.MinClientCollateral)
+			assert.Equal(t, maxC, c.bounds.MaxClientCollateral)
+		}
+
+		// Minimum provider collateral scales with deal size.
+		assert.True(t, small.MinProviderCollateral.GreaterThan(big.Zero()))
+		assert.True(t, large.MinProviderCollateral.GreaterThan(small.MinProviderCollateral))
+		assert.Equal(t, small.MaxProviderCollateral, large.MaxProviderCollateral)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails with invalid piece size", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.DealCollateralBounds, &market.DealCollateralBoundsParams{PieceSize: abi.PaddedPieceSize(1000), Duration: duration})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails with non-positive duration", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.DealCollateralBounds, &market.DealCollateralBoundsParams{PieceSize: abi.PaddedPieceSize(1 << 20), Duration: 0})
+		})
+		rt.Verify()
+	})
+}
+
+func TestEscrowBreakdown(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+	extra := abi.NewTokenAmount(1000)
+
+	// Publishes and activates one deal, publishes another starting later, and adds unlocked funds for the client.
+	setup := func(t *testing.T) (*mock.Runtime, *marketActorTestHarness, abi.DealID, abi.DealID) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		active := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		pending := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch+20*builtin.EpochsInDay, endEpoch+20*builtin.EpochsInDay)
+		actor.addParticipantFunds(rt, client, extra)
+		return rt, actor, active, pending
+	}
+
+	t.Run("attributes locked funds to storage fees and collateral", func(t *testing.T) {
+		rt, actor, activeID, pendingID := setup(t)
+		active := actor.getDealProposal(rt, activeID)
+		pending := actor.getDealProposal(rt, pendingID)
+
+		ret := actor.escrowBreakdown(rt, client, 0, 0)
+		assert.Equal(t, actor.getEscrowBalance(rt, client), ret.Total)
+		assert.Equal(t, actor.getLockedBalance(rt, client), ret.Locked)
+		assert.Equal(t, extra, ret.Available)
+		assert.Equal(t, big.Add(active.TotalStorageFee(), pending.TotalStorageFee()), ret.LockedStorageFee)
+		assert.Equal(t, big.Add(active.ClientCollateral, pending.ClientCollateral), ret.LockedCollateral)
+		assert.Equal(t, ret.Locked, big.Add(ret.LockedStorageFee, ret.LockedCollateral))
+		assert.Equal(t, abi.DealID(2), ret.NextDealID)
+		assert.True(t, ret.Done)
+
+		ret = actor.escrowBreakdown(rt, provider, 0, 0)
+		assert.Equal(t, actor.getLockedBalance(rt, provider), ret.Locked)
+		assert.Equal(t, big.Zero(), ret.LockedStorageFee)
+		assert.Equal(t, big.Add(active.ProviderCollateral, pending.ProviderCollateral), ret.LockedCollateral)
+		assert.Equal(t, ret.Locked, ret.LockedCollateral)
+		actor.checkState(rt)
+	})
+
+	t.Run("excludes storage fees already paid", func(t *testing.T) {
+		rt, actor, activeID, pendingID := setup(t)
+		active := actor.getDealProposal(rt, activeID)
+		pending := actor.getDealProposal(rt, pendingID)
+
+		current := startEpoch + 10*builtin.EpochsInDay
+		rt.SetEpoch(current)
+		payment, _ := actor.cronTickAndAssertBalances(rt, client, provider, current, activeID)
+		require.True(t, payment.GreaterThan(big.Zero()))
+
+		ret := actor.escrowBreakdown(rt, client, 0, 0)
+		assert.Equal(t, extra, ret.Available)
+		assert.Equal(t, big.Sum(active.TotalStorageFee(), pending.TotalStorageFee(), payment.Neg()), ret.LockedStorageFee)
+		assert.Equal(t, ret.Locked, big.Add(ret.LockedStorageFee, ret.LockedCollateral))
+		actor.checkState(rt)
+	})
+
+	t.Run("pages through deals with a limit", func(t *testing.T) {
+		rt, actor, activeID, pendingID := setup(t)
+		active := actor.getDealProposal(rt, activeID)
+		pending := actor.getDealProposal(rt, pendingID)
+
+		ret := actor.escrowBreakdown(rt, client, 0, 1)
+		assert.Equal(t, active.ClientCollateral, ret.LockedCollateral)
+		assert.Equal(t, abi.DealID(1), ret.NextDealID)
+		assert.False(t, ret.Done)
+
+		ret = actor.escrowBreakdown(rt, client, ret.NextDealID, 1)
+		assert.Equal(t, pending.ClientCollateral, ret.LockedCollateral)
+		assert.Equal(t, extra, ret.Available)
+		assert.True(t, ret.Done)
+	})
+
+	t.Run("address without escrow has empty breakdown", func(t *testing.T) {
+		rt, actor, _, _ := setup(t)
+		ret := actor.escrowBreakdown(rt, owner, 0, 0)
+		assert.Equal(t, &market.EscrowBreakdownReturn{
+			Total:            big.Zero(),
+			Locked:           big.Zero(),
+			Available:        big.Zero(),
+			LockedStorageFee: big.Zero(),
+			LockedCollateral: big.Zero(),
+			NextDealID:       2,
+			Done:             true,
+		}, ret)
+	})
+}
+
+func TestDealDurationHistogram(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	sectorExpiry := startEpoch + 500*builtin.EpochsInDay
+	bounds := []abi.ChainEpoch{225 * builtin.EpochsInDay, 350 * builtin.EpochsInDay}
+
+	// Publishes and activates deals remaining 200, 250, 300 and 400 days from the start epoch, plus one unactivated deal.
+	setup := func(t *testing.T) (*mock.Runtime, *marketActorTestHarness, []abi.DealID) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		var dealIDs []abi.DealID
+		for _, days := range []abi.ChainEpoch{200, 250, 300, 400} {
+			dealIDs = append(dealIDs, actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch,
+				startEpoch+days*builtin.EpochsInDay, 0, sectorExpiry))
+		}
+		actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch+1, startEpoch+200*builtin.EpochsInDay)
+		return rt, actor, dealIDs
+	}
+
+	t.Run("buckets active deals by remaining duration", func(t *testing.T) {
+		rt, actor, _ := setup(t)
+		rt.SetEpoch(startEpoch)
+
+		ret := actor.dealDurationHistogram(rt, bounds, 0, 0)
+		assert.Equal(t, []uint64{1, 2, 1}, ret.Counts)
+		assert.Equal(t, abi.DealID(5), ret.NextDealID)
+		assert.True(t, ret.Done)
+
+		// Advancing time moves deals into shorter duration buckets, and excludes expired ones.
+		rt.SetEpoch(startEpoch + 260*builtin.EpochsInDay)
+		ret = actor.dealDurationHistogram(rt, bounds, 0, 0)
+		assert.Equal(t, []uint64{2, 0, 0}, ret.Counts)
+		actor.checkState(rt)
+	})
+
+	t.Run("excludes terminated deals", func(t *testing.T) {
+		rt, actor, dealIDs := setup(t)
+		rt.SetEpoch(startEpoch)
+		actor.terminateDeals(rt, provider, dealIDs[1])
+
+		ret := actor.dealDurationHistogram(rt, bounds, 0, 0)
+		assert.Equal(t, []uint64{1, 1, 1}, ret.Counts)
+		actor.checkState(rt)
+	})
+
+	t.Run("pages through deals with a limit", func(t *testing.T) {
+		rt, actor, _ := setup(t)
+		rt.SetEpoch(startEpoch)
+
+		ret := actor.dealDurationHistogram(rt, bounds, 0, 3)
+		assert.Equal(t, []uint64{1, 2, 0}, ret.Counts)
+		assert.Equal(t, abi.DealID(3), ret.NextDealID)
+		assert.False(t, ret.Done)
+
+		ret = actor.dealDurationHistogram(rt, bounds, ret.NextDealID, 3)
+		assert.Equal(t, []uint64{0, 0, 1}, ret.Counts)
+		assert.Equal(t, abi.DealID(5), ret.NextDealID)
+		assert.True(t, ret.Done)
+
+		// Resuming from the end examines nothing.
+		ret = actor.dealDurationHistogram(rt, bounds, ret.NextDealID, 3)
+		assert.Equal(t, []uint64{0, 0, 0}, ret.Counts)
+		assert.True(t, ret.Done)
+	})
+
+	t.Run("fails with invalid bucket bounds", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		for _, invalid := range [][]abi.ChainEpoch{
+			nil,
+			{0, 100},
+			{100, 100},
+			{200, 100},
+			make([]abi.ChainEpoch, market.DealDurationHistogramMaxBuckets+1),
+		} {
+			rt.SetCaller(client, builtin.AccountActorCodeID)
+			rt.ExpectValidateCallerAny()
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.DealDurationHistogram, &market.DealDurationHistogramParams{BucketBounds: invalid})
+			})
+			rt.Verify()
+		}
+	})
+}
+
+func TestDataCapReconciliation(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+
+	t.Run("consumed datacap is pending until activation", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal1 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal1.VerifiedDeal = true
+		deal2 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+1)
+		deal2.VerifiedDeal = true
+		deal3 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+2)
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealIDs := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal1}, publishDealReq{deal: deal2}, publishDealReq{deal: deal3})
+
+		// Only verified deals are recorded.
+		ret := actor.dataCapReconciliation(rt)
+		assert.Equal(t, uint64(2), ret.Pending)
+		assert.Empty(t, ret.Mismatches)
+
+		actor.activateDeals(rt, sectorExpiry, provider, rt.Epoch(), dealIDs[0])
+		ret = actor.dataCapReconciliation(rt)
+		assert.Equal(t, uint64(1), ret.Pending)
+		assert.Empty(t, ret.Mismatches)
+		actor.checkState(rt)
+	})
+
+	t.Run("datacap consumed then restored is reconciled", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal.VerifiedDeal = true
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealIDs := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})
+
+		rt.SetEpoch(processEpoch(t, dealIDs[0], startEpoch))
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, &verifreg.RestoreBytesParams{
+			Address:  deal.Client,
+			DealSize: big.NewIntUnsigned(uint64(deal.PieceSize)),
+		}, abi.NewTokenAmount(0), &verifreg.RestoreBytesReturn{Restored: big.NewIntUnsigned(uint64(deal.PieceSize))}, exitcode.Ok)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, deal.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+
+		ret := actor.dataCapReconciliation(rt)
+		assert.Equal(t, uint64(0), ret.Pending)
+		assert.Empty(t, ret.Mismatches)
+		actor.checkState(rt)
+	})
+
+	t.Run("remainder forfeited when datacap is exhausted is flagged as a mismatch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal.VerifiedDeal = true
+
+		// The deal leaves the client with less than the minimum deal size, which the registry
+		// forfeits along with the deal's size.
+		dealSize := big.NewIntUnsigned(uint64(deal.PieceSize))
+		consumed := big.Add(dealSize, big.NewInt(1000))
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealIDs := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal, dataCapConsumed: consumed})
+		rt.ExpectLogsContain(fmt.Sprintf("verified client %s removed after deal 0 consumed %v of its DataCap", client, consumed))
+
+		ret := actor.dataCapReconciliation(rt)
+		assert.Equal(t, uint64(1), ret.Pending)
+		assert.Empty(t, ret.Mismatches)
+
+		// Only the deal's size is restored when it times out.
+		timeoutEpoch := processEpoch(t, dealIDs[0], startEpoch)
+		rt.SetEpoch(timeoutEpoch)
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, &verifreg.RestoreBytesParams{
+			Address:  deal.Client,
+			DealSize: dealSize,
+		}, abi.NewTokenAmount(0), &verifreg.RestoreBytesReturn{Restored: dealSize}, exitcode.Ok)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, deal.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+
+		ret = actor.dataCapReconciliation(rt)
+		assert.Equal(t, uint64(0), ret.Pending)
+		require.Len(t, ret.Mismatches, 1)
+		mismatch := ret.Mismatches[0]
+		assert.Equal(t, dealIDs[0], mismatch.DealID)
+		assert.Equal(t, client, mismatch.Client)
+		assert.Equal(t, consumed, mismatch.Consumed)
+		assert.Equal(t, dealSize, mismatch.Restored)
+		assert.Equal(t, timeoutEpoch, mismatch.RestoreEpoch)
+		actor.checkState(rt)
+	})
+
+	t.Run("failed restoration is flagged as a mismatch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal1 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal1.VerifiedDeal = true
+		deal2 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+1)
+		deal2.VerifiedDeal = true
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealIDs := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal1}, publishDealReq{deal: deal2})
+
+		// The registry restores the first deal's datacap but fails to restore the second's.
+		timeoutEpoch := processEpoch(t, dealIDs[1], startEpoch)
+		rt.SetEpoch(timeoutEpoch)
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, &verifreg.RestoreBytesParams{
+			Address:  deal1.Client,
+			DealSize: big.NewIntUnsigned(uint64(deal1.PieceSize)),
+		}, abi.NewTokenAmount(0), &verifreg.RestoreBytesReturn{Restored: big.NewIntUnsigned(uint64(deal1.PieceSize))}, exitcode.Ok)
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, &verifreg.RestoreBytesParams{
+			Address:  deal2.Client,
+			DealSize: big.NewIntUnsigned(uint64(deal2.PieceSize)),
+		}, abi.NewTokenAmount(0), &verifreg.RestoreBytesReturn{Restored: big.Zero()}, exitcode.ErrIllegalArgument)
+		expectedBurn := big.Mul(big.NewInt(2), deal1.ProviderCollateral)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedBurn, nil, exitcode.Ok)
+		actor.cronTick(rt)
+
+		ret := actor.dataCapReconciliation(rt)
+		assert.Equal(t, uint64(0), ret.Pending)
+		require.Len(t, ret.Mismatches, 1)
+		mismatch := ret.Mismatches[0]
+		assert.Equal(t, dealIDs[1], mismatch.DealID)
+		assert.Equal(t, client, mismatch.Client)
+		assert.Equal(t, big.NewIntUnsigned(uint64(deal2.PieceSize)), mismatch.Consumed)
+		assert.Equal(t, big.Zero(), mismatch.Restored)
+		assert.Equal(t, timeoutEpoch, mismatch.RestoreEpoch)
+		actor.checkState(rt)
+
+		// Once reconciled, the client clears the mismatch.
+		actor.clearDataCapMismatches(rt, client, dealIDs[1])
+		ret = actor.dataCapReconciliation(rt)
+		assert.Empty(t, ret.Mismatches)
+		actor.checkState(rt)
+	})
+
+	t.Run("only the client may clear a mismatch, and only once restored", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal.VerifiedDeal = true
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealIDs := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is pending", func() {
+			actor.clearDataCapMismatches(rt, client, dealIDs[0])
+		})
+
+		rt.SetEpoch(processEpoch(t, dealIDs[0], startEpoch))
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, &verifreg.RestoreBytesParams{
+			Address:  deal.Client,
+			DealSize: big.NewIntUnsigned(uint64(deal.PieceSize)),
+		}, abi.NewTokenAmount(0), &verifreg.RestoreBytesReturn{Restored: big.Zero()}, exitcode.ErrIllegalArgument)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, deal.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is not the client", func() {
+			actor.clearDataCapMismatches(rt, provider, dealIDs[0])
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrNotFound, "no datacap ledger entry", func() {
+			actor.clearDataCapMismatches(rt, client, dealIDs[0]+1)
+		})
+		require.Len(t, actor.dataCapReconciliation(rt).Mismatches, 1)
+		actor.checkState(rt)
+	})
+}
+
+func TestComputeDealProposalCid(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+
+	rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+	deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+	rt.SetCaller(worker, builtin.AccountActorCodeID)
+	actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})
+
+	// The computed CID is that recorded in pending proposals.
+	pcid := actor.computeDealProposalCid(rt, &deal)
+	var st market.State
+	rt.GetState(&st)
+	pending, err := adt.AsMap(adt.AsStore(rt), st.PendingProposals, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	found, err := pending.Get(abi.CidKey(pcid), nil)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	// A different proposal has a different CID, which is not pending.
+	other := deal
+	other.EndEpoch++
+	otherCid := actor.computeDealProposalCid(rt, &other)
+	assert.NotEqual(t, pcid, otherCid)
+	found, err = pending.Get(abi.CidKey(otherCid), nil)
+	require.NoError(t, err)
+	assert.False(t, found)
+	actor.checkState(rt)
+}
+
 func TestComputeDataCommitment(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -2626,6 +3603,331 @@ func TestComputeDataCommitment(t *testing.T) {
 
 }
 
+func TestClientAgents(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	agent := tutil.NewIDAddr(t, 105)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sigErr := errors.New("invalid signature")
+
+	// Publishes a single deal whose signature does not verify for the client, expecting verification
+	// to be attempted against each of the given agents in turn.
+	expectPublish := func(rt *mock.Runtime, actor *marketActorTestHarness, deal market.DealProposal, agentResults ...error) interface{} {
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, big.Zero(),
+			&miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, exitcode.Ok)
+		expectQueryNetworkInfo(rt, actor)
+		rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&deal), sigErr)
+		for i, result := range agentResults {
+			rt.ExpectVerifySignature(crypto.Signature{}, tutil.NewIDAddr(t, 105+uint64(i)), mustCbor(&deal), result)
+		}
+		return rt.Call(actor.PublishStorageDeals, mkPublishStorageParams(deal))
+	}
+
+	t.Run("deal signed by an authorized agent is published", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		actor.authorizeClientAgent(rt, client, agent)
+		actor.authorizeClientAgent(rt, client, tutil.NewIDAddr(t, 106))
+
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		ret := expectPublish(rt, actor, deal, sigErr, nil)
+		rt.Verify()
+
+		resp := ret.(*market.PublishStorageDealsReturn)
+		require.Len(t, resp.IDs, 1)
+		assert.Equal(t, client, actor.getDealProposal(rt, resp.IDs[0]).Client)
+		actor.checkState(rt)
+	})
+
+	t.Run("deal signed by an unauthorized agent is rejected", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "All deal proposals invalid", func() {
+			expectPublish(rt, actor, deal)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("deal signed by a revoked agent is rejected", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		actor.authorizeClientAgent(rt, client, agent)
+		actor.revokeClientAgent(rt, client, agent)
+
+		var st market.State
+		rt.GetState(&st)
+		emptyMap, err := adt.StoreEmptyMap(adt.AsStore(rt), builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		assert.Equal(t, emptyMap, st.ClientAgents)
+
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "All deal proposals invalid", func() {
+			expectPublish(rt, actor, deal)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to authorize self or an existing agent", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "cannot authorize itself", func() {
+			rt.Call(actor.AuthorizeClientAgent, &market.ClientAgentParams{Agent: client})
+		})
+		rt.Verify()
+
+		actor.authorizeClientAgent(rt, client, agent)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "already authorized", func() {
+			rt.Call(actor.AuthorizeClientAgent, &market.ClientAgentParams{Agent: agent})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to authorize more than the maximum agents", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		for i := 0; i < market.MaxClientAgents; i++ {
+			actor.authorizeClientAgent(rt, client, tutil.NewIDAddr(t, 200+uint64(i)))
+		}
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.AuthorizeClientAgent, &market.ClientAgentParams{Agent: agent})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to revoke an agent that is not authorized", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.RevokeClientAgent, &market.ClientAgentParams{Agent: agent})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
+func TestUpdateDealDurationBounds(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+
+	// Publishes a single funded deal of the given duration, expecting it to be rejected.
+	expectPublishRejected := func(rt *mock.Runtime, actor *marketActorTestHarness, duration abi.ChainEpoch) {
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, startEpoch+duration)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, big.Zero(),
+			&miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, exitcode.Ok)
+		expectQueryNetworkInfo(rt, actor)
+		rt.ExpectVerifySignature(crypto.Signature{}, client, mustCbor(&deal), nil)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "All deal proposals invalid", func() {
+			rt.Call(actor.PublishStorageDeals, mkPublishStorageParams(deal))
+		})
+		rt.Verify()
+	}
+
+	t.Run("constructed with mainnet defaults", func(t *testing.T) {
+		rt, _ := basicMarketSetup(t, owner, provider, worker, client)
+		var st market.State
+		rt.GetState(&st)
+		assert.Equal(t, market.DealMinDuration, st.DealMinDuration)
+		assert.Equal(t, market.DealMaxDuration, st.DealMaxDuration)
+	})
+
+	t.Run("narrowed bounds reject a previously valid duration", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		duration := abi.ChainEpoch(200 * builtin.EpochsInDay)
+		actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, startEpoch+duration)
+
+		actor.updateDealDurationBounds(rt, market.DealMinDuration, duration-1)
+		expectPublishRejected(rt, actor, duration)
+		actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, startEpoch+duration-1)
+
+		actor.updateDealDurationBounds(rt, duration+1, market.DealMaxDuration)
+		expectPublishRejected(rt, actor, duration)
+		actor.checkState(rt)
+	})
+
+	t.Run("widened bounds allow a previously invalid duration", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		short := abi.ChainEpoch(30 * builtin.EpochsInDay)
+		long := market.DealMaxDuration + builtin.EpochsInDay
+		expectPublishRejected(rt, actor, short)
+		expectPublishRejected(rt, actor, long)
+
+		actor.updateDealDurationBounds(rt, short, long)
+		actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, startEpoch+short)
+		actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, startEpoch+long)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when called by other than the system actor", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCaller(owner, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.UpdateDealDurationBounds, &market.UpdateDealDurationBoundsParams{
+				MinDuration: builtin.EpochsInDay,
+				MaxDuration: market.DealMaxDuration,
+			})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails with invalid bounds", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		for _, params := range []market.UpdateDealDurationBoundsParams{
+			{MinDuration: 0, MaxDuration: market.DealMaxDuration},
+			{MinDuration: market.DealMaxDuration + 1, MaxDuration: market.DealMaxDuration},
+		} {
+			rt.SetCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+			rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.UpdateDealDurationBounds, &params)
+			})
+			rt.Verify()
+		}
+		actor.checkState(rt)
+	})
+}
+
+func TestSettleDealPayments(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+
+	t.Run("settles a subset of the provider's deals", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealID1 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		dealID2 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch+1, endEpoch+1, 0, sectorExpiry)
+		dealID3 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch+2, endEpoch+2, 0, sectorExpiry)
+		d1 := actor.getDealProposal(rt, dealID1)
+		d2 := actor.getDealProposal(rt, dealID2)
+
+		cEscrow := actor.getEscrowBalance(rt, client)
+		cLocked := actor.getLockedBalance(rt, client)
+		pEscrow := actor.getEscrowBalance(rt, provider)
+		pLocked := actor.getLockedBalance(rt, provider)
+
+		current := rt.SetEpoch(startEpoch + 100)
+		expected := big.Add(
+			big.Mul(big.NewInt(int64(current-d1.StartEpoch)), d1.StoragePricePerEpoch),
+			big.Mul(big.NewInt(int64(current-d2.StartEpoch)), d2.StoragePricePerEpoch),
+		)
+		ret := actor.settleDealPayments(rt, mAddrs, dealID1, dealID2)
+		assert.Equal(t, expected, ret.PaymentTransferred)
+
+		assert.Equal(t, big.Sub(cEscrow, expected), actor.getEscrowBalance(rt, client))
+		assert.Equal(t, big.Sub(cLocked, expected), actor.getLockedBalance(rt, client))
+		assert.Equal(t, big.Add(pEscrow, expected), actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, pLocked, actor.getLockedBalance(rt, provider))
+
+		assert.Equal(t, current, actor.getDealState(rt, dealID1).LastUpdatedEpoch)
+		assert.Equal(t, current, actor.getDealState(rt, dealID2).LastUpdatedEpoch)
+		assert.Equal(t, market.EpochUndefined, actor.getDealState(rt, dealID3).LastUpdatedEpoch)
+
+		// Settling again in the same epoch transfers nothing.
+		ret = actor.settleDealPayments(rt, mAddrs, dealID1, dealID2)
+		assert.Equal(t, big.Zero(), ret.PaymentTransferred)
+		actor.checkState(rt)
+	})
+
+	t.Run("cron pays only what remains after settlement", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealID := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealID)
+
+		settled := rt.SetEpoch(processEpoch(t, dealID, startEpoch) - 10)
+		ret := actor.settleDealPayments(rt, mAddrs, dealID)
+		assert.Equal(t, big.Mul(big.NewInt(int64(settled-startEpoch)), d.StoragePricePerEpoch), ret.PaymentTransferred)
+
+		current := rt.SetEpoch(processEpoch(t, dealID, startEpoch))
+		pay, _ := actor.cronTickAndAssertBalances(rt, client, provider, current, dealID)
+		assert.Equal(t, big.Mul(big.NewInt(int64(current-settled)), d.StoragePricePerEpoch), pay)
+		actor.checkState(rt)
+	})
+
+	t.Run("deal not yet started transfers nothing", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealID := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		rt.SetEpoch(startEpoch - 1)
+		ret := actor.settleDealPayments(rt, mAddrs, dealID)
+		assert.Equal(t, big.Zero(), ret.PaymentTransferred)
+		assert.Equal(t, market.EpochUndefined, actor.getDealState(rt, dealID).LastUpdatedEpoch)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for a deal of another provider", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealID := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		provider2 := tutil.NewIDAddr(t, 201)
+		owner2 := tutil.NewIDAddr(t, 202)
+		worker2 := tutil.NewIDAddr(t, 203)
+		rt.SetAddressActorType(provider2, builtin.StorageMinerActorCodeID)
+
+		rt.SetEpoch(startEpoch + 100)
+		rt.SetCaller(worker2, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(owner2, worker2)
+		expectGetControlAddresses(rt, provider2, owner2, worker2)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.SettleDealPayments, &market.SettleDealPaymentsParams{Provider: provider2, DealIDs: []abi.DealID{dealID}})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails when caller is not the provider's owner or worker", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealID := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(owner, worker)
+		expectGetControlAddresses(rt, provider, owner, worker)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.SettleDealPayments, &market.SettleDealPaymentsParams{Provider: provider, DealIDs: []abi.DealID{dealID}})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails for a deal not yet activated", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealID := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(owner, worker)
+		expectGetControlAddresses(rt, provider, owner, worker)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.SettleDealPayments, &market.SettleDealPaymentsParams{Provider: provider, DealIDs: []abi.DealID{dealID}})
+		})
+		rt.Verify()
+	})
+}
+
 func TestVerifyDealsForActivation(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -2789,6 +4091,26 @@ func TestVerifyDealsForActivation(t *testing.T) {
 		})
 		actor.checkState(rt)
 	})
+
+	t.Run("fail when the same deal ID is passed in multiple sectors", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, start, end)
+		otherDealId := actor.generateAndPublishDeal(rt, client, mAddrs, start, end+1)
+
+		param := &market.VerifyDealsForActivationParams{Sectors: []market.SectorDeals{{
+			SectorExpiry: sectorExpiry,
+			DealIDs:      []abi.DealID{dealId},
+		}, {
+			SectorExpiry: sectorExpiry,
+			DealIDs:      []abi.DealID{otherDealId, dealId},
+		}}}
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "present in sectors 0 and 1", func() {
+			rt.Call(actor.VerifyDealsForActivation, param)
+		})
+		actor.checkState(rt)
+	})
 }
 
 type marketActorTestHarness struct {
@@ -2963,6 +4285,9 @@ func (h *marketActorTestHarness) cronTickAndAssertBalances(rt *mock.Runtime, cli
 	}
 
 	h.cronTick(rt)
+	ret := h.lastCronTick(rt)
+	require.EqualValues(h.t, payment, ret.PaymentTransferred)
+	require.EqualValues(h.t, amountSlashed, ret.AmountSlashed)
 
 	require.EqualValues(h.t, updatedClientEscrow, h.getEscrowBalance(rt, client))
 	require.EqualValues(h.t, updatedClientLocked, h.getLockedBalance(rt, client))
@@ -2980,12 +4305,27 @@ func (h *marketActorTestHarness) cronTick(rt *mock.Runtime) {
 	rt.Verify()
 }
 
+func (h *marketActorTestHarness) lastCronTick(rt *mock.Runtime) *market.CronTickSummary {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.LastCronTick, nil).(*market.CronTickSummary)
+	rt.Verify()
+	return ret
+}
+
 type publishDealReq struct {
 	deal market.DealProposal
+	// The DataCap the verified registry reports consumed by a verified deal, if not the deal's size.
+	// This happens only when the registry removes the client, which it reports along with the amount.
+	dataCapConsumed abi.StoragePower
 }
 
 func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *minerAddrs, publishDealReqs ...publishDealReq) []abi.DealID {
+	return h.publishDealsWithOptions(rt, minerAddrs, nil, publishDealReqs...)
+}
 
+// Publishes deals with PublishStorageDealsWithOptions if options are given, else with PublishStorageDeals.
+func (h *marketActorTestHarness) publishDealsWithOptions(rt *mock.Runtime, minerAddrs *minerAddrs, options *market.PublishStorageDealsWithOptionsParams,
+	publishDealReqs ...publishDealReq) []abi.DealID {
 	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
 	rt.ExpectSend(
 		minerAddrs.provider,
@@ -3015,11 +4355,22 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 				DealSize: big.NewIntUnsigned(uint64(pdr.deal.PieceSize)),
 			}
 
-			rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.UseBytes, param, abi.NewTokenAmount(0), nil, exitcode.Ok)
+			ret := &verifreg.UseBytesReturn{RemainingCap: big.Zero(), Consumed: param.DealSize}
+			if !pdr.dataCapConsumed.Nil() {
+				ret.Consumed = pdr.dataCapConsumed
+				ret.ClientRemoved = true
+			}
+			rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.UseBytes, param, abi.NewTokenAmount(0), ret, exitcode.Ok)
 		}
 	}
 
-	ret := rt.Call(h.PublishStorageDeals, &params)
+	var ret interface{}
+	if options != nil {
+		options.Deals = params.Deals
+		ret = rt.Call(h.PublishStorageDealsWithOptions, options)
+	} else {
+		ret = rt.Call(h.PublishStorageDeals, &params)
+	}
 	rt.Verify()
 
 	resp, ok := ret.(*market.PublishStorageDealsReturn)
@@ -3079,6 +4430,40 @@ func (h *marketActorTestHarness) activateDeals(rt *mock.Runtime, sectorExpiry ab
 	}
 }
 
+func (h *marketActorTestHarness) batchActivateDeals(rt *mock.Runtime, provider address.Address, sectors ...market.SectorDeals) *market.BatchActivateDealsReturn {
+	rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+
+	ret := rt.Call(h.BatchActivateDeals, &market.BatchActivateDealsParams{Sectors: sectors}).(*market.BatchActivateDealsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) updateDealDurationBounds(rt *mock.Runtime, minDuration, maxDuration abi.ChainEpoch) {
+	rt.SetCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+	ret := rt.Call(h.UpdateDealDurationBounds, &market.UpdateDealDurationBoundsParams{
+		MinDuration: minDuration,
+		MaxDuration: maxDuration,
+	})
+	assert.Nil(h.t, ret)
+	rt.Verify()
+
+	var st market.State
+	rt.GetState(&st)
+	assert.Equal(h.t, minDuration, st.DealMinDuration)
+	assert.Equal(h.t, maxDuration, st.DealMaxDuration)
+}
+
+func (h *marketActorTestHarness) settleDealPayments(rt *mock.Runtime, minerAddrs *minerAddrs, dealIDs ...abi.DealID) *market.SettleDealPaymentsReturn {
+	rt.SetCaller(minerAddrs.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(minerAddrs.owner, minerAddrs.worker)
+	expectGetControlAddresses(rt, minerAddrs.provider, minerAddrs.owner, minerAddrs.worker)
+	ret := rt.Call(h.SettleDealPayments, &market.SettleDealPaymentsParams{Provider: minerAddrs.provider, DealIDs: dealIDs})
+	rt.Verify()
+	return ret.(*market.SettleDealPaymentsReturn)
+}
+
 func (h *marketActorTestHarness) getDealProposal(rt *mock.Runtime, dealID abi.DealID) *market.DealProposal {
 	var st market.State
 	rt.GetState(&st)
@@ -3234,6 +4619,19 @@ func (h *marketActorTestHarness) updateLastUpdated(rt *mock.Runtime, dealId abi.
 	rt.ReplaceState(&st)
 }
 
+func (h *marketActorTestHarness) deletePendingProposal(rt *mock.Runtime, dealId abi.DealID) {
+	var st market.State
+	rt.GetState(&st)
+	pcid, err := h.getDealProposal(rt, dealId).Cid()
+	require.NoError(h.t, err)
+	pending, err := adt.AsMap(adt.AsStore(rt), st.PendingProposals, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+	require.NoError(h.t, pending.Delete(abi.CidKey(pcid)))
+	st.PendingProposals, err = pending.Root()
+	require.NoError(h.t, err)
+	rt.ReplaceState(&st)
+}
+
 func (h *marketActorTestHarness) deleteDealProposal(rt *mock.Runtime, dealId abi.DealID) {
 	var st market.State
 	rt.GetState(&st)
@@ -3295,6 +4693,22 @@ func (h *marketActorTestHarness) generateDealWithCollateralAndAddFunds(rt *mock.
 	return deal
 }
 
+func (h *marketActorTestHarness) authorizeClientAgent(rt *mock.Runtime, client, agent address.Address) {
+	rt.SetCaller(client, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	ret := rt.Call(h.AuthorizeClientAgent, &market.ClientAgentParams{Agent: agent})
+	assert.Nil(h.t, ret)
+	rt.Verify()
+}
+
+func (h *marketActorTestHarness) revokeClientAgent(rt *mock.Runtime, client, agent address.Address) {
+	rt.SetCaller(client, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	ret := rt.Call(h.RevokeClientAgent, &market.ClientAgentParams{Agent: agent})
+	assert.Nil(h.t, ret)
+	rt.Verify()
+}
+
 func (h *marketActorTestHarness) checkState(rt *mock.Runtime, expectedMessagePatterns ...string) {
 	var st market.State
 	rt.GetState(&st)
@@ -3379,6 +4793,69 @@ func expectGetControlAddresses(rt *mock.Runtime, provider address.Address, owner
 	)
 }
 
+func (h *marketActorTestHarness) dealCollateralBounds(rt *mock.Runtime, size abi.PaddedPieceSize, verified bool,
+	duration abi.ChainEpoch) *market.DealCollateralBoundsReturn {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	expectQueryNetworkInfo(rt, h)
+	ret := rt.Call(h.DealCollateralBounds, &market.DealCollateralBoundsParams{
+		PieceSize:    size,
+		VerifiedDeal: verified,
+		Duration:     duration,
+	}).(*market.DealCollateralBoundsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) computeDealProposalCid(rt *mock.Runtime, proposal *market.DealProposal) cid.Cid {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.ComputeDealProposalCid, proposal).(*cbg.CborCid)
+	rt.Verify()
+	return cid.Cid(*ret)
+}
+
+func (h *marketActorTestHarness) clearDataCapMismatches(rt *mock.Runtime, caller address.Address, dealIDs ...abi.DealID) {
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	rt.Call(h.ClearDataCapMismatches, &market.ClearDataCapMismatchesParams{DealIDs: dealIDs})
+	rt.Verify()
+}
+
+func (h *marketActorTestHarness) dataCapReconciliation(rt *mock.Runtime) *market.DataCapReconciliationReturn {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.DataCapReconciliation, nil).(*market.DataCapReconciliationReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) escrowBreakdown(rt *mock.Runtime, a address.Address, start abi.DealID,
+	limit uint64) *market.EscrowBreakdownReturn {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.EscrowBreakdown, &market.EscrowBreakdownParams{
+		Address:     a,
+		StartDealID: start,
+		Limit:       limit,
+	}).(*market.EscrowBreakdownReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) dealDurationHistogram(rt *mock.Runtime, bounds []abi.ChainEpoch, start abi.DealID,
+	limit uint64) *market.DealDurationHistogramReturn {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 1000), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.DealDurationHistogram, &market.DealDurationHistogramParams{
+		BucketBounds: bounds,
+		StartDealID:  start,
+		Limit:        limit,
+	}).(*market.DealDurationHistogramReturn)
+	rt.Verify()
+	return ret
+}
+
 func expectQueryNetworkInfo(rt *mock.Runtime, h *marketActorTestHarness) {
 	currentPower := power.CurrentTotalPowerReturn{
 		QualityAdjPower: h.networkQAPower,
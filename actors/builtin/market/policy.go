@@ -17,16 +17,41 @@ var ProviderCollateralSupplyTarget = builtin.BigFrac{
 	Denominator: big.NewInt(100),
 }
 
-// Minimum deal duration.
+// Minimum deal duration with which the market is constructed. Governance may change the bound in state.
 var DealMinDuration = abi.ChainEpoch(180 * builtin.EpochsInDay) // PARAM_SPEC
 
-// Maximum deal duration
+// Maximum deal duration with which the market is constructed. Governance may change the bound in state.
 var DealMaxDuration = abi.ChainEpoch(540 * builtin.EpochsInDay) // PARAM_SPEC
 
 // DealMaxLabelSize is the maximum size of a deal label.
 const DealMaxLabelSize = 256
 
-// Bounds (inclusive) on deal duration
+// Maximum number of agents a client may authorize to sign deal proposals on its behalf.
+const MaxClientAgents = 8
+
+// Maximum number of deals from a single provider accepted by one PublishStorageDeals message.
+// Deals beyond the limit are excluded from the valid deals rather than failing the message,
+// and may be published by a later message.
+const MaxDealsPerProviderPerPublish = 1_000 // PARAM_SPEC
+
+// Maximum number of deal operations processed by a single CronTick. Any remaining are processed by later ticks.
+const MaxDealOpsPerCronTick = 10_000 // PARAM_SPEC
+
+// Maximum number of deal IDs examined by a single DealDurationHistogram call.
+const DealDurationHistogramMaxScan = 10_000
+
+// Maximum number of deal IDs examined by a single EscrowBreakdown call.
+const EscrowBreakdownMaxScan = 10_000
+
+// Maximum number of sectors whose deals may be activated by a single BatchActivateDeals call.
+// This matches the maximum number of sectors in a miner's ProveCommitAggregate.
+const BatchActivateDealsMaxSectors = 819
+
+// Maximum number of buckets in a DealDurationHistogram query.
+const DealDurationHistogramMaxBuckets = 64
+
+// Default bounds (inclusive) on deal duration, with which the market is constructed.
+// The bounds enforced at publication are those held in the market state.
 func DealDurationBounds(_ abi.PaddedPieceSize) (min abi.ChainEpoch, max abi.ChainEpoch) {
 	return DealMinDuration, DealMaxDuration
 }
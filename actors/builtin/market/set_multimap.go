@@ -117,9 +117,37 @@ func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
 	return nil
 }
 
+// Removes values for a key, which must be present.
+func (mm *SetMultimap) RemoveMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
+	k := abi.UIntKey(uint64(epoch))
+	set, found, err := mm.get(k)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return xerrors.Errorf("no set at key %v", epoch)
+	}
+
+	for _, v := range vs {
+		if err = set.Delete(dealKey(v)); err != nil {
+			return xerrors.Errorf("failed to remove key from set %v: %w", epoch, err)
+		}
+	}
+
+	src, err := set.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to flush set root: %w", err)
+	}
+	newSetRoot := cbg.CborCid(src)
+	if err = mm.mp.Put(k, &newSetRoot); err != nil {
+		return xerrors.Errorf("failed to store set: %w", err)
+	}
+	return nil
+}
+
 // Removes all values for a key.
 func (mm *SetMultimap) RemoveAll(key abi.ChainEpoch) error {
-	if _, err := mm.mp.TryDelete(abi.UIntKey(uint64(key))); err != nil {
+	if _, _, err := mm.mp.TryDelete(abi.UIntKey(uint64(key))); err != nil {
 		return xerrors.Errorf("failed to delete set key %v: %w", key, err)
 	}
 	return nil
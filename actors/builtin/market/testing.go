@@ -50,6 +50,10 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 		st.TotalClientStorageFee.GreaterThanEqual(big.Zero()),
 		"negative total client storage fee: %v", st.TotalClientLockedCollateral)
 
+	acc.Require(
+		st.DealMinDuration > 0 && st.DealMinDuration <= st.DealMaxDuration,
+		"invalid deal duration bounds [%d, %d]", st.DealMinDuration, st.DealMaxDuration)
+
 	//
 	// Proposals
 	//
@@ -58,6 +62,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 	maxDealID := int64(-1)
 	proposalStats := make(map[abi.DealID]*DealSummary)
 	expectedDealOps := make(map[abi.DealID]struct{})
+	verifiedDealSizes := make(map[abi.DealID]abi.PaddedPieceSize)
 	totalProposalCollateral := abi.NewTokenAmount(0)
 
 	if proposals, err := adt.AsArray(store, st.Proposals, ProposalsAmtBitwidth); err != nil {
@@ -88,6 +93,10 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 				SlashEpoch:       abi.ChainEpoch(-1),
 			}
 
+			if proposal.VerifiedDeal {
+				verifiedDealSizes[abi.DealID(dealID)] = proposal.PieceSize
+			}
+
 			totalProposalCollateral = big.Sum(totalProposalCollateral, proposal.ClientCollateral, proposal.ProviderCollateral)
 
 			acc.Require(proposal.Client.Protocol() == address.ID, "client address for deal %d is not an ID address", dealID)
@@ -168,6 +177,102 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 		acc.RequireNoError(err, "error iterating pending proposals")
 	}
 
+	//
+	// DataCap Ledger
+	//
+
+	// A verified deal's space is counted towards verified deal weight once, when it activates, at which point
+	// its ledger entry is removed. An entry for an extant proposal must therefore be for a pending verified deal.
+	// Conversely, every pending verified deal has an entry.
+	if ledger, err := adt.AsMap(store, st.DataCapLedger, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading datacap ledger: %v", err)
+	} else {
+		ledgerDealIDs := make(map[abi.DealID]struct{})
+		var entry DataCapLedgerEntry
+		err = ledger.ForEach(&entry, func(key string) error {
+			dealID, err := abi.ParseUIntKey(key)
+			if err != nil {
+				return err
+			}
+			ledgerDealIDs[abi.DealID(dealID)] = struct{}{}
+
+			stats, found := proposalStats[abi.DealID(dealID)]
+			if !found {
+				acc.Require(entry.RestoreEpoch != EpochUndefined,
+					"datacap ledger entry for deal %d with missing proposal has not been restored", dealID)
+				return nil
+			}
+
+			size, verified := verifiedDealSizes[abi.DealID(dealID)]
+			acc.Require(verified, "datacap ledger entry for deal %d which is not verified", dealID)
+			acc.Require(stats.SectorStartEpoch == EpochUndefined,
+				"datacap ledger entry for deal %d activated at epoch %d", dealID, stats.SectorStartEpoch)
+			acc.Require(entry.RestoreEpoch == EpochUndefined,
+				"datacap ledger entry for pending deal %d restored at epoch %d", dealID, entry.RestoreEpoch)
+			acc.Require(!verified || entry.Consumed.GreaterThanEqual(big.NewIntUnsigned(uint64(size))),
+				"datacap ledger entry for deal %d consumed %v, less than deal size %d", dealID, entry.Consumed, size)
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating datacap ledger")
+
+		for dealID := range verifiedDealSizes { //nolint:nomaprange
+			if stats := proposalStats[dealID]; stats.SectorStartEpoch == EpochUndefined {
+				_, found := ledgerDealIDs[dealID]
+				acc.Require(found, "no datacap ledger entry for pending verified deal %d", dealID)
+			}
+		}
+	}
+
+	//
+	// Auto-withdraw Deals
+	//
+
+	// Auto-withdraw entries are removed when their deal is removed.
+	if autoWithdrawDeals, err := adt.AsSet(store, st.AutoWithdrawDeals, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading auto-withdraw deals: %v", err)
+	} else {
+		err = autoWithdrawDeals.ForEach(func(key string) error {
+			dealID, err := abi.ParseUIntKey(key)
+			if err != nil {
+				return err
+			}
+			_, found := proposalStats[abi.DealID(dealID)]
+			acc.Require(found, "auto-withdraw entry for deal %d with missing proposal", dealID)
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating auto-withdraw deals")
+	}
+
+	//
+	// Client Agents
+	//
+
+	if agentsMap, err := adt.AsMap(store, st.ClientAgents, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading client agents: %v", err)
+	} else {
+		var entry ClientAgents
+		err = agentsMap.ForEach(&entry, func(key string) error {
+			client, err := address.NewFromBytes([]byte(key))
+			if err != nil {
+				return err
+			}
+			acc.Require(client.Protocol() == address.ID, "client agents key %v is not an ID address", client)
+			acc.Require(len(entry.Agents) > 0, "client %v has an empty agent list", client)
+			acc.Require(len(entry.Agents) <= MaxClientAgents, "client %v has %d agents, more than max %d",
+				client, len(entry.Agents), MaxClientAgents)
+			seen := make(map[address.Address]struct{}, len(entry.Agents))
+			for _, agent := range entry.Agents {
+				acc.Require(agent.Protocol() == address.ID, "client %v agent %v is not an ID address", client, agent)
+				acc.Require(agent != client, "client %v is its own agent", client)
+				_, dup := seen[agent]
+				acc.Require(!dup, "client %v has duplicate agent %v", client, agent)
+				seen[agent] = struct{}{}
+			}
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating client agents")
+	}
+
 	//
 	// Escrow Table and Locked Table
 	//
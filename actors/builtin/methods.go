@@ -26,11 +26,16 @@ var MethodsCron = struct {
 }{MethodConstructor, 2}
 
 var MethodsReward = struct {
-	Constructor      abi.MethodNum
-	AwardBlockReward abi.MethodNum
-	ThisEpochReward  abi.MethodNum
-	UpdateNetworkKPI abi.MethodNum
-}{MethodConstructor, 2, 3, 4}
+	Constructor              abi.MethodNum
+	AwardBlockReward         abi.MethodNum
+	ThisEpochReward          abi.MethodNum
+	UpdateNetworkKPI         abi.MethodNum
+	CumulativeReward         abi.MethodNum
+	ThisEpochRewardBreakdown abi.MethodNum
+	RewardFilterState        abi.MethodNum
+	LatestEpochReward        abi.MethodNum
+	MintingDecaySchedule     abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
 
 var MethodsMultisig = struct {
 	Constructor                 abi.MethodNum
@@ -52,16 +57,29 @@ var MethodsPaych = struct {
 }{MethodConstructor, 2, 3, 4}
 
 var MethodsMarket = struct {
-	Constructor              abi.MethodNum
-	AddBalance               abi.MethodNum
-	WithdrawBalance          abi.MethodNum
-	PublishStorageDeals      abi.MethodNum
-	VerifyDealsForActivation abi.MethodNum
-	ActivateDeals            abi.MethodNum
-	OnMinerSectorsTerminate  abi.MethodNum
-	ComputeDataCommitment    abi.MethodNum
-	CronTick                 abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	Constructor                    abi.MethodNum
+	AddBalance                     abi.MethodNum
+	WithdrawBalance                abi.MethodNum
+	PublishStorageDeals            abi.MethodNum
+	VerifyDealsForActivation       abi.MethodNum
+	ActivateDeals                  abi.MethodNum
+	OnMinerSectorsTerminate        abi.MethodNum
+	ComputeDataCommitment          abi.MethodNum
+	CronTick                       abi.MethodNum
+	DealCollateralBounds           abi.MethodNum
+	DealDurationHistogram          abi.MethodNum
+	DataCapReconciliation          abi.MethodNum
+	ComputeDealProposalCid         abi.MethodNum
+	AuthorizeClientAgent           abi.MethodNum
+	RevokeClientAgent              abi.MethodNum
+	EscrowBreakdown                abi.MethodNum
+	BatchActivateDeals             abi.MethodNum
+	UpdateDealDurationBounds       abi.MethodNum
+	SettleDealPayments             abi.MethodNum
+	PublishStorageDealsWithOptions abi.MethodNum
+	ClearDataCapMismatches         abi.MethodNum
+	LastCronTick                   abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22}
 
 var MethodsPower = struct {
 	Constructor              abi.MethodNum
@@ -73,37 +91,48 @@ var MethodsPower = struct {
 	Deprecated1              abi.MethodNum
 	SubmitPoRepForBulkVerify abi.MethodNum
 	CurrentTotalPower        abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	CurrentPledgeBase        abi.MethodNum
+	ReconcileClaim           abi.MethodNum
+	MinerClaim               abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
 
 var MethodsMiner = struct {
-	Constructor              abi.MethodNum
-	ControlAddresses         abi.MethodNum
-	ChangeWorkerAddress      abi.MethodNum
-	ChangePeerID             abi.MethodNum
-	SubmitWindowedPoSt       abi.MethodNum
-	PreCommitSector          abi.MethodNum
-	ProveCommitSector        abi.MethodNum
-	ExtendSectorExpiration   abi.MethodNum
-	TerminateSectors         abi.MethodNum
-	DeclareFaults            abi.MethodNum
-	DeclareFaultsRecovered   abi.MethodNum
-	OnDeferredCronEvent      abi.MethodNum
-	CheckSectorProven        abi.MethodNum
-	ApplyRewards             abi.MethodNum
-	ReportConsensusFault     abi.MethodNum
-	WithdrawBalance          abi.MethodNum
-	ConfirmSectorProofsValid abi.MethodNum
-	ChangeMultiaddrs         abi.MethodNum
-	CompactPartitions        abi.MethodNum
-	CompactSectorNumbers     abi.MethodNum
-	ConfirmUpdateWorkerKey   abi.MethodNum
-	RepayDebt                abi.MethodNum
-	ChangeOwnerAddress       abi.MethodNum
-	DisputeWindowedPoSt      abi.MethodNum
-	PreCommitSectorBatch     abi.MethodNum
-	ProveCommitAggregate     abi.MethodNum
-	ProveReplicaUpdates      abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27}
+	Constructor                   abi.MethodNum
+	ControlAddresses              abi.MethodNum
+	ChangeWorkerAddress           abi.MethodNum
+	ChangePeerID                  abi.MethodNum
+	SubmitWindowedPoSt            abi.MethodNum
+	PreCommitSector               abi.MethodNum
+	ProveCommitSector             abi.MethodNum
+	ExtendSectorExpiration        abi.MethodNum
+	TerminateSectors              abi.MethodNum
+	DeclareFaults                 abi.MethodNum
+	DeclareFaultsRecovered        abi.MethodNum
+	OnDeferredCronEvent           abi.MethodNum
+	CheckSectorProven             abi.MethodNum
+	ApplyRewards                  abi.MethodNum
+	ReportConsensusFault          abi.MethodNum
+	WithdrawBalance               abi.MethodNum
+	ConfirmSectorProofsValid      abi.MethodNum
+	ChangeMultiaddrs              abi.MethodNum
+	CompactPartitions             abi.MethodNum
+	CompactSectorNumbers          abi.MethodNum
+	ConfirmUpdateWorkerKey        abi.MethodNum
+	RepayDebt                     abi.MethodNum
+	ChangeOwnerAddress            abi.MethodNum
+	DisputeWindowedPoSt           abi.MethodNum
+	PreCommitSectorBatch          abi.MethodNum
+	ProveCommitAggregate          abi.MethodNum
+	ProveReplicaUpdates           abi.MethodNum
+	DeadlineExpirations           abi.MethodNum
+	AggregateProveCommitBounds    abi.MethodNum
+	PreviewDeclareFaultsRecovered abi.MethodNum
+	MinerAddresses                abi.MethodNum
+	SectorHealth                  abi.MethodNum
+	TopUpSectorPledge             abi.MethodNum
+	SectorsAtRisk                 abi.MethodNum
+	BatchDeclareFaults            abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35}
 
 var MethodsVerifiedRegistry = struct {
 	Constructor                 abi.MethodNum
@@ -113,4 +142,13 @@ var MethodsVerifiedRegistry = struct {
 	UseBytes                    abi.MethodNum
 	RestoreBytes                abi.MethodNum
 	RemoveVerifiedClientDataCap abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7}
+	SetMinVerifiedDealSize      abi.MethodNum
+	AuditLog                    abi.MethodNum
+	RemoveVerifierAndReclaim    abi.MethodNum
+	TotalDataCap                abi.MethodNum
+	CanUseBytes                 abi.MethodNum
+	VerifierClientCount         abi.MethodNum
+	RemoveVerifiers             abi.MethodNum
+	SetMaxClientDataCap         abi.MethodNum
+	RootKey                     abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
@@ -3,6 +3,7 @@ package miner
 import (
 	"bytes"
 	"errors"
+	"sort"
 
 	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
@@ -269,6 +270,71 @@ func (d *Deadline) LoadPartitionSnapshot(store adt.Store, partIdx uint64) (*Part
 	return &partition, nil
 }
 
+// Summarises the sectors scheduled to expire at a single (quantized) epoch.
+type ExpirationSummary struct {
+	Epoch         abi.ChainEpoch
+	OnTimeSectors uint64          // Number of sectors expiring at the end of their committed life
+	EarlySectors  uint64          // Number of faulty sectors expiring early
+	OnTimePledge  abi.TokenAmount // Pledge total for the on-time sectors
+	ActivePower   PowerPair       // Power of expiring sectors that is currently active
+	FaultyPower   PowerPair       // Power of expiring sectors that is currently faulty
+}
+
+// Aggregates the expiration queues of all partitions in this deadline into a summary per epoch,
+// in ascending epoch order.
+func (dl *Deadline) ExpirationSummaries(store adt.Store, quant builtin.QuantSpec) ([]ExpirationSummary, error) {
+	partitions, err := dl.PartitionsArray(store)
+	if err != nil {
+		return nil, err
+	}
+
+	byEpoch := map[abi.ChainEpoch]*ExpirationSummary{}
+	var partition Partition
+	if err := partitions.ForEach(&partition, func(partIdx int64) error {
+		queue, err := LoadExpirationQueue(store, partition.ExpirationsEpochs, quant, PartitionExpirationAmtBitwidth)
+		if err != nil {
+			return xerrors.Errorf("failed to load expiration queue for partition %d: %w", partIdx, err)
+		}
+		return queue.traverse(func(epoch abi.ChainEpoch, es *ExpirationSet) (bool, error) {
+			onTime, err := es.OnTimeSectors.Count()
+			if err != nil {
+				return false, err
+			}
+			early, err := es.EarlySectors.Count()
+			if err != nil {
+				return false, err
+			}
+			summary, ok := byEpoch[epoch]
+			if !ok {
+				summary = &ExpirationSummary{
+					Epoch:        epoch,
+					OnTimePledge: big.Zero(),
+					ActivePower:  NewPowerPairZero(),
+					FaultyPower:  NewPowerPairZero(),
+				}
+				byEpoch[epoch] = summary
+			}
+			summary.OnTimeSectors += onTime
+			summary.EarlySectors += early
+			summary.OnTimePledge = big.Add(summary.OnTimePledge, es.OnTimePledge)
+			summary.ActivePower = summary.ActivePower.Add(es.ActivePower)
+			summary.FaultyPower = summary.FaultyPower.Add(es.FaultyPower)
+			return true, nil
+		})
+	}); err != nil {
+		return nil, xerrors.Errorf("failed to traverse partition expirations: %w", err)
+	}
+
+	summaries := make([]ExpirationSummary, 0, len(byEpoch))
+	for _, summary := range byEpoch {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Epoch < summaries[j].Epoch
+	})
+	return summaries, nil
+}
+
 // Adds some partition numbers to the set expiring at an epoch.
 func (d *Deadline) AddExpirationPartitions(store adt.Store, expirationEpoch abi.ChainEpoch, partitions []uint64, quant builtin.QuantSpec) error {
 	// Avoid doing any work if there's nothing to reschedule.
@@ -684,10 +750,6 @@ func (dl *Deadline) RemovePartitions(store adt.Store, toRemove bitfield.BitField
 		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("cannot remove partitions from deadline with early terminations: %w", err)
 	}
 
-	newPartitions, err := adt.MakeEmptyArray(store, DeadlinePartitionsAmtBitwidth)
-	if err != nil {
-		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("failed to create empty array for initializing partitions: %w", err)
-	}
 	allDeadSectors := make([]bitfield.BitField, 0, len(toRemoveSet))
 	allLiveSectors := make([]bitfield.BitField, 0, len(toRemoveSet))
 	removedPower = NewPowerPairZero()
@@ -699,9 +761,9 @@ func (dl *Deadline) RemovePartitions(store adt.Store, toRemove bitfield.BitField
 		partition     Partition
 	)
 	if err = oldPartitions.ForEach(&lazyPartition, func(partIdx int64) error {
-		// If we're keeping the partition as-is, append it to the new partitions array.
+		// If we're keeping the partition as-is, leave it to be shifted into place.
 		if _, ok := toRemoveSet[uint64(partIdx)]; !ok {
-			return newPartitions.AppendContinuous(&lazyPartition)
+			return nil
 		}
 
 		// Ok, actually unmarshal the partition.
@@ -744,7 +806,19 @@ func (dl *Deadline) RemovePartitions(store adt.Store, toRemove bitfield.BitField
 		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("while removing partitions: %w", err)
 	}
 
-	dl.Partitions, err = newPartitions.Root()
+	// Delete the removed partitions and shift the remaining ones to the left.
+	removedIdxs, err := toRemove.All(partitionCount)
+	if err != nil {
+		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("failed to expand partitions to remove: %w", err)
+	}
+	if err = oldPartitions.BatchDelete(removedIdxs, true); err != nil {
+		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("failed to delete removed partitions: %w", err)
+	}
+	if _, err = oldPartitions.Compact(); err != nil {
+		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("failed to compact partitions: %w", err)
+	}
+
+	dl.Partitions, err = oldPartitions.Root()
 	if err != nil {
 		return bitfield.BitField{}, bitfield.BitField{}, NewPowerPairZero(), xerrors.Errorf("failed to persist new partition table: %w", err)
 	}
@@ -793,6 +867,50 @@ func (dl *Deadline) RemovePartitions(store adt.Store, toRemove bitfield.BitField
 	return live, dead, removedPower, nil
 }
 
+// RemoveEmptyPartitions removes all partitions with no live sectors, shifting the
+// remaining ones to the left, and returning the terminated sectors they contained.
+// The returned mapping holds, for each remaining partition's new index, the index
+// it previously occupied.
+//
+// Like RemovePartitions, this does not renumber the partitions referenced by PoSt
+// submissions, so must not be called while the deadline's proofs may be disputed.
+// Returns an error if the deadline has early terminations.
+func (dl *Deadline) RemoveEmptyPartitions(store adt.Store, quant builtin.QuantSpec) (
+	oldIndices []uint64, dead bitfield.BitField, err error,
+) {
+	partitions, err := dl.PartitionsArray(store)
+	if err != nil {
+		return nil, bitfield.BitField{}, xerrors.Errorf("failed to load partitions: %w", err)
+	}
+
+	var empty []uint64
+	var partition Partition
+	if err = partitions.ForEach(&partition, func(partIdx int64) error {
+		live, err := partition.LiveSectors()
+		if err != nil {
+			return xerrors.Errorf("failed to calculate live sectors for partition %d: %w", partIdx, err)
+		}
+		noLive, err := live.IsEmpty()
+		if err != nil {
+			return xerrors.Errorf("failed to check live sectors for partition %d: %w", partIdx, err)
+		}
+		if noLive {
+			empty = append(empty, uint64(partIdx))
+		} else {
+			oldIndices = append(oldIndices, uint64(partIdx))
+		}
+		return nil
+	}); err != nil {
+		return nil, bitfield.BitField{}, xerrors.Errorf("failed to find empty partitions: %w", err)
+	}
+
+	_, dead, _, err = dl.RemovePartitions(store, bitfield.NewFromSet(empty), quant)
+	if err != nil {
+		return nil, bitfield.BitField{}, xerrors.Errorf("failed to remove empty partitions: %w", err)
+	}
+	return oldIndices, dead, nil
+}
+
 func (dl *Deadline) RecordFaults(
 	store adt.Store, sectors Sectors, ssize abi.SectorSize, quant builtin.QuantSpec,
 	faultExpirationEpoch abi.ChainEpoch, partitionSectors PartitionSectorMap,
@@ -339,6 +339,42 @@ func TestDeadlines(t *testing.T) {
 			).assert(t, store, dl)
 	})
 
+	t.Run("removes empty partitions", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+		dl := emptyDeadline(t, store)
+		addSectors(t, store, dl, true)
+
+		// Terminate every sector in the first and last partitions, and one in the middle partition.
+		_, err := dl.TerminateSectors(store, sectorsArr(t, store, sectors), 15, miner.PartitionSectorMap{
+			0: bf(1, 2, 3, 4),
+			1: bf(6),
+			2: bf(9),
+		}, sectorSize, quantSpec)
+		require.NoError(t, err)
+
+		// Early terminations must be processed first.
+		_, _, err = dl.RemoveEmptyPartitions(store, quantSpec)
+		require.Error(t, err)
+		_, _, err = dl.PopEarlyTerminations(store, 100, 100)
+		require.NoError(t, err)
+
+		oldIndices, dead, err := dl.RemoveEmptyPartitions(store, quantSpec)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1}, oldIndices)
+		assertBitfieldEquals(t, dead, 1, 2, 3, 4, 9)
+
+		dlState.withTerminations(6).
+			withPartitions(
+				bf(5, 6, 7, 8),
+			).assert(t, store, dl)
+
+		// With no empty partitions left, nothing more is removed.
+		oldIndices, dead, err = dl.RemoveEmptyPartitions(store, quantSpec)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{0}, oldIndices)
+		assertBitfieldEquals(t, dead)
+	})
+
 	t.Run("fails to remove partitions with faulty sectors", func(t *testing.T) {
 		store := ipld.NewADTStore(context.Background())
 
@@ -486,6 +522,47 @@ func TestDeadlines(t *testing.T) {
 			).assert(t, store, dl)
 	})
 
+	t.Run("summarises expirations across partitions", func(t *testing.T) {
+		store := ipld.NewADTStore(context.Background())
+
+		dl := emptyDeadline(t, store)
+		addSectors(t, store, dl, true)
+
+		summaries, err := dl.ExpirationSummaries(store, quantSpec)
+		require.NoError(t, err)
+		require.Len(t, summaries, 3)
+
+		// Expirations are quantized up to epochs 5, 9 and 13, and epoch 9 spans all three partitions.
+		assertSummary := func(s miner.ExpirationSummary, epoch abi.ChainEpoch, onTime, early uint64, pledge int64,
+			activePower, faultyPower miner.PowerPair) {
+			assert.Equal(t, epoch, s.Epoch)
+			assert.Equal(t, onTime, s.OnTimeSectors)
+			assert.Equal(t, early, s.EarlySectors)
+			assert.Equal(t, abi.NewTokenAmount(pledge), s.OnTimePledge)
+			assert.True(t, activePower.Equals(s.ActivePower), "epoch %d active power %v", epoch, s.ActivePower)
+			assert.True(t, faultyPower.Equals(s.FaultyPower), "epoch %d faulty power %v", epoch, s.FaultyPower)
+		}
+		zero := miner.NewPowerPairZero()
+		assertSummary(summaries[0], 5, 2, 0, 1000+1001, sectorPower(t, 1, 2), zero)
+		assertSummary(summaries[1], 9, 5, 0, 1002+1003+1004+1007+1008, sectorPower(t, 3, 4, 5, 8, 9), zero)
+		assertSummary(summaries[2], 13, 2, 0, 1005+1006, sectorPower(t, 6, 7), zero)
+
+		// Faulty sectors are rescheduled to expire early at the fault expiration.
+		_, err = dl.RecordFaults(store, sectorsArr(t, store, sectors), sectorSize, quantSpec, 9,
+			map[uint64]bitfield.BitField{
+				0: bf(1),
+				1: bf(5, 6),
+			})
+		require.NoError(t, err)
+
+		summaries, err = dl.ExpirationSummaries(store, quantSpec)
+		require.NoError(t, err)
+		require.Len(t, summaries, 3)
+		assertSummary(summaries[0], 5, 2, 0, 1000+1001, sectorPower(t, 2), sectorPower(t, 1))
+		assertSummary(summaries[1], 9, 5, 1, 1002+1003+1004+1007+1008, sectorPower(t, 3, 4, 8, 9), sectorPower(t, 5, 6))
+		assertSummary(summaries[2], 13, 1, 0, 1006, sectorPower(t, 7), zero)
+	})
+
 	t.Run("cannot pop expired sectors before proving", func(t *testing.T) {
 		store := ipld.NewADTStore(context.Background())
 
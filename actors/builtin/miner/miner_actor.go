@@ -22,7 +22,7 @@ import (
 	miner3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/miner"
 	miner5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/miner"
 	cid "github.com/ipfs/go-cid"
-	cbg "github.com/whyrusleeping/cbor-gen"
+	multiaddr "github.com/multiformats/go-multiaddr"
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
@@ -80,6 +80,14 @@ func (a Actor) Exports() []interface{} {
 		25:                        a.PreCommitSectorBatch,
 		26:                        a.ProveCommitAggregate,
 		27:                        a.ProveReplicaUpdates,
+		28:                        a.DeadlineExpirations,
+		29:                        a.AggregateProveCommitBounds,
+		30:                        a.PreviewDeclareFaultsRecovered,
+		31:                        a.MinerAddresses,
+		32:                        a.SectorHealth,
+		33:                        a.TopUpSectorPledge,
+		34:                        a.SectorsAtRisk,
+		35:                        a.BatchDeclareFaults,
 	}
 }
 
@@ -162,6 +170,29 @@ func (a Actor) ControlAddresses(rt Runtime, _ *abi.EmptyValue) *GetControlAddres
 	}
 }
 
+type MinerAddressesReturn struct {
+	Owner  addr.Address
+	Worker addr.Address
+	// The worker key change awaiting confirmation, if any.
+	PendingWorkerKey *WorkerKeyChange
+	ControlAddrs     []addr.Address
+}
+
+// Returns the miner's owner, worker and control addresses along with any pending worker key change,
+// so that tooling need not read the miner info from state.
+func (a Actor) MinerAddresses(rt Runtime, _ *abi.EmptyValue) *MinerAddressesReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	var st State
+	rt.StateReadonly(&st)
+	info := getMinerInfo(rt, &st)
+	return &MinerAddressesReturn{
+		Owner:            info.Owner,
+		Worker:           info.Worker,
+		PendingWorkerKey: info.PendingWorkerKey,
+		ControlAddrs:     info.ControlAddresses,
+	}
+}
+
 //type ChangeWorkerAddressParams struct {
 //	NewWorker       addr.Address
 //	NewControlAddrs []addr.Address
@@ -289,8 +320,15 @@ func (a Actor) ChangePeerID(rt Runtime, params *ChangePeerIDParams) *abi.EmptyVa
 //}
 type ChangeMultiaddrsParams = miner0.ChangeMultiaddrsParams
 
+// Replaces the miner's multiaddrs, each of which must be a well-formed multiaddr.
+// The added and removed multiaddrs are logged.
 func (a Actor) ChangeMultiaddrs(rt Runtime, params *ChangeMultiaddrsParams) *abi.EmptyValue {
 	checkPeerInfo(rt, nil, params.NewMultiaddrs)
+	for i, ma := range params.NewMultiaddrs {
+		if _, err := multiaddr.NewMultiaddrBytes(ma); err != nil {
+			rt.Abortf(exitcode.ErrIllegalArgument, "malformed multiaddr %d: %s", i, err)
+		}
+	}
 
 	var st State
 	rt.StateTransaction(&st, func() {
@@ -298,6 +336,9 @@ func (a Actor) ChangeMultiaddrs(rt Runtime, params *ChangeMultiaddrsParams) *abi
 
 		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
 
+		added, removed := diffMultiaddrs(info.Multiaddrs, params.NewMultiaddrs)
+		rt.Log(rtt.INFO, "changed multiaddrs of miner %v: added %v, removed %v", rt.Receiver(),
+			formatMultiaddrs(added), formatMultiaddrs(removed))
 		info.Multiaddrs = params.NewMultiaddrs
 		err := st.SaveInfo(adt.AsStore(rt), info)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "could not save miner info")
@@ -305,6 +346,44 @@ func (a Actor) ChangeMultiaddrs(rt Runtime, params *ChangeMultiaddrsParams) *abi
 	return nil
 }
 
+// Computes the multiaddrs present only in next (added) and only in prev (removed).
+func diffMultiaddrs(prev, next []abi.Multiaddrs) (added, removed []abi.Multiaddrs) {
+	prevSet := make(map[string]struct{}, len(prev))
+	for _, ma := range prev {
+		prevSet[string(ma)] = struct{}{}
+	}
+	nextSet := make(map[string]struct{}, len(next))
+	for _, ma := range next {
+		nextSet[string(ma)] = struct{}{}
+	}
+
+	for _, ma := range next {
+		if _, ok := prevSet[string(ma)]; !ok {
+			added = append(added, ma)
+		}
+	}
+	for _, ma := range prev {
+		if _, ok := nextSet[string(ma)]; !ok {
+			removed = append(removed, ma)
+		}
+	}
+	return added, removed
+}
+
+// Renders multiaddrs in their textual form. Multiaddrs registered before they were validated may not parse,
+// and are rendered in hex.
+func formatMultiaddrs(mas []abi.Multiaddrs) []string {
+	out := make([]string, len(mas))
+	for i, ma := range mas {
+		if parsed, err := multiaddr.NewMultiaddrBytes(ma); err == nil {
+			out[i] = parsed.String()
+		} else {
+			out[i] = fmt.Sprintf("%x", []byte(ma))
+		}
+	}
+	return out
+}
+
 //////////////////
 // WindowedPoSt //
 //////////////////
@@ -1086,28 +1165,22 @@ func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainIn
 	// a constant number of them.
 
 	activation := rt.CurrEpoch()
+	// Check (and activate) storage deals associated to sectors, in a single call to the market actor.
+	sectorDeals := make([]market.SectorDeals, len(preCommits))
+	for i, precommit := range preCommits {
+		sectorDeals[i] = market.SectorDeals{
+			DealIDs:      precommit.Info.DealIDs,
+			SectorExpiry: precommit.Info.Expiration,
+		}
+	}
+	dealsActivated := batchActivateDeals(rt, sectorDeals)
+
 	// Pre-commits for new sectors.
 	var validPreCommits []*SectorPreCommitOnChainInfo
-	for _, precommit := range preCommits {
-		if len(precommit.Info.DealIDs) > 0 {
-			// Check (and activate) storage deals associated to sector. Abort if checks failed.
-			// TODO: we should batch these calls...
-			// https://github.com/filecoin-project/specs-actors/issues/474
-			code := rt.Send(
-				builtin.StorageMarketActorAddr,
-				builtin.MethodsMarket.ActivateDeals,
-				&market.ActivateDealsParams{
-					DealIDs:      precommit.Info.DealIDs,
-					SectorExpiry: precommit.Info.Expiration,
-				},
-				abi.NewTokenAmount(0),
-				&builtin.Discard{},
-			)
-
-			if code != exitcode.Ok {
-				rt.Log(rtt.INFO, "failed to activate deals on sector %d, dropping from prove commit set", precommit.Info.SectorNumber)
-				continue
-			}
+	for i, precommit := range preCommits {
+		if !dealsActivated[i] {
+			rt.Log(rtt.INFO, "failed to activate deals on sector %d, dropping from prove commit set", precommit.Info.SectorNumber)
+			continue
 		}
 
 		validPreCommits = append(validPreCommits, precommit)
@@ -1222,6 +1295,220 @@ func (a Actor) CheckSectorProven(rt Runtime, params *CheckSectorProvenParams) *a
 	return nil
 }
 
+type DeadlineExpirationsParams struct {
+	Deadline uint64
+}
+
+type DeadlineExpirationsReturn struct {
+	// Sectors scheduled to expire from the deadline at each quantized epoch, in ascending epoch order.
+	Expirations []ExpirationSummary
+}
+
+// Returns the epochs at which sectors assigned to a deadline are scheduled to expire, with the number of
+// sectors and their power and pledge at each epoch. Only the requested deadline's partitions are loaded.
+func (a Actor) DeadlineExpirations(rt Runtime, params *DeadlineExpirationsParams) *DeadlineExpirationsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	if params.Deadline >= WPoStPeriodDeadlines {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid deadline %d of %d", params.Deadline, WPoStPeriodDeadlines)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	deadlines, err := st.LoadDeadlines(store)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+	deadline, err := deadlines.LoadDeadline(store, params.Deadline)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", params.Deadline)
+
+	expirations, err := deadline.ExpirationSummaries(store, st.QuantSpecForDeadline(params.Deadline))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load expirations for deadline %d", params.Deadline)
+
+	return &DeadlineExpirationsReturn{Expirations: expirations}
+}
+
+type SectorHealthReturn struct {
+	// Sectors assigned to deadlines, including terminated sectors not yet compacted away.
+	Total uint64
+	// Live sectors that are proven and not faulty.
+	Active   uint64
+	Unproven uint64
+	Faulty   uint64
+	// Faulty sectors declared as recovering.
+	Recovering uint64
+	Terminated uint64
+	// The power of all live sectors, and of active sectors alone.
+	LivePower   PowerPair
+	ActivePower PowerPair
+}
+
+// Returns the number of the miner's sectors in each status, with their power, summed over all partitions.
+// Partitions of deadlines with no sectors are not loaded.
+func (a Actor) SectorHealth(rt Runtime, _ *abi.EmptyValue) *SectorHealthReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	deadlines, err := st.LoadDeadlines(store)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+	ret := SectorHealthReturn{
+		LivePower:   NewPowerPairZero(),
+		ActivePower: NewPowerPairZero(),
+	}
+	err = deadlines.ForEach(store, func(dlIdx uint64, dl *Deadline) error {
+		if dl.TotalSectors == 0 {
+			return nil
+		}
+		partitions, err := dl.PartitionsArray(store)
+		if err != nil {
+			return xerrors.Errorf("failed to load partitions for deadline %d: %w", dlIdx, err)
+		}
+		var partition Partition
+		return partitions.ForEach(&partition, func(partIdx int64) error {
+			counts := []struct {
+				bf  bitfield.BitField
+				out *uint64
+			}{
+				{partition.Sectors, &ret.Total},
+				{partition.Unproven, &ret.Unproven},
+				{partition.Faults, &ret.Faulty},
+				{partition.Recoveries, &ret.Recovering},
+				{partition.Terminated, &ret.Terminated},
+			}
+			for _, c := range counts {
+				n, err := c.bf.Count()
+				if err != nil {
+					return xerrors.Errorf("failed to count sectors in deadline %d partition %d: %w", dlIdx, partIdx, err)
+				}
+				*c.out += n
+			}
+			active, err := partition.ActiveSectors()
+			if err != nil {
+				return err
+			}
+			n, err := active.Count()
+			if err != nil {
+				return xerrors.Errorf("failed to count active sectors in deadline %d partition %d: %w", dlIdx, partIdx, err)
+			}
+			ret.Active += n
+			ret.LivePower = ret.LivePower.Add(partition.LivePower)
+			ret.ActivePower = ret.ActivePower.Add(partition.ActivePower())
+			return nil
+		})
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to summarise sector health")
+
+	return &ret
+}
+
+type DeadlineSectorsAtRisk struct {
+	Deadline uint64
+	// The epoch at which the deadline next closes, when missed PoSts are detected.
+	Close abi.ChainEpoch
+	// Live, non-faulty sectors in partitions not yet proven for the deadline's current challenge window.
+	Sectors bitfield.BitField
+	Power   PowerPair
+}
+
+type SectorsAtRiskReturn struct {
+	// Deadlines with sectors at risk, in the order in which they next close.
+	Deadlines []DeadlineSectorsAtRisk
+}
+
+// Returns, for each deadline over the next proving period, the sectors that will be detected faulty when
+// the deadline closes if no Window PoSt is submitted for them. Recovering sectors are already faulty and
+// so are not included. Partitions of deadlines with no sectors are not loaded.
+func (a Actor) SectorsAtRisk(rt Runtime, _ *abi.EmptyValue) *SectorsAtRiskReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+	currEpoch := rt.CurrEpoch()
+	currDeadline := st.DeadlineInfo(currEpoch)
+
+	deadlines, err := st.LoadDeadlines(store)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+	ret := SectorsAtRiskReturn{}
+	for i := uint64(0); i < WPoStPeriodDeadlines; i++ {
+		dlIdx := (currDeadline.Index + i) % WPoStPeriodDeadlines
+		dl, err := deadlines.LoadDeadline(store, dlIdx)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+		if dl.TotalSectors == 0 {
+			continue
+		}
+
+		partitions, err := dl.PartitionsArray(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load partitions for deadline %d", dlIdx)
+
+		var atRisk []bitfield.BitField
+		power := NewPowerPairZero()
+		var partition Partition
+		err = partitions.ForEach(&partition, func(partIdx int64) error {
+			proven, err := dl.PartitionsPoSted.IsSet(uint64(partIdx))
+			if err != nil {
+				return xerrors.Errorf("failed to check PoSt of partition %d: %w", partIdx, err)
+			} else if proven {
+				return nil
+			}
+			live, err := partition.LiveSectors()
+			if err != nil {
+				return err
+			}
+			sectors, err := bitfield.SubtractBitField(live, partition.Faults)
+			if err != nil {
+				return xerrors.Errorf("failed to subtract faults from partition %d: %w", partIdx, err)
+			}
+			atRisk = append(atRisk, sectors)
+			power = power.Add(partition.LivePower.Sub(partition.FaultyPower))
+			return nil
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to find sectors at risk in deadline %d", dlIdx)
+
+		sectors, err := bitfield.MultiMerge(atRisk...)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to merge sectors at risk in deadline %d", dlIdx)
+		empty, err := sectors.IsEmpty()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check sectors at risk in deadline %d", dlIdx)
+		if empty {
+			continue
+		}
+
+		info := NewDeadlineInfo(currDeadline.PeriodStart, dlIdx, currEpoch).NextNotElapsed()
+		ret.Deadlines = append(ret.Deadlines, DeadlineSectorsAtRisk{
+			Deadline: dlIdx,
+			Close:    info.Close,
+			Sectors:  sectors,
+			Power:    power,
+		})
+	}
+	return &ret
+}
+
+type AggregateProveCommitBoundsReturn struct {
+	// The minimum and maximum number of sectors that may be proven in a single ProveCommitAggregate.
+	MinSectors uint64
+	MaxSectors uint64
+	// The maximum size in bytes of an aggregate proof.
+	MaxProofSize uint64
+}
+
+// Returns the limits on batch size and proof size enforced by ProveCommitAggregate, so that miners can
+// size batches before constructing an aggregate proof.
+func (a Actor) AggregateProveCommitBounds(rt Runtime, _ *abi.EmptyValue) *AggregateProveCommitBoundsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	return &AggregateProveCommitBoundsReturn{
+		MinSectors:   MinAggregatedSectors,
+		MaxSectors:   MaxAggregatedSectors,
+		MaxProofSize: MaxAggregateProofSize,
+	}
+}
+
 /////////////////////////
 // Sector Modification //
 /////////////////////////
@@ -1635,6 +1922,113 @@ func (a Actor) DeclareFaults(rt Runtime, params *DeclareFaultsParams) *abi.Empty
 	return nil
 }
 
+type DeadlineFaultsResult struct {
+	Deadline uint64
+	// Ok if the deadline's faults were recorded, otherwise the reason they were not.
+	Code exitcode.ExitCode
+	// Power of the sectors newly faulted in the deadline.
+	PowerFaulted PowerPair
+}
+
+type BatchDeclareFaultsReturn struct {
+	// The outcome for each deadline addressed, in ascending deadline order.
+	Deadlines []DeadlineFaultsResult
+	// Total power of the sectors newly faulted in all deadlines.
+	PowerFaulted PowerPair
+}
+
+// Declares faulty sectors across many deadlines, as for DeclareFaults, processing the declarations grouped
+// by deadline. The declarations for each deadline are validated independently: if any is invalid, none of that
+// deadline's faults are recorded, but those of other deadlines may be.
+func (a Actor) BatchDeclareFaults(rt Runtime, params *DeclareFaultsParams) *BatchDeclareFaultsReturn {
+	if len(params.Faults) > DeclarationsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument,
+			"too many fault declarations for a single message: %d > %d",
+			len(params.Faults), DeclarationsMax,
+		)
+	}
+
+	toProcess := make(DeadlineSectorMap)
+	for _, decl := range params.Faults {
+		err := toProcess.Add(decl.Deadline, decl.Partition, decl.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
+			"failed to process deadline %d, partition %d", decl.Deadline, decl.Partition,
+		)
+	}
+	err := toProcess.Check(AddressedPartitionsMax, AddressedSectorsMax)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "cannot process requested parameters")
+
+	store := adt.AsStore(rt)
+	var st State
+	ret := BatchDeclareFaultsReturn{PowerFaulted: NewPowerPairZero()}
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+		deadlines, err := st.LoadDeadlines(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+		sectors, err := LoadSectors(store, st.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+
+		currEpoch := rt.CurrEpoch()
+		err = toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
+			deadlinePowerDelta, err := declareDeadlineFaults(store, &st, deadlines, sectors, info.SectorSize, currEpoch, dlIdx, pm)
+			if err != nil {
+				code := exitcode.Unwrap(err, exitcode.ErrIllegalState)
+				if code == exitcode.ErrIllegalState {
+					return err
+				}
+				rt.Log(rtt.INFO, "failed to declare faults for deadline %d: %s", dlIdx, err)
+				ret.Deadlines = append(ret.Deadlines, DeadlineFaultsResult{Deadline: dlIdx, Code: code, PowerFaulted: NewPowerPairZero()})
+				return nil
+			}
+			ret.Deadlines = append(ret.Deadlines, DeadlineFaultsResult{Deadline: dlIdx, Code: exitcode.Ok, PowerFaulted: deadlinePowerDelta.Neg()})
+			ret.PowerFaulted = ret.PowerFaulted.Sub(deadlinePowerDelta)
+			return nil
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate deadlines")
+
+		err = st.SaveDeadlines(store, deadlines)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+	})
+
+	// Remove power for new faulty sectors.
+	requestUpdatePower(rt, ret.PowerFaulted.Neg())
+
+	// Payment of penalty for declared faults is deferred to the deadline cron.
+	return &ret
+}
+
+// Records the declared faults in a single deadline, returning the (non-positive) change in power.
+// Invalid declarations are reported with an exit code other than ErrIllegalState, and leave the deadlines unchanged.
+func declareDeadlineFaults(store adt.Store, st *State, deadlines *Deadlines, sectors Sectors, sectorSize abi.SectorSize,
+	currEpoch abi.ChainEpoch, dlIdx uint64, pm PartitionSectorMap) (PowerPair, error) {
+	targetDeadline, err := declarationDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch)
+	if err != nil {
+		return PowerPair{}, exitcode.ErrIllegalArgument.Wrapf("invalid fault declaration deadline %d: %w", dlIdx, err)
+	}
+	if err = validateFRDeclarationDeadline(targetDeadline); err != nil {
+		return PowerPair{}, exitcode.ErrIllegalArgument.Wrapf("failed fault declaration at deadline %d: %w", dlIdx, err)
+	}
+
+	deadline, err := deadlines.LoadDeadline(store, dlIdx)
+	if err != nil {
+		return PowerPair{}, exitcode.ErrIllegalState.Wrapf("failed to load deadline %d: %w", dlIdx, err)
+	}
+
+	faultExpirationEpoch := targetDeadline.Last() + FaultMaxAge
+	powerDelta, err := deadline.RecordFaults(store, sectors, sectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, pm)
+	if err != nil {
+		return PowerPair{}, xerrors.Errorf("failed to declare faults for deadline %d: %w", dlIdx, err)
+	}
+
+	if err = deadlines.UpdateDeadline(store, dlIdx, deadline); err != nil {
+		return PowerPair{}, exitcode.ErrIllegalState.Wrapf("failed to store deadline %d partitions: %w", dlIdx, err)
+	}
+	return powerDelta, nil
+}
+
 //type DeclareFaultsRecoveredParams struct {
 //	Recoveries []RecoveryDeclaration
 //}
@@ -1650,6 +2044,10 @@ type DeclareFaultsRecoveredParams = miner0.DeclareFaultsRecoveredParams
 //}
 type RecoveryDeclaration = miner0.RecoveryDeclaration
 
+// Declares faulty sectors as recovered, to be restored to power when next proven.
+// At most DeclaredRecoveriesMax sectors are declared per message. Declarations are processed in order
+// until the next would exceed this; it and all following declarations are deferred, and may be declared
+// in a subsequent message. PreviewDeclareFaultsRecovered reports which declarations would be deferred.
 func (a Actor) DeclareFaultsRecovered(rt Runtime, params *DeclareFaultsRecoveredParams) *abi.EmptyValue {
 	if len(params.Recoveries) > DeclarationsMax {
 		rt.Abortf(exitcode.ErrIllegalArgument,
@@ -1658,14 +2056,20 @@ func (a Actor) DeclareFaultsRecovered(rt Runtime, params *DeclareFaultsRecovered
 		)
 	}
 
+	recoveries, deferred, _, err := capRecoveryDeclarations(params.Recoveries)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to count recovered sectors")
+	if len(recoveries) == 0 && len(deferred) > 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "first recovery declaration exceeds the maximum of %d sectors", DeclaredRecoveriesMax)
+	}
+
 	toProcess := make(DeadlineSectorMap)
-	for _, term := range params.Recoveries {
+	for _, term := range recoveries {
 		err := toProcess.Add(term.Deadline, term.Partition, term.Sectors)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
 			"failed to process deadline %d, partition %d", term.Deadline, term.Partition,
 		)
 	}
-	err := toProcess.Check(AddressedPartitionsMax, AddressedSectorsMax)
+	err = toProcess.Check(AddressedPartitionsMax, AddressedSectorsMax)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "cannot process requested parameters")
 
 	store := adt.AsStore(rt)
@@ -1716,10 +2120,104 @@ func (a Actor) DeclareFaultsRecovered(rt Runtime, params *DeclareFaultsRecovered
 	err = st.CheckBalanceInvariants(rt.CurrentBalance())
 	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
 
+	if len(deferred) > 0 {
+		rt.Log(rtt.INFO, "deferred %d recovery declarations beyond the maximum of %d sectors", len(deferred), DeclaredRecoveriesMax)
+	}
+
 	// Power is not restored yet, but when the recovered sectors are successfully PoSted.
 	return nil
 }
 
+type PreviewDeclareFaultsRecoveredReturn struct {
+	// The outstanding fee debt, which would be repaid and burnt by the declaration.
+	FeeDebt abi.TokenAmount
+	// The fee for the sectors that would be newly declared recovered. Recovering sectors remain faulty until
+	// proven, so this is the continued fault fee charged for them at their deadline if the recovery is not proven.
+	FaultFee abi.TokenAmount
+	// The number of sectors in the declarations that would be processed.
+	DeclaredSectors uint64
+	// Declarations that would be deferred by DeclaredRecoveriesMax, in the order given.
+	Deferred []RecoveryDeclaration
+}
+
+// Previews the fees and deferrals of a DeclareFaultsRecovered message with the same parameters.
+// Sectors not faulty or already recovering are ignored, as they would be by the declaration.
+func (a Actor) PreviewDeclareFaultsRecovered(rt Runtime, params *DeclareFaultsRecoveredParams) *PreviewDeclareFaultsRecoveredReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	recoveries, deferred, declared, err := capRecoveryDeclarations(params.Recoveries)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to count recovered sectors")
+
+	toProcess := make(DeadlineSectorMap)
+	for _, decl := range recoveries {
+		err := toProcess.Add(decl.Deadline, decl.Partition, decl.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument,
+			"failed to process deadline %d, partition %d", decl.Deadline, decl.Partition,
+		)
+	}
+
+	store := adt.AsStore(rt)
+	var st State
+	rt.StateReadonly(&st)
+	info := getMinerInfo(rt, &st)
+
+	deadlines, err := st.LoadDeadlines(store)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+	sectors, err := LoadSectors(store, st.Sectors)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+
+	recoveringPower := NewPowerPairZero()
+	err = toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
+		deadline, err := deadlines.LoadDeadline(store, dlIdx)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to load deadline %d", dlIdx)
+
+		return pm.ForEach(func(partIdx uint64, sectorNos bitfield.BitField) error {
+			partition, err := deadline.LoadPartition(store, partIdx)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to load partition %d:%d", dlIdx, partIdx)
+
+			newRecoveries, err := bitfield.IntersectBitField(sectorNos, partition.Faults)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to intersect recoveries with faults")
+			newRecoveries, err = bitfield.SubtractBitField(newRecoveries, partition.Recoveries)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to subtract existing recoveries")
+
+			recoverySectors, err := sectors.Load(newRecoveries)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load recovery sectors")
+			recoveringPower = recoveringPower.Add(PowerForSectors(info.SectorSize, recoverySectors))
+			return nil
+		})
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to walk sectors")
+
+	rewardStats := requestCurrentEpochBlockReward(rt)
+	pwrTotal := requestCurrentTotalPower(rt)
+	faultFee := PledgePenaltyForContinuedFault(rewardStats.ThisEpochRewardSmoothed, pwrTotal.QualityAdjPowerSmoothed, recoveringPower.QA)
+
+	return &PreviewDeclareFaultsRecoveredReturn{
+		FeeDebt:         st.FeeDebt,
+		FaultFee:        faultFee,
+		DeclaredSectors: declared,
+		Deferred:        deferred,
+	}
+}
+
+// Splits recovery declarations, in order, into those declaring at most DeclaredRecoveriesMax sectors in total
+// and those deferred, returning also the number of sectors accepted.
+func capRecoveryDeclarations(decls []RecoveryDeclaration) ([]RecoveryDeclaration, []RecoveryDeclaration, uint64, error) {
+	total := uint64(0)
+	for i, decl := range decls {
+		count, err := decl.Sectors.Count()
+		if err != nil {
+			return nil, nil, 0, xerrors.Errorf("failed to count sectors for deadline %d, partition %d: %w", decl.Deadline, decl.Partition, err)
+		}
+		if total+count > DeclaredRecoveriesMax {
+			return decls[:i], decls[i:], total, nil
+		}
+		total += count
+	}
+	return decls, nil, total, nil
+}
+
 /////////////////
 // Maintenance //
 /////////////////
@@ -1905,6 +2403,9 @@ func (a Actor) ReportConsensusFault(rt Runtime, params *ReportConsensusFaultPara
 	if fault.Target != rt.Receiver() {
 		rt.Abortf(exitcode.ErrIllegalArgument, "fault by %v reported to miner %v", fault.Target, rt.Receiver())
 	}
+	if !isKnownConsensusFaultType(fault.Type) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "unknown consensus fault type %d", fault.Type)
+	}
 
 	// Elapsed since the fault (i.e. since the higher of the two blocks)
 	currEpoch := rt.CurrEpoch()
@@ -1950,6 +2451,7 @@ func (a Actor) ReportConsensusFault(rt Runtime, params *ReportConsensusFaultPara
 		// reduce burnAmount by rewardAmount
 		burnAmount = big.Sub(burnAmount, rewardAmount)
 		info.ConsensusFaultElapsed = currEpoch + ConsensusFaultIneligibilityDuration
+		info.ConsensusFaultType = fault.Type
 		err = st.SaveInfo(adt.AsStore(rt), info)
 		builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to save miner info")
 	})
@@ -2059,6 +2561,139 @@ func (a Actor) RepayDebt(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 	return nil
 }
 
+type SectorPledgeTopUp struct {
+	Deadline  uint64
+	Partition uint64
+	Sector    abi.SectorNumber
+	// Amount to add to the sector's initial pledge.
+	Amount abi.TokenAmount
+}
+
+type TopUpSectorPledgeParams struct {
+	TopUps []SectorPledgeTopUp
+}
+
+// Adds the attached value to the initial pledge of active sectors, e.g. after the pledge requirement has risen.
+// The value received must equal the sum of the top-up amounts.
+// The increased pledge is recorded in the sector's on-chain info and released with the sector at expiration
+// or termination.
+func (a Actor) TopUpSectorPledge(rt Runtime, params *TopUpSectorPledgeParams) *abi.EmptyValue {
+	builtin.RequireParam(rt, len(params.TopUps) > 0, "no sectors to top up")
+	builtin.RequireParam(rt, len(params.TopUps) <= AddressedSectorsMax, "too many sectors to top up (%d > %d)", len(params.TopUps), AddressedSectorsMax)
+
+	totalTopUp := big.Zero()
+	topUpsByDeadline := map[uint64][]*SectorPledgeTopUp{}
+	var deadlinesToLoad []uint64
+	seen := map[abi.SectorNumber]struct{}{}
+	for i := range params.TopUps {
+		topUp := &params.TopUps[i]
+		builtin.RequireParam(rt, topUp.Amount.GreaterThan(big.Zero()), "top-up for sector %d must be positive, was %v", topUp.Sector, topUp.Amount)
+		builtin.RequireParam(rt, topUp.Deadline < WPoStPeriodDeadlines, "invalid deadline %d", topUp.Deadline)
+		if _, ok := seen[topUp.Sector]; ok {
+			rt.Abortf(exitcode.ErrIllegalArgument, "duplicate top-up for sector %d", topUp.Sector)
+		}
+		seen[topUp.Sector] = struct{}{}
+
+		if _, ok := topUpsByDeadline[topUp.Deadline]; !ok {
+			deadlinesToLoad = append(deadlinesToLoad, topUp.Deadline)
+		}
+		topUpsByDeadline[topUp.Deadline] = append(topUpsByDeadline[topUp.Deadline], topUp)
+		totalTopUp = big.Add(totalTopUp, topUp.Amount)
+	}
+	builtin.RequireParam(rt, rt.ValueReceived().Equals(totalTopUp), "value received %v does not match total top-up %v", rt.ValueReceived(), totalTopUp)
+
+	store := adt.AsStore(rt)
+	pledgeDelta := big.Zero()
+	var st State
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+		currEpoch := rt.CurrEpoch()
+		deadlines, err := st.LoadDeadlines(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+		sectors, err := LoadSectors(store, st.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+
+		newSectors := make([]*SectorOnChainInfo, 0, len(params.TopUps))
+		for _, dlIdx := range deadlinesToLoad {
+			// Partition expiration queues are updated, so deadlines being proven may not be modified.
+			if !deadlineIsMutable(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch) {
+				rt.Abortf(exitcode.ErrIllegalArgument, "cannot top up pledge of sectors in immutable deadline %d", dlIdx)
+			}
+			quant := st.QuantSpecForDeadline(dlIdx)
+
+			deadline, err := deadlines.LoadDeadline(store, dlIdx)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+
+			partitions, err := deadline.PartitionsArray(store)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load partitions for deadline %d", dlIdx)
+
+			for _, topUp := range topUpsByDeadline[dlIdx] {
+				var partition Partition
+				found, err := partitions.Get(topUp.Partition, &partition)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d partition %d", dlIdx, topUp.Partition)
+				if !found {
+					rt.Abortf(exitcode.ErrNotFound, "no such deadline %d partition %d", dlIdx, topUp.Partition)
+				}
+
+				active, err := partition.ActiveSectors()
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute active sectors")
+				isActive, err := active.IsSet(uint64(topUp.Sector))
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check sector %d", topUp.Sector)
+				if !isActive {
+					rt.Abortf(exitcode.ErrIllegalArgument, "sector %d is not active in deadline %d partition %d", topUp.Sector, dlIdx, topUp.Partition)
+				}
+
+				oldSector, err := sectors.MustGet(topUp.Sector)
+				builtin.RequireNoErr(rt, err, exitcode.ErrNotFound, "failed to load sector %d", topUp.Sector)
+
+				newSector := *oldSector
+				newSector.InitialPledge = big.Add(oldSector.InitialPledge, topUp.Amount)
+
+				_, partitionPledgeDelta, err := partition.ReplaceSectors(store,
+					[]*SectorOnChainInfo{oldSector},
+					[]*SectorOnChainInfo{&newSector},
+					info.SectorSize,
+					quant)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to replace sector %d at deadline %d partition %d", topUp.Sector, dlIdx, topUp.Partition)
+				pledgeDelta = big.Add(pledgeDelta, partitionPledgeDelta)
+
+				err = partitions.Set(topUp.Partition, &partition)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %d partition %d", dlIdx, topUp.Partition)
+
+				newSectors = append(newSectors, &newSector)
+			}
+
+			deadline.Partitions, err = partitions.Root()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save partitions for deadline %d", dlIdx)
+
+			err = deadlines.UpdateDeadline(store, dlIdx, deadline)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %d", dlIdx)
+		}
+
+		err = sectors.Store(newSectors...)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update sector infos")
+
+		st.Sectors, err = sectors.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save sectors")
+
+		err = st.SaveDeadlines(store, deadlines)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+
+		builtin.RequirePredicate(rt, pledgeDelta.Equals(totalTopUp), exitcode.ErrIllegalState, "pledge delta %v does not match total top-up %v", pledgeDelta, totalTopUp)
+		err = st.AddInitialPledge(pledgeDelta)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add initial pledge")
+	})
+
+	notifyPledgeChanged(rt, pledgeDelta)
+	err := st.CheckBalanceInvariants(rt.CurrentBalance())
+	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
+
+	return nil
+}
+
 type ReplicaUpdate = miner7.ReplicaUpdate
 
 type ProveReplicaUpdatesParams = miner7.ProveReplicaUpdatesParams
@@ -2089,6 +2724,8 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 	var sectorsDeals []market.SectorDeals
 	var sectorsDataSpec []*market.SectorDataSpec
 	var validatedUpdates []*updateAndSectorInfo
+	var candidateUpdates []*updateAndSectorInfo
+	var candidateDeals []market.SectorDeals
 	sectorNumbers := bitfield.New()
 	for i := range params.Updates {
 		update := params.Updates[i]
@@ -2159,31 +2796,26 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 			continue
 		}
 
-		code := rt.Send(
-			builtin.StorageMarketActorAddr,
-			builtin.MethodsMarket.ActivateDeals,
-			&market.ActivateDealsParams{
-				DealIDs:      update.Deals,
-				SectorExpiry: sectorInfo.Expiration,
-			},
-			abi.NewTokenAmount(0),
-			&builtin.Discard{},
-		)
-
-		if code != exitcode.Ok {
-			rt.Log(rtt.INFO, "failed to activate deals, skipping sector %d", update.SectorID)
-			continue
-		}
-
-		validatedUpdates = append(validatedUpdates, &updateAndSectorInfo{
+		candidateUpdates = append(candidateUpdates, &updateAndSectorInfo{
 			update:     &update,
 			sectorInfo: sectorInfo,
 		})
+		candidateDeals = append(candidateDeals, market.SectorDeals{DealIDs: update.Deals, SectorExpiry: sectorInfo.Expiration})
+	}
 
-		sectorsDeals = append(sectorsDeals, market.SectorDeals{DealIDs: update.Deals, SectorExpiry: sectorInfo.Expiration})
+	// Activate the deals of all candidate updates in a single call to the market actor.
+	dealsActivated := batchActivateDeals(rt, candidateDeals)
+	for i, candidate := range candidateUpdates {
+		if !dealsActivated[i] {
+			rt.Log(rtt.INFO, "failed to activate deals, skipping sector %d", candidate.update.SectorID)
+			continue
+		}
+
+		validatedUpdates = append(validatedUpdates, candidate)
+		sectorsDeals = append(sectorsDeals, candidateDeals[i])
 		sectorsDataSpec = append(sectorsDataSpec, &market.SectorDataSpec{
-			SectorType: sectorInfo.SealProof,
-			DealIDs:    update.Deals,
+			SectorType: candidate.sectorInfo.SealProof,
+			DealIDs:    candidate.update.Deals,
 		})
 	}
 
@@ -2453,6 +3085,10 @@ func processEarlyTerminations(rt Runtime, rewardSmoothed smoothing.FilterEstimat
 		})
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to process terminations")
 
+		// Report the amounts so operators can reconcile the change in balance.
+		rt.Log(rtt.INFO, "storage provider %s terminated %d sectors: released pledge %s, charged termination fee %s",
+			rt.Receiver(), result.SectorsProcessed, totalInitialPledge, penalty)
+
 		// Pay penalty
 		err = st.ApplyPenalty(penalty)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to apply penalty")
@@ -2507,6 +3143,9 @@ func handleProvingDeadline(rt Runtime,
 	var continueCron bool
 	var st State
 	rt.StateTransaction(&st, func() {
+		// The penalties applied by this deadline, and the fee debt before them, for reporting how they were paid.
+		penaltyApplied := big.Zero()
+		debtBefore := st.FeeDebt
 		{
 			// Vest locked funds.
 			// This happens first so that any subsequent penalties are taken
@@ -2523,12 +3162,19 @@ func handleProvingDeadline(rt Runtime,
 		}
 
 		{
-			depositToBurn, err := st.CleanUpExpiredPreCommits(store, currEpoch)
+			depositToBurn, expired, err := st.CleanUpExpiredPreCommits(store, currEpoch)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to expire pre-committed sectors")
 
 			err = st.ApplyPenalty(depositToBurn)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to apply penalty")
 			rt.Log(rtt.DEBUG, "storage provider %s penalized %s for expired pre commits", rt.Receiver(), depositToBurn)
+			penaltyApplied = depositToBurn
+			if !depositToBurn.IsZero() {
+				expiredNos, err := expired.All(AddressedSectorsMax)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to expand expired pre-commits")
+				rt.Log(rtt.INFO, "storage provider %s burnt pre-commit deposit %s for expired sectors %v",
+					rt.Receiver(), depositToBurn, expiredNos)
+			}
 		}
 
 		// Record whether or not we _had_ early terminations in the queue before this method.
@@ -2558,6 +3204,15 @@ func handleProvingDeadline(rt Runtime,
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unlock penalty")
 			penaltyTotal = big.Add(penaltyFromVesting, penaltyFromBalance)
 			pledgeDeltaTotal = big.Sub(pledgeDeltaTotal, penaltyFromVesting)
+
+			// The penalty applied is paid from vesting funds and balance, with any shortfall added to fee debt.
+			// Payment may also repay prior fee debt, in which case the debt added is negative.
+			penaltyApplied = big.Add(penaltyApplied, penaltyTarget)
+			addedToDebt := big.Sub(st.FeeDebt, debtBefore)
+			if !penaltyApplied.IsZero() || !addedToDebt.IsZero() {
+				rt.Log(rtt.INFO, "storage provider %s applied deadline penalty %s: paid %s from vesting funds, %s from balance, added %s to fee debt",
+					rt.Receiver(), penaltyApplied, penaltyFromVesting, penaltyFromBalance, addedToDebt)
+			}
 		}
 
 		continueCron = st.ContinueDeadlineCron()
@@ -2660,9 +3315,11 @@ func requestUpdatePower(rt Runtime, delta PowerPair) {
 	builtin.RequireSuccess(rt, code, "failed to update power with %v", delta)
 }
 
+// Notifies the market actor of terminated deals in batches of at most DealTerminationBatchSize.
+// Failure of any batch aborts the whole message, so the market never observes a partial termination.
 func requestTerminateDeals(rt Runtime, epoch abi.ChainEpoch, dealIDs []abi.DealID) {
 	for len(dealIDs) > 0 {
-		size := min64(cbg.MaxLength, uint64(len(dealIDs)))
+		size := min64(DealTerminationBatchSize, uint64(len(dealIDs)))
 		code := rt.Send(
 			builtin.StorageMarketActorAddr,
 			builtin.MethodsMarket.OnMinerSectorsTerminate,
@@ -2803,6 +3460,52 @@ func requestUnsealedSectorCIDs(rt Runtime, dataCommitmentInputs ...*market.Secto
 	return unsealedCIDs
 }
 
+// Activates the deals of each of a number of sectors, returning whether each sector's deals were activated.
+// Sectors without deals are trivially activated without calling the market actor.
+func batchActivateDeals(rt Runtime, sectors []market.SectorDeals) []bool {
+	activated := make([]bool, len(sectors))
+	var withDeals []market.SectorDeals
+	var withDealsIdx []int
+	for i, sector := range sectors {
+		if len(sector.DealIDs) == 0 {
+			activated[i] = true
+			continue
+		}
+		withDeals = append(withDeals, sector)
+		withDealsIdx = append(withDealsIdx, i)
+	}
+
+	for start := 0; start < len(withDeals); start += market.BatchActivateDealsMaxSectors {
+		end := start + market.BatchActivateDealsMaxSectors
+		if end > len(withDeals) {
+			end = len(withDeals)
+		}
+
+		var ret market.BatchActivateDealsReturn
+		code := rt.Send(
+			builtin.StorageMarketActorAddr,
+			builtin.MethodsMarket.BatchActivateDeals,
+			&market.BatchActivateDealsParams{Sectors: withDeals[start:end]},
+			abi.NewTokenAmount(0),
+			&ret,
+		)
+		if code != exitcode.Ok {
+			rt.Log(rtt.WARN, "failed to activate deals for %d sectors: %v", end-start, code)
+			continue
+		}
+
+		err := ret.Activated.ForEach(func(i uint64) error {
+			if i >= uint64(end-start) {
+				return xerrors.Errorf("activated sector index %d out of range %d", i, end-start)
+			}
+			activated[withDealsIdx[start+int(i)]] = true
+			return nil
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to read activated sectors")
+	}
+	return activated
+}
+
 func requestDealWeights(rt Runtime, sectors []market.SectorDeals) *market.VerifyDealsForActivationReturn {
 	// Short-circuit if there are no deals in any of the sectors.
 	dealCount := 0
@@ -3036,6 +3739,14 @@ func PowerForSectors(ssize abi.SectorSize, sectors []*SectorOnChainInfo) PowerPa
 	}
 }
 
+func isKnownConsensusFaultType(faultType runtime.ConsensusFaultType) bool {
+	switch faultType {
+	case runtime.ConsensusFaultDoubleForkMining, runtime.ConsensusFaultParentGrinding, runtime.ConsensusFaultTimeOffsetMining:
+		return true
+	}
+	return false
+}
+
 func ConsensusFaultActive(info *MinerInfo, currEpoch abi.ChainEpoch) bool {
 	// For penalization period to last for exactly finality epochs
 	// consensus faults are active until currEpoch exceeds ConsensusFaultElapsed
@@ -3088,13 +3799,19 @@ func checkPeerInfo(rt Runtime, peerID abi.PeerID, multiaddrs []abi.Multiaddrs) {
 	}
 
 	totalSize := 0
-	for _, ma := range multiaddrs {
+	for i, ma := range multiaddrs {
 		if len(ma) == 0 {
 			rt.Abortf(exitcode.ErrIllegalArgument, "invalid empty multiaddr")
 		}
+		if len(ma) > MaxMultiaddrSize {
+			rt.Abortf(exitcode.ErrIllegalArgument, "multiaddr %d length of %d exceeds maximum of %d", i, len(ma), MaxMultiaddrSize)
+		}
 		totalSize += len(ma)
 	}
 	if totalSize > MaxMultiaddrData {
 		rt.Abortf(exitcode.ErrIllegalArgument, "multiaddr size of %d exceeds maximum of %d", totalSize, MaxMultiaddrData)
 	}
+	if len(multiaddrs) > MaxMultiaddrCount {
+		rt.Abortf(exitcode.ErrIllegalArgument, "multiaddr count of %d exceeds maximum of %d", len(multiaddrs), MaxMultiaddrCount)
+	}
 }
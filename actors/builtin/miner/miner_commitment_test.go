@@ -889,7 +889,7 @@ func TestProveCommit(t *testing.T) {
 		// Set the right epoch for all following tests
 		rt.SetEpoch(precommitEpoch + miner.PreCommitChallengeDelay + 1)
 
-		// Invalid deals (market ActivateDeals aborts)
+		// Invalid deals (market BatchActivateDeals skips the sector)
 		verifyDealsExit := map[abi.SectorNumber]exitcode.ExitCode{
 			precommit.Info.SectorNumber: exitcode.ErrIllegalArgument,
 		}
@@ -1220,6 +1220,57 @@ func TestAggregateProveCommit(t *testing.T) {
 		assert.Equal(t, tenSectorsInitialPledge, st.InitialPledge)
 
 	})
+
+	t.Run("reported bounds match those enforced", func(t *testing.T) {
+		actor := newHarness(t, periodOffset)
+		rt := builderForHarness(actor).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		rt.SetEpoch(periodOffset + 1)
+		actor.constructAndVerify(rt)
+
+		bounds := actor.aggregateProveCommitBounds(rt)
+		assert.Equal(t, uint64(miner.MinAggregatedSectors), bounds.MinSectors)
+		assert.Equal(t, uint64(miner.MaxAggregatedSectors), bounds.MaxSectors)
+		assert.Equal(t, uint64(miner.MaxAggregateProofSize), bounds.MaxProofSize)
+
+		sectorRange := func(n uint64) bitfield.BitField {
+			sectorNos := make([]uint64, n)
+			for i := range sectorNos {
+				sectorNos[i] = uint64(i)
+			}
+			return bitfield.NewFromSet(sectorNos)
+		}
+
+		// Batch and proof sizes are validated before anything else, so out-of-bounds params abort
+		// regardless of the sectors addressed.
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too few sectors addressed", func() {
+			rt.Call(actor.a.ProveCommitAggregate, makeProveCommitAggregate(sectorRange(bounds.MinSectors-1)))
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too many sectors addressed", func() {
+			rt.Call(actor.a.ProveCommitAggregate, makeProveCommitAggregate(sectorRange(bounds.MaxSectors+1)))
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "exceeds max size", func() {
+			rt.Call(actor.a.ProveCommitAggregate, &miner.ProveCommitAggregateParams{
+				SectorNumbers:  sectorRange(bounds.MaxSectors),
+				AggregateProof: make([]byte, bounds.MaxProofSize+1),
+			})
+		})
+
+		// Params at the bounds pass validation, failing later because the sectors are not precommitted.
+		for _, count := range []uint64{bounds.MinSectors, bounds.MaxSectors} {
+			rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+			rt.ExpectAbortContainsMessage(exitcode.ErrNotFound, "failed to get precommits", func() {
+				rt.Call(actor.a.ProveCommitAggregate, &miner.ProveCommitAggregateParams{
+					SectorNumbers:  sectorRange(count),
+					AggregateProof: make([]byte, bounds.MaxProofSize),
+				})
+			})
+			rt.Reset()
+		}
+		actor.checkState(rt)
+	})
 }
 
 func TestBatchMethodNetworkFees(t *testing.T) {
@@ -15,6 +15,7 @@ import (
 	xerrors "golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 )
 
@@ -123,6 +124,10 @@ type MinerInfo struct {
 	// and winning block elections as a result of being reported for a consensus fault.
 	ConsensusFaultElapsed abi.ChainEpoch
 
+	// The type of the most recent consensus fault reported against this miner,
+	// or zero if no fault has been reported.
+	ConsensusFaultType runtime.ConsensusFaultType
+
 	// A proposed new owner account for this miner.
 	// Must be confirmed by a message from the pending address itself.
 	PendingOwnerAddress *addr.Address
@@ -1022,24 +1027,25 @@ func (st *State) AddPreCommitCleanUps(store adt.Store, cleanUpEvents map[abi.Cha
 	return nil
 }
 
-func (st *State) CleanUpExpiredPreCommits(store adt.Store, currEpoch abi.ChainEpoch) (depositToBurn abi.TokenAmount, err error) {
+func (st *State) CleanUpExpiredPreCommits(store adt.Store, currEpoch abi.ChainEpoch) (depositToBurn abi.TokenAmount, cleanedUp bitfield.BitField, err error) {
 	depositToBurn = abi.NewTokenAmount(0)
+	cleanedUp = bitfield.New()
 
 	// cleanup expired pre-committed sectors
 	cleanUpQ, err := LoadBitfieldQueue(store, st.PreCommittedSectorsCleanUp, st.QuantSpecEveryDeadline(), PrecommitCleanUpAmtBitwidth)
 	if err != nil {
-		return depositToBurn, xerrors.Errorf("failed to load sector expiry queue: %w", err)
+		return depositToBurn, cleanedUp, xerrors.Errorf("failed to load sector expiry queue: %w", err)
 	}
 
 	sectors, modified, err := cleanUpQ.PopUntil(currEpoch)
 	if err != nil {
-		return depositToBurn, xerrors.Errorf("failed to pop expired sectors: %w", err)
+		return depositToBurn, cleanedUp, xerrors.Errorf("failed to pop expired sectors: %w", err)
 	}
 
 	if modified {
 		st.PreCommittedSectorsCleanUp, err = cleanUpQ.Root()
 		if err != nil {
-			return depositToBurn, xerrors.Errorf("failed to save pre commit clean up queue: %w", err)
+			return depositToBurn, cleanedUp, xerrors.Errorf("failed to save pre commit clean up queue: %w", err)
 		}
 	}
 
@@ -1062,23 +1068,27 @@ func (st *State) CleanUpExpiredPreCommits(store adt.Store, currEpoch abi.ChainEp
 		depositToBurn = big.Add(depositToBurn, sector.PreCommitDeposit)
 		return nil
 	}); err != nil {
-		return big.Zero(), xerrors.Errorf("failed to check pre-commit expiries: %w", err)
+		return big.Zero(), cleanedUp, xerrors.Errorf("failed to check pre-commit expiries: %w", err)
 	}
 
 	// Actually delete it.
 	if len(precommitsToDelete) > 0 {
 		if err := st.DeletePrecommittedSectors(store, precommitsToDelete...); err != nil {
-			return big.Zero(), fmt.Errorf("failed to delete pre-commits: %w", err)
+			return big.Zero(), cleanedUp, fmt.Errorf("failed to delete pre-commits: %w", err)
+		}
+		cleanedUp = bitfield.New()
+		for _, sectorNo := range precommitsToDelete {
+			cleanedUp.Set(uint64(sectorNo))
 		}
 	}
 
 	st.PreCommitDeposits = big.Sub(st.PreCommitDeposits, depositToBurn)
 	if st.PreCommitDeposits.LessThan(big.Zero()) {
-		return big.Zero(), xerrors.Errorf("pre-commit clean up caused negative deposits: %v", st.PreCommitDeposits)
+		return big.Zero(), cleanedUp, xerrors.Errorf("pre-commit clean up caused negative deposits: %v", st.PreCommitDeposits)
 	}
 
 	// This deposit was locked separately to pledge collateral so there's no pledge change here.
-	return depositToBurn, nil
+	return depositToBurn, cleanedUp, nil
 }
 
 type AdvanceDeadlineResult struct {
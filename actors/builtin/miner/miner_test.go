@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
@@ -17,6 +18,7 @@ import (
 	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
 	cid "github.com/ipfs/go-cid"
 	"github.com/minio/blake2b-simd"
+	multiaddr "github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	cbg "github.com/whyrusleeping/cbor-gen"
@@ -315,7 +317,7 @@ func TestPeerInfo(t *testing.T) {
 		rt := builder.Build(t)
 		h.constructAndVerify(rt)
 
-		h.setMultiaddrs(rt, abi.Multiaddrs("imanewminer"))
+		h.setMultiaddrs(rt, mustMultiaddr(t, "/ip4/1.2.3.4/tcp/1234"))
 		h.checkState(rt)
 	})
 
@@ -323,7 +325,7 @@ func TestPeerInfo(t *testing.T) {
 		rt := builder.Build(t)
 		h.constructAndVerify(rt)
 
-		h.setMultiaddrs(rt, abi.Multiaddrs("imanewminer"), abi.Multiaddrs("ihavemany"))
+		h.setMultiaddrs(rt, mustMultiaddr(t, "/ip4/1.2.3.4/tcp/1234"), mustMultiaddr(t, "/dns4/miner.example.com/tcp/5678"))
 		h.checkState(rt)
 	})
 
@@ -359,6 +361,79 @@ func TestPeerInfo(t *testing.T) {
 		})
 		h.checkState(rt)
 	})
+
+	t.Run("can't set oversized multiaddr", func(t *testing.T) {
+		rt := builder.Build(t)
+		h.constructAndVerify(rt)
+
+		large := make([]byte, miner.MaxMultiaddrSize+1)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "multiaddr 1 length", func() {
+			h.setMultiaddrs(rt, mustMultiaddr(t, "/ip4/1.2.3.4/tcp/1234"), large)
+		})
+		h.checkState(rt)
+	})
+
+	t.Run("can't set too many multiaddrs", func(t *testing.T) {
+		rt := builder.Build(t)
+		h.constructAndVerify(rt)
+
+		maddrs := make([]abi.Multiaddrs, miner.MaxMultiaddrCount+1)
+		for i := range maddrs {
+			maddrs[i] = []byte{byte(i)}
+		}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "multiaddr count", func() {
+			h.setMultiaddrs(rt, maddrs...)
+		})
+		h.checkState(rt)
+	})
+
+	t.Run("can't set malformed multiaddr", func(t *testing.T) {
+		valid := mustMultiaddr(t, "/ip4/1.2.3.4/tcp/1234")
+		for _, tc := range []struct {
+			name string
+			ma   abi.Multiaddrs
+		}{
+			{"unterminated protocol code", []byte{0x80, 0x80, 0x80}},
+			{"unknown protocol code", []byte{0x7f}},
+			{"truncated component", valid[:len(valid)-1]},
+			{"trailing bytes", append(append([]byte{}, valid...), 0x06)},
+			{"not a multiaddr", abi.Multiaddrs("imanewminer")},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				rt := builder.Build(t)
+				h.constructAndVerify(rt)
+
+				rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "malformed multiaddr 1", func() {
+					h.setMultiaddrs(rt, valid, tc.ma)
+				})
+				h.checkState(rt)
+			})
+		}
+	})
+
+	t.Run("logs added and removed multiaddrs", func(t *testing.T) {
+		rt := builder.Build(t)
+		h.constructAndVerify(rt)
+
+		a, b, c := mustMultiaddr(t, "/ip4/1.1.1.1/tcp/1"), mustMultiaddr(t, "/ip4/2.2.2.2/tcp/2"), mustMultiaddr(t, "/ip6/::1/udp/3")
+		// Multiaddrs registered before they were validated are logged in hex.
+		st := getState(rt)
+		info, err := st.GetInfo(adt.AsStore(rt))
+		require.NoError(t, err)
+		info.Multiaddrs = testMultiaddrs
+		require.NoError(t, st.SaveInfo(adt.AsStore(rt), info))
+		rt.ReplaceState(st)
+
+		h.setMultiaddrs(rt, a, b)
+		rt.ExpectLogsContain(fmt.Sprintf("added [/ip4/1.1.1.1/tcp/1 /ip4/2.2.2.2/tcp/2], removed [%x %x]", testMultiaddrs[0], testMultiaddrs[1]))
+
+		h.setMultiaddrs(rt, b, c)
+		rt.ExpectLogsContain("added [/ip6/::1/udp/3], removed [/ip4/1.1.1.1/tcp/1]")
+
+		h.setMultiaddrs(rt)
+		rt.ExpectLogsContain("added [], removed [/ip4/2.2.2.2/tcp/2 /ip6/::1/udp/3]")
+		h.checkState(rt)
+	})
 }
 
 // Tests for fetching and manipulating miner addresses.
@@ -378,6 +453,76 @@ func TestControlAddresses(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("addresses reflect worker and control address changes", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		ret := actor.minerAddresses(rt)
+		assert.Equal(t, actor.owner, ret.Owner)
+		assert.Equal(t, actor.worker, ret.Worker)
+		assert.Nil(t, ret.PendingWorkerKey)
+		assert.Equal(t, actor.controlAddrs, ret.ControlAddrs)
+
+		newWorker := tutil.NewIDAddr(t, 999)
+		c1 := tutil.NewIDAddr(t, 5001)
+		rt.SetAddressActorType(c1, builtin.AccountActorCodeID)
+
+		currentEpoch := abi.ChainEpoch(5)
+		rt.SetEpoch(currentEpoch)
+		effectiveEpoch := currentEpoch + miner.WorkerKeyChangeDelay
+		actor.changeWorkerAddress(rt, newWorker, effectiveEpoch, []addr.Address{c1})
+
+		// The control addresses change immediately, while the worker change is pending.
+		ret = actor.minerAddresses(rt)
+		assert.Equal(t, actor.worker, ret.Worker)
+		require.NotNil(t, ret.PendingWorkerKey)
+		assert.Equal(t, newWorker, ret.PendingWorkerKey.NewWorker)
+		assert.Equal(t, effectiveEpoch, ret.PendingWorkerKey.EffectiveAt)
+		assert.Equal(t, []addr.Address{c1}, ret.ControlAddrs)
+
+		rt.SetEpoch(effectiveEpoch)
+		actor.confirmUpdateWorkerKey(rt)
+
+		ret = actor.minerAddresses(rt)
+		assert.Equal(t, actor.owner, ret.Owner)
+		assert.Equal(t, newWorker, ret.Worker)
+		assert.Nil(t, ret.PendingWorkerKey)
+		assert.Equal(t, []addr.Address{c1}, ret.ControlAddrs)
+		actor.checkState(rt)
+	})
+
+	// MinerAddresses reports the pending worker key change and the epoch it takes effect.
+	t.Run("addresses report pending worker key until it takes effect", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		newWorker := tutil.NewIDAddr(t, 999)
+		currentEpoch := abi.ChainEpoch(5)
+		rt.SetEpoch(currentEpoch)
+		effectiveEpoch := currentEpoch + miner.WorkerKeyChangeDelay
+		actor.changeWorkerAddress(rt, newWorker, effectiveEpoch, actor.controlAddrs)
+
+		expected := &miner.WorkerKeyChange{NewWorker: newWorker, EffectiveAt: effectiveEpoch}
+		assert.Equal(t, expected, actor.minerAddresses(rt).PendingWorkerKey)
+
+		// Requesting another worker while a change is pending does not reschedule it.
+		rt.SetEpoch(effectiveEpoch - 1)
+		actor.changeWorkerAddress(rt, tutil.NewIDAddr(t, 1000), effectiveEpoch, actor.controlAddrs)
+		assert.Equal(t, expected, actor.minerAddresses(rt).PendingWorkerKey)
+
+		// Confirming before the effective epoch leaves the change pending.
+		actor.confirmUpdateWorkerKey(rt)
+		ret := actor.minerAddresses(rt)
+		assert.Equal(t, actor.worker, ret.Worker)
+		assert.Equal(t, expected, ret.PendingWorkerKey)
+
+		rt.SetEpoch(effectiveEpoch)
+		actor.confirmUpdateWorkerKey(rt)
+		ret = actor.minerAddresses(rt)
+		assert.Equal(t, newWorker, ret.Worker)
+		assert.Nil(t, ret.PendingWorkerKey)
+		actor.checkState(rt)
+	})
 }
 
 func TestWindowPost(t *testing.T) {
@@ -1492,6 +1637,80 @@ func TestDeadlineCron(t *testing.T) {
 		})
 		actor.checkState(rt)
 	})
+
+	t.Run("reports penalty exceeding balance as fee debt", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		oneSector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, oneSector...)
+		actor.declareFaults(rt, oneSector...)
+
+		st := getState(rt)
+		dlIdx, _, err := st.FindSector(rt.AdtStore(), oneSector[0].SectorNumber)
+		require.NoError(t, err)
+		dlinfo := advanceToDeadline(rt, actor, dlIdx)
+
+		// Unlocked balance covers only half the continued fault fee, the rest becomes fee debt.
+		ongoingPwr := miner.PowerForSectors(actor.sectorSize, oneSector)
+		ff := miner.PledgePenaltyForContinuedFault(actor.epochRewardSmooth, actor.epochQAPowerSmooth, ongoingPwr.QA)
+		paid := big.Div(ff, big.NewInt(2))
+		st = getState(rt)
+		require.True(t, st.LockedFunds.IsZero())
+		rt.SetBalance(big.Sum(st.PreCommitDeposits, st.InitialPledge, paid))
+
+		rt.SetEpoch(dlinfo.Last())
+		actor.onDeadlineCron(rt, &cronConfig{
+			expectedEnrollment:     dlinfo.Last() + miner.WPoStChallengeWindow,
+			continuedFaultsPenalty: paid,
+			penaltyFromUnlocked:    paid,
+		})
+		addedToDebt := big.Sub(ff, paid)
+		rt.ExpectLogsContain(fmt.Sprintf("applied deadline penalty %s: paid 0 from vesting funds, %s from balance, added %s to fee debt",
+			ff, paid, addedToDebt))
+
+		st = getState(rt)
+		assert.Equal(t, addedToDebt, st.FeeDebt)
+		actor.checkState(rt)
+	})
+
+	t.Run("reports deposit burnt and sectors cleaned up for expired pre-commits", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		precommitEpoch := periodOffset + 1
+		rt.SetEpoch(precommitEpoch)
+		dlinfo := actor.deadline(rt)
+		expiration := dlinfo.PeriodEnd() + defaultSectorExpiration*miner.WPoStProvingPeriod
+		pc1 := actor.preCommitSector(rt, actor.makePreCommit(100, precommitEpoch-1, expiration, nil), preCommitConf{}, true)
+		pc2 := actor.preCommitSector(rt, actor.makePreCommit(101, precommitEpoch-1, expiration, nil), preCommitConf{}, false)
+
+		// Deposits are burnt at the end of the first deadline opening after the clean up epoch.
+		// Until then, no deadline cron burns any deposit.
+		cleanUpEpoch := precommitEpoch + miner.MaxProveCommitDuration[actor.sealProofType] + miner.ExpiredPreCommitCleanUpDelay
+		for dlinfo.Open <= cleanUpEpoch {
+			dlinfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+
+		deposits := big.Add(pc1.PreCommitDeposit, pc2.PreCommitDeposit)
+		require.Equal(t, deposits, getState(rt).PreCommitDeposits)
+		rt.SetEpoch(dlinfo.Last())
+		actor.onDeadlineCron(rt, &cronConfig{
+			noEnrollment:            true,
+			expiredPrecommitPenalty: deposits,
+		})
+		rt.ExpectLogsContain(fmt.Sprintf("burnt pre-commit deposit %s for expired sectors [100 101]", deposits))
+		rt.ExpectLogsContain(fmt.Sprintf("applied deadline penalty %s: paid 0 from vesting funds, %s from balance, added 0 to fee debt",
+			deposits, deposits))
+
+		st := getState(rt)
+		assert.True(t, st.PreCommitDeposits.IsZero())
+		for _, sectorNo := range []abi.SectorNumber{100, 101} {
+			_, found, err := st.GetPrecommittedSector(rt.AdtStore(), sectorNo)
+			require.NoError(t, err)
+			assert.False(t, found)
+		}
+		actor.checkState(rt)
+	})
 }
 
 // cronControl is a convenience harness on top of the actor harness giving the caller access to common
@@ -1771,6 +1990,90 @@ func TestDeclareRecoveries(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("declarations beyond the cap are deferred", func(t *testing.T) {
+		defer func(prev uint64) { miner.DeclaredRecoveriesMax = prev }(miner.DeclaredRecoveriesMax)
+		miner.DeclaredRecoveriesMax = 2
+
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sectors := actor.commitAndProveSectors(rt, 3, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+		actor.declareFaults(rt, sectors...)
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sectors[0].SectorNumber)
+		require.NoError(t, err)
+		var decls []miner.RecoveryDeclaration
+		for _, sector := range sectors {
+			decls = append(decls, miner.RecoveryDeclaration{Deadline: dlIdx, Partition: pIdx, Sectors: bf(uint64(sector.SectorNumber))})
+		}
+
+		faultFee := func(sectors ...*miner.SectorOnChainInfo) abi.TokenAmount {
+			return miner.PledgePenaltyForContinuedFault(actor.epochRewardSmooth, actor.epochQAPowerSmooth,
+				miner.PowerForSectors(actor.sectorSize, sectors).QA)
+		}
+
+		// Declarations at the cap are all processed.
+		atCap := &miner.DeclareFaultsRecoveredParams{Recoveries: decls[:2]}
+		preview := actor.previewDeclareFaultsRecovered(rt, atCap)
+		assert.Equal(t, big.Zero(), preview.FeeDebt)
+		assert.Equal(t, faultFee(sectors[:2]...), preview.FaultFee)
+		assert.Equal(t, uint64(2), preview.DeclaredSectors)
+		assert.Empty(t, preview.Deferred)
+
+		// Beyond the cap, the remaining declarations are deferred.
+		beyondCap := &miner.DeclareFaultsRecoveredParams{Recoveries: decls}
+		preview = actor.previewDeclareFaultsRecovered(rt, beyondCap)
+		assert.Equal(t, faultFee(sectors[:2]...), preview.FaultFee)
+		assert.Equal(t, uint64(2), preview.DeclaredSectors)
+		assert.Equal(t, decls[2:], preview.Deferred)
+
+		actor.declareRecoveriesWithParams(rt, beyondCap, big.Zero())
+		rt.ExpectLogsContain("deferred 1 recovery declarations beyond the maximum of 2 sectors")
+
+		dl := actor.getDeadline(rt, dlIdx)
+		p, err := dl.LoadPartition(rt.AdtStore(), pIdx)
+		require.NoError(t, err)
+		assertBitfieldEquals(t, p.Recoveries, uint64(sectors[0].SectorNumber), uint64(sectors[1].SectorNumber))
+
+		// Sectors already recovering incur no further fee.
+		preview = actor.previewDeclareFaultsRecovered(rt, atCap)
+		assert.Equal(t, big.Zero(), preview.FaultFee)
+
+		// The deferred declaration succeeds in a subsequent message.
+		deferred := &miner.DeclareFaultsRecoveredParams{Recoveries: decls[2:]}
+		preview = actor.previewDeclareFaultsRecovered(rt, deferred)
+		assert.Equal(t, faultFee(sectors[2]), preview.FaultFee)
+		assert.Empty(t, preview.Deferred)
+
+		actor.declareRecoveriesWithParams(rt, deferred, big.Zero())
+		dl = actor.getDeadline(rt, dlIdx)
+		p, err = dl.LoadPartition(rt.AdtStore(), pIdx)
+		require.NoError(t, err)
+		assert.Equal(t, p.Faults, p.Recoveries)
+		actor.checkState(rt)
+	})
+
+	t.Run("declaration larger than the cap is rejected", func(t *testing.T) {
+		defer func(prev uint64) { miner.DeclaredRecoveriesMax = prev }(miner.DeclaredRecoveriesMax)
+		miner.DeclaredRecoveriesMax = 1
+
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sectors := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+		actor.declareFaults(rt, sectors...)
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sectors[0].SectorNumber)
+		require.NoError(t, err)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "exceeds the maximum", func() {
+			actor.declareRecoveries(rt, dlIdx, pIdx, bf(uint64(sectors[0].SectorNumber), uint64(sectors[1].SectorNumber)), big.Zero())
+		})
+		rt.Reset()
+		actor.checkState(rt)
+	})
+
 	t.Run("recovery fails during active consensus fault", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -2163,6 +2466,45 @@ func TestTerminateSectors(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("reports pledge released and penalty charged", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectors := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+
+		// The harness expects the fee to be paid from locked funds.
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		st := getState(rt)
+		pledgeBefore := st.InitialPledge
+		balanceBefore := rt.Balance()
+
+		expectedFee := big.Zero()
+		lockedPledge := big.Zero()
+		for _, sector := range sectors {
+			sectorPower := miner.QAPowerForSector(actor.sectorSize, sector)
+			dayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, builtin.EpochsInDay)
+			twentyDayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, miner.InitialPledgeProjectionPeriod)
+			sectorAge := rt.Epoch() - sector.Activation
+			expectedFee = big.Add(expectedFee, miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, actor.epochQAPowerSmooth, sectorPower, actor.epochRewardSmooth, big.Zero(), 0))
+			lockedPledge = big.Add(lockedPledge, sector.InitialPledge)
+		}
+		require.True(t, expectedFee.GreaterThan(big.Zero()))
+
+		// The harness asserts the returned pledge released and penalty charged.
+		actor.terminateSectors(rt, bf(uint64(sectors[0].SectorNumber), uint64(sectors[1].SectorNumber)), expectedFee)
+
+		// Pledge requirement drops by the released pledge, while the balance drops by the penalty,
+		// so the net funds freed are the sectors' pledge less the penalty.
+		st = getState(rt)
+		pledgeFreed := big.Sub(pledgeBefore, st.InitialPledge)
+		penaltyPaid := big.Sub(balanceBefore, rt.Balance())
+		assert.Equal(t, lockedPledge, pledgeFreed)
+		assert.Equal(t, expectedFee, penaltyPaid)
+		assert.Equal(t, big.Sub(lockedPledge, expectedFee), big.Sub(pledgeFreed, penaltyPaid))
+		actor.checkState(rt)
+	})
+
 	t.Run("cannot terminate a sector when the challenge window is open", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -2194,6 +2536,99 @@ func TestTerminateSectors(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	// Returns n consecutive deal IDs starting from first.
+	dealIDRange := func(first abi.DealID, n int) []abi.DealID {
+		ids := make([]abi.DealID, n)
+		for i := range ids {
+			ids[i] = first + abi.DealID(i)
+		}
+		return ids
+	}
+
+	t.Run("notifies market of terminated deals in batches", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		// 600 deals over three sectors exceed a single batch.
+		dealIDs := [][]abi.DealID{dealIDRange(1000, 200), dealIDRange(2000, 200), dealIDRange(3000, 200)}
+		sectorInfo := actor.commitAndProveSectors(rt, 3, defaultSectorExpiration, dealIDs, true)
+		advanceAndSubmitPoSts(rt, actor, sectorInfo...)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+
+		// The deals are notified in two batches, expected by the harness, with a single burn of the penalty.
+		sectors := bf(uint64(sectorInfo[0].SectorNumber), uint64(sectorInfo[1].SectorNumber), uint64(sectorInfo[2].SectorNumber))
+		expectedFee := expectedTerminationFee(actor, rt, sectorInfo...)
+		actor.terminateSectors(rt, sectors, expectedFee)
+
+		st := getState(rt)
+		assert.Equal(t, big.Zero(), st.InitialPledge)
+		actor.checkState(rt)
+	})
+
+	t.Run("aborts if any batch of deal terminations fails", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		dealIDs := [][]abi.DealID{dealIDRange(1000, 200), dealIDRange(2000, 200), dealIDRange(3000, 200)}
+		sectorInfo := actor.commitAndProveSectors(rt, 3, defaultSectorExpiration, dealIDs, true)
+		advanceAndSubmitPoSts(rt, actor, sectorInfo...)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sectorInfo[0].SectorNumber)
+		require.NoError(t, err)
+		sectors := bitfield.New()
+		initialPledge := big.Zero()
+		var allDeals []abi.DealID
+		for _, sector := range sectorInfo {
+			sectorDl, sectorPart, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+			require.NoError(t, err)
+			require.Equal(t, dlIdx, sectorDl)
+			require.Equal(t, pIdx, sectorPart)
+			sectors.Set(uint64(sector.SectorNumber))
+			initialPledge = big.Add(initialPledge, sector.InitialPledge)
+			allDeals = append(allDeals, sector.DealIDs...)
+		}
+
+		expectedFee := expectedTerminationFee(actor, rt, sectorInfo...)
+		pledgeDelta := big.Sum(expectedFee.Neg(), initialPledge.Neg())
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		expectQueryNetworkInfo(rt, actor)
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedFee, nil, exitcode.Ok)
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &pledgeDelta, big.Zero(), nil, exitcode.Ok)
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.OnMinerSectorsTerminate, &market.OnMinerSectorsTerminateParams{
+			Epoch:   rt.Epoch(),
+			DealIDs: allDeals[:miner.DealTerminationBatchSize],
+		}, abi.NewTokenAmount(0), nil, exitcode.Ok)
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.OnMinerSectorsTerminate, &market.OnMinerSectorsTerminateParams{
+			Epoch:   rt.Epoch(),
+			DealIDs: allDeals[miner.DealTerminationBatchSize:],
+		}, abi.NewTokenAmount(0), nil, exitcode.ErrIllegalState)
+
+		params := &miner.TerminateSectorsParams{Terminations: []miner.TerminationDeclaration{{
+			Deadline:  dlIdx,
+			Partition: pIdx,
+			Sectors:   sectors,
+		}}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalState, "failed to terminate deals", func() {
+			rt.Call(actor.a.TerminateSectors, params)
+		})
+		rt.Verify()
+	})
+}
+
+// Computes the fee for terminating sectors at the current epoch, assuming no locked funds constrain it.
+func expectedTerminationFee(h *actorHarness, rt *mock.Runtime, sectors ...*miner.SectorOnChainInfo) abi.TokenAmount {
+	fee := big.Zero()
+	for _, sector := range sectors {
+		sectorPower := miner.QAPowerForSector(h.sectorSize, sector)
+		dayReward := miner.ExpectedRewardForPower(h.epochRewardSmooth, h.epochQAPowerSmooth, sectorPower, builtin.EpochsInDay)
+		twentyDayReward := miner.ExpectedRewardForPower(h.epochRewardSmooth, h.epochQAPowerSmooth, sectorPower, miner.InitialPledgeProjectionPeriod)
+		sectorAge := rt.Epoch() - sector.Activation
+		fee = big.Add(fee, miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, h.epochQAPowerSmooth, sectorPower, h.epochRewardSmooth, big.Zero(), 0))
+	}
+	return fee
 }
 
 func TestWithdrawBalance(t *testing.T) {
@@ -2587,6 +3022,446 @@ func TestCheckSectorProven(t *testing.T) {
 	})
 }
 
+func TestDeadlineExpirations(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+
+	t.Run("reports sector expirations at quantized epochs", func(t *testing.T) {
+		actor := newHarness(t, periodOffset)
+		rt := builderForHarness(actor).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		actor.constructAndVerify(rt)
+
+		sectors := actor.commitAndProveSectors(rt, 4, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...) // prove and activate power.
+
+		// Extend two of the sectors to an expiration that is not on a quantization boundary.
+		st := getState(rt)
+		store := rt.AdtStore()
+		extended := sectors[:2]
+		dlIdx, pIdx, err := st.FindSector(store, extended[0].SectorNumber)
+		require.NoError(t, err)
+		newExpiration := extended[0].Expiration + miner.WPoStProvingPeriod + 7
+		actor.extendSectors(rt, &miner.ExtendSectorExpirationParams{
+			Extensions: []miner.ExpirationExtension{{
+				Deadline:      dlIdx,
+				Partition:     pIdx,
+				Sectors:       bf(uint64(extended[0].SectorNumber), uint64(extended[1].SectorNumber)),
+				NewExpiration: newExpiration,
+			}},
+		})
+
+		st = getState(rt)
+		for i, sector := range sectors {
+			var found bool
+			sectors[i], found, err = st.GetSector(store, sector.SectorNumber)
+			require.NoError(t, err)
+			require.True(t, found)
+		}
+		expectedCounts := map[uint64]map[abi.ChainEpoch]uint64{}
+		expectedPledge := big.Zero()
+		for _, sector := range sectors {
+			dlIdx, _, err := st.FindSector(store, sector.SectorNumber)
+			require.NoError(t, err)
+			epoch := st.QuantSpecForDeadline(dlIdx).QuantizeUp(sector.Expiration)
+			if expectedCounts[dlIdx] == nil {
+				expectedCounts[dlIdx] = map[abi.ChainEpoch]uint64{}
+			}
+			expectedCounts[dlIdx][epoch]++
+			expectedPledge = big.Add(expectedPledge, sector.InitialPledge)
+		}
+
+		epochs := map[abi.ChainEpoch]struct{}{}
+		totalPledge := big.Zero()
+		for dlIdx := uint64(0); dlIdx < miner.WPoStPeriodDeadlines; dlIdx++ {
+			ret := actor.deadlineExpirations(rt, dlIdx)
+			quant := st.QuantSpecForDeadline(dlIdx)
+			counts := map[abi.ChainEpoch]uint64{}
+			for i, exp := range ret.Expirations {
+				if i > 0 {
+					assert.Greater(t, exp.Epoch, ret.Expirations[i-1].Epoch)
+				}
+				assert.Equal(t, quant.QuantizeUp(exp.Epoch), exp.Epoch)
+				assert.Zero(t, exp.EarlySectors)
+				assert.True(t, exp.FaultyPower.IsZero())
+				counts[exp.Epoch] = exp.OnTimeSectors
+				epochs[exp.Epoch] = struct{}{}
+				totalPledge = big.Add(totalPledge, exp.OnTimePledge)
+			}
+			if expectedCounts[dlIdx] == nil {
+				assert.Empty(t, ret.Expirations, "deadline %d", dlIdx)
+			} else {
+				assert.Equal(t, expectedCounts[dlIdx], counts, "deadline %d", dlIdx)
+			}
+		}
+		assert.Len(t, epochs, 2)
+		assert.True(t, expectedPledge.Equals(totalPledge))
+		actor.checkState(rt)
+	})
+
+	t.Run("fails with invalid deadline", func(t *testing.T) {
+		actor := newHarness(t, periodOffset)
+		rt := builderForHarness(actor).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.deadlineExpirations(rt, miner.WPoStPeriodDeadlines)
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestSectorHealth(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	rt := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero()).
+		Build(t)
+	actor.constructAndVerify(rt)
+	rt.SetEpoch(abi.ChainEpoch(1))
+
+	assert.Equal(t, &miner.SectorHealthReturn{
+		LivePower:   miner.NewPowerPairZero(),
+		ActivePower: miner.NewPowerPairZero(),
+	}, actor.sectorHealth(rt))
+
+	sectors := actor.commitAndProveSectors(rt, 4, defaultSectorExpiration, nil, true)
+	advanceAndSubmitPoSts(rt, actor, sectors...) // prove and activate power.
+	pwr := miner.PowerForSector(actor.sectorSize, sectors[0])
+
+	health := actor.sectorHealth(rt)
+	assert.Equal(t, uint64(4), health.Total)
+	assert.Equal(t, uint64(4), health.Active)
+	assert.Equal(t, pwr.Mul(big.NewInt(4)), health.ActivePower)
+
+	// Fault two sectors and declare one of them recovering.
+	advanceDeadline(rt, actor, &cronConfig{})
+	actor.declareFaults(rt, sectors[1], sectors[2])
+	advanceDeadline(rt, actor, &cronConfig{})
+	st := getState(rt)
+	dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sectors[2].SectorNumber)
+	require.NoError(t, err)
+	actor.declareRecoveries(rt, dlIdx, pIdx, bf(uint64(sectors[2].SectorNumber)), big.Zero())
+
+	// Terminate another, with locked funds from which to pay the termination fee.
+	actor.applyRewards(rt, bigRewards, big.Zero())
+	sectorPower := miner.QAPowerForSector(actor.sectorSize, sectors[3])
+	dayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, builtin.EpochsInDay)
+	twentyDayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, miner.InitialPledgeProjectionPeriod)
+	sectorAge := rt.Epoch() - sectors[3].Activation
+	expectedFee := miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, actor.epochQAPowerSmooth, sectorPower, actor.epochRewardSmooth, big.Zero(), 0)
+	actor.terminateSectors(rt, bf(uint64(sectors[3].SectorNumber)), expectedFee)
+
+	// Commit a new sector, which remains unproven.
+	actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, false)
+
+	health = actor.sectorHealth(rt)
+	assert.Equal(t, uint64(5), health.Total)
+	assert.Equal(t, uint64(1), health.Active)
+	assert.Equal(t, uint64(1), health.Unproven)
+	assert.Equal(t, uint64(2), health.Faulty)
+	assert.Equal(t, uint64(1), health.Recovering)
+	assert.Equal(t, uint64(1), health.Terminated)
+	assert.Equal(t, pwr.Mul(big.NewInt(4)), health.LivePower)
+	assert.Equal(t, pwr, health.ActivePower)
+	actor.checkState(rt)
+}
+
+func TestSectorsAtRisk(t *testing.T) {
+	// Small partitions spread the sectors across deadlines.
+	miner.WindowPoStProofTypes[abi.RegisteredPoStProof_StackedDrgWindow2KiBV1] = struct{}{}
+	defer func() {
+		delete(miner.WindowPoStProofTypes, abi.RegisteredPoStProof_StackedDrgWindow2KiBV1)
+	}()
+
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	actor.setProofType(abi.RegisteredSealProof_StackedDrg2KiBV1_1)
+	rt := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero()).
+		Build(t)
+	actor.constructAndVerify(rt)
+	rt.SetEpoch(abi.ChainEpoch(1))
+
+	assert.Empty(t, actor.sectorsAtRisk(rt).Deadlines)
+
+	sectors := actor.commitAndProveSectors(rt, 6, defaultSectorExpiration, nil, true)
+	advanceAndSubmitPoSts(rt, actor, sectors...) // prove and activate power.
+	pwr := miner.PowerForSector(actor.sectorSize, sectors[0])
+
+	// Expect every sector to be at risk at its deadline.
+	expected := map[uint64][]uint64{}
+	st := getState(rt)
+	for _, sector := range sectors {
+		dlIdx, _, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+		expected[dlIdx] = append(expected[dlIdx], uint64(sector.SectorNumber))
+	}
+	require.Greater(t, len(expected), 1, "sectors should be spread across deadlines")
+
+	checkAtRisk := func(expected map[uint64][]uint64) {
+		atRisk := actor.sectorsAtRisk(rt)
+		require.Equal(t, len(expected), len(atRisk.Deadlines))
+		prevClose := rt.Epoch()
+		for _, dl := range atRisk.Deadlines {
+			sectorNos, ok := expected[dl.Deadline]
+			require.True(t, ok, "unexpected deadline %d", dl.Deadline)
+			assertBitfieldEquals(t, dl.Sectors, sectorNos...)
+			assert.Equal(t, pwr.Mul(big.NewInt(int64(len(sectorNos)))), dl.Power)
+			assert.Equal(t, miner.NewDeadlineInfo(st.CurrentProvingPeriodStart(rt.Epoch()), dl.Deadline, rt.Epoch()).NextNotElapsed().Close, dl.Close)
+			assert.Greater(t, dl.Close, prevClose, "deadlines should be in closing order")
+			prevClose = dl.Close
+		}
+	}
+	checkAtRisk(expected)
+
+	// Sectors proven for the current challenge window are not at risk until the deadline next opens.
+	provenIdx := actor.sectorsAtRisk(rt).Deadlines[0].Deadline
+	dlinfo := advanceToDeadline(rt, actor, provenIdx)
+	var infos []*miner.SectorOnChainInfo
+	for _, sector := range sectors {
+		if dlIdx, _, err := st.FindSector(rt.AdtStore(), sector.SectorNumber); err == nil && dlIdx == provenIdx {
+			infos = append(infos, sector)
+		}
+	}
+	partitions, err := actor.getDeadline(rt, provenIdx).PartitionsArray(rt.AdtStore())
+	require.NoError(t, err)
+	var posts []miner.PoStPartition
+	for pIdx := uint64(0); pIdx < partitions.Length(); pIdx++ {
+		posts = append(posts, miner.PoStPartition{Index: pIdx, Skipped: bitfield.New()})
+	}
+	actor.submitWindowPoSt(rt, dlinfo, posts, infos, nil)
+	proven := expected[provenIdx]
+	delete(expected, provenIdx)
+	checkAtRisk(expected)
+
+	// A faulty sector is no longer at risk of detection.
+	atRisk := actor.sectorsAtRisk(rt)
+	lastIdx := atRisk.Deadlines[len(atRisk.Deadlines)-1].Deadline
+	faulty := expected[lastIdx][0]
+	for _, sector := range sectors {
+		if uint64(sector.SectorNumber) == faulty {
+			actor.declareFaults(rt, sector)
+		}
+	}
+	expected[lastIdx] = expected[lastIdx][1:]
+	if len(expected[lastIdx]) == 0 {
+		delete(expected, lastIdx)
+	}
+	checkAtRisk(expected)
+
+	advanceDeadline(rt, actor, &cronConfig{})
+	expected[provenIdx] = proven
+	checkAtRisk(expected)
+	actor.checkState(rt)
+}
+
+func TestBatchDeclareFaults(t *testing.T) {
+	// Small partitions spread the sectors across deadlines.
+	miner.WindowPoStProofTypes[abi.RegisteredPoStProof_StackedDrgWindow2KiBV1] = struct{}{}
+	defer func() {
+		delete(miner.WindowPoStProofTypes, abi.RegisteredPoStProof_StackedDrgWindow2KiBV1)
+	}()
+
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	actor.setProofType(abi.RegisteredSealProof_StackedDrg2KiBV1_1)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	// Returns proven sectors grouped by deadline, and the deadline indices in ascending order.
+	setup := func(t *testing.T) (*mock.Runtime, map[uint64][]*miner.SectorOnChainInfo, []uint64) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectors := actor.commitAndProveSectors(rt, 6, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+
+		st := getState(rt)
+		byDeadline := map[uint64][]*miner.SectorOnChainInfo{}
+		var dlIdxs []uint64
+		for _, sector := range sectors {
+			dlIdx, _, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+			require.NoError(t, err)
+			if _, ok := byDeadline[dlIdx]; !ok {
+				dlIdxs = append(dlIdxs, dlIdx)
+			}
+			byDeadline[dlIdx] = append(byDeadline[dlIdx], sector)
+		}
+		sort.Slice(dlIdxs, func(i, j int) bool { return dlIdxs[i] < dlIdxs[j] })
+		require.Greater(t, len(dlIdxs), 2, "sectors should be spread across deadlines")
+		return rt, byDeadline, dlIdxs
+	}
+
+	t.Run("declares faults in several deadlines", func(t *testing.T) {
+		rt, byDeadline, dlIdxs := setup(t)
+		var all []*miner.SectorOnChainInfo
+		for _, dlIdx := range dlIdxs {
+			all = append(all, byDeadline[dlIdx]...)
+		}
+		params := makeFaultParamsFromFaultingSectors(t, getState(rt), rt.AdtStore(), all)
+		totalPower := miner.PowerForSectors(actor.sectorSize, all)
+
+		ret := actor.batchDeclareFaults(rt, params, totalPower)
+		assert.Equal(t, totalPower, ret.PowerFaulted)
+		require.Len(t, ret.Deadlines, len(dlIdxs))
+		for i, dlIdx := range dlIdxs {
+			result := ret.Deadlines[i]
+			assert.Equal(t, dlIdx, result.Deadline)
+			assert.Equal(t, exitcode.Ok, result.Code)
+			dlPower := miner.PowerForSectors(actor.sectorSize, byDeadline[dlIdx])
+			assert.Equal(t, dlPower, result.PowerFaulted)
+			assert.True(t, dlPower.Equals(actor.getDeadline(rt, dlIdx).FaultyPower))
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("skips a deadline with an invalid declaration", func(t *testing.T) {
+		rt, byDeadline, dlIdxs := setup(t)
+		badIdx := dlIdxs[1]
+		var valid []*miner.SectorOnChainInfo
+		for _, dlIdx := range dlIdxs {
+			if dlIdx != badIdx {
+				valid = append(valid, byDeadline[dlIdx]...)
+			}
+		}
+		params := makeFaultParamsFromFaultingSectors(t, getState(rt), rt.AdtStore(), append(valid, byDeadline[badIdx]...))
+		// The bad deadline also declares a fault in a partition that doesn't exist.
+		params.Faults = append(params.Faults, miner.FaultDeclaration{
+			Deadline:  badIdx,
+			Partition: 99,
+			Sectors:   bf(uint64(byDeadline[badIdx][0].SectorNumber)),
+		})
+		validPower := miner.PowerForSectors(actor.sectorSize, valid)
+
+		ret := actor.batchDeclareFaults(rt, params, validPower)
+		assert.Equal(t, validPower, ret.PowerFaulted)
+		require.Len(t, ret.Deadlines, len(dlIdxs))
+		for i, dlIdx := range dlIdxs {
+			result := ret.Deadlines[i]
+			assert.Equal(t, dlIdx, result.Deadline)
+			if dlIdx == badIdx {
+				assert.Equal(t, exitcode.ErrNotFound, result.Code)
+				assert.True(t, result.PowerFaulted.IsZero())
+				assert.True(t, actor.getDeadline(rt, dlIdx).FaultyPower.IsZero())
+			} else {
+				assert.Equal(t, exitcode.Ok, result.Code)
+				assert.Equal(t, miner.PowerForSectors(actor.sectorSize, byDeadline[dlIdx]), result.PowerFaulted)
+			}
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("no power change when every deadline is invalid", func(t *testing.T) {
+		rt, byDeadline, dlIdxs := setup(t)
+		params := &miner.DeclareFaultsParams{}
+		for _, dlIdx := range dlIdxs {
+			params.Faults = append(params.Faults, miner.FaultDeclaration{
+				Deadline:  dlIdx,
+				Partition: 99,
+				Sectors:   bf(uint64(byDeadline[dlIdx][0].SectorNumber)),
+			})
+		}
+
+		ret := actor.batchDeclareFaults(rt, params, miner.NewPowerPairZero())
+		assert.True(t, ret.PowerFaulted.IsZero())
+		require.Len(t, ret.Deadlines, len(dlIdxs))
+		for _, result := range ret.Deadlines {
+			assert.Equal(t, exitcode.ErrNotFound, result.Code)
+		}
+		actor.checkState(rt)
+	})
+}
+
+func TestTopUpSectorPledge(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+	topUp := big.Mul(big.NewInt(1e18), big.NewInt(10))
+
+	setup := func(t *testing.T) (*mock.Runtime, *miner.SectorOnChainInfo, uint64, uint64) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+		return rt, sector, dlIdx, pIdx
+	}
+
+	t.Run("topped-up pledge is released on termination without changing the fee", func(t *testing.T) {
+		rt, sector, dlIdx, pIdx := setup(t)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+		initialLockedFunds := getState(rt).LockedFunds
+
+		actor.topUpSectorPledge(rt, miner.SectorPledgeTopUp{Deadline: dlIdx, Partition: pIdx, Sector: sector.SectorNumber, Amount: topUp})
+
+		toppedUp := actor.getSector(rt, sector.SectorNumber)
+		expectedPledge := big.Add(sector.InitialPledge, topUp)
+		assert.Equal(t, expectedPledge, toppedUp.InitialPledge)
+		assert.Equal(t, expectedPledge, getState(rt).InitialPledge)
+		// Pledge is scheduled for release at the sector's on-time expiration.
+		_, partition := actor.findSector(rt, sector.SectorNumber)
+		for _, es := range actor.collectPartitionExpirations(rt, partition) {
+			assert.Equal(t, expectedPledge, es.OnTimePledge)
+		}
+		actor.checkState(rt)
+
+		// The termination fee derives from expected rewards, not pledge, so is unchanged by the top-up,
+		// while the full increased pledge is released.
+		sectorPower := miner.QAPowerForSector(actor.sectorSize, sector)
+		dayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, builtin.EpochsInDay)
+		twentyDayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, miner.InitialPledgeProjectionPeriod)
+		sectorAge := rt.Epoch() - sector.Activation
+		expectedFee := miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, actor.epochQAPowerSmooth, sectorPower, actor.epochRewardSmooth, big.Zero(), 0)
+
+		_, pledgeDelta := actor.terminateSectors(rt, bf(uint64(sector.SectorNumber)), expectedFee)
+		assert.Equal(t, big.Sum(expectedFee, expectedPledge).Neg(), pledgeDelta)
+
+		st := getState(rt)
+		assert.Equal(t, big.Sub(initialLockedFunds, expectedFee), st.LockedFunds)
+		assert.Equal(t, big.Zero(), st.InitialPledge)
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects value not matching total top-up", func(t *testing.T) {
+		rt, sector, dlIdx, pIdx := setup(t)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.SetReceived(big.Sub(topUp, big.NewInt(1)))
+		params := &miner.TopUpSectorPledgeParams{TopUps: []miner.SectorPledgeTopUp{
+			{Deadline: dlIdx, Partition: pIdx, Sector: sector.SectorNumber, Amount: topUp},
+		}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "does not match total top-up", func() {
+			rt.Call(actor.a.TopUpSectorPledge, params)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects faulty sector", func(t *testing.T) {
+		rt, sector, dlIdx, pIdx := setup(t)
+		actor.declareFaults(rt, sector)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.SetBalance(big.Add(rt.Balance(), topUp))
+		rt.SetReceived(topUp)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		params := &miner.TopUpSectorPledgeParams{TopUps: []miner.SectorPledgeTopUp{
+			{Deadline: dlIdx, Partition: pIdx, Sector: sector.SectorNumber, Amount: topUp},
+		}}
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "is not active", func() {
+			rt.Call(actor.a.TopUpSectorPledge, params)
+		})
+		actor.checkState(rt)
+	})
+}
+
 func TestChangeMultiAddrs(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 
@@ -2597,8 +3472,8 @@ func TestChangeMultiAddrs(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
 
-		addr1 := abi.Multiaddrs([]byte("addr1"))
-		addr2 := abi.Multiaddrs([]byte("addr2"))
+		addr1 := mustMultiaddr(t, "/ip4/1.2.3.4/tcp/1")
+		addr2 := mustMultiaddr(t, "/ip4/1.2.3.4/tcp/2")
 
 		actor.changeMultiAddrs(rt, []abi.Multiaddrs{addr1, addr2})
 		actor.checkState(rt)
@@ -3151,6 +4026,52 @@ func TestReportConsensusFault(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("each fault type is penalized equally and recorded", func(t *testing.T) {
+		for _, faultType := range []runtime.ConsensusFaultType{
+			runtime.ConsensusFaultDoubleForkMining,
+			runtime.ConsensusFaultParentGrinding,
+			runtime.ConsensusFaultTimeOffsetMining,
+		} {
+			rt := builder.Build(t)
+			actor.constructAndVerify(rt)
+			rt.SetEpoch(abi.ChainEpoch(1))
+
+			// The harness expects the same ConsensusFaultPenalty to be charged for every type.
+			actor.reportConsensusFault(rt, addr.TestAddress, &runtime.ConsensusFault{
+				Target: actor.receiver,
+				Epoch:  rt.Epoch() - 1,
+				Type:   faultType,
+			})
+			info := actor.getInfo(rt)
+			assert.Equal(t, faultType, info.ConsensusFaultType)
+			assert.Equal(t, rt.Epoch()+miner.ConsensusFaultIneligibilityDuration, info.ConsensusFaultElapsed)
+			actor.checkState(rt)
+		}
+	})
+
+	t.Run("unknown fault type rejected", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+
+		rt.SetCaller(addr.TestAddress, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+		rt.ExpectVerifyConsensusFault(nil, nil, nil, &runtime.ConsensusFault{
+			Target: actor.receiver,
+			Epoch:  rt.Epoch() - 1,
+			Type:   runtime.ConsensusFaultType(99),
+		}, nil)
+		// Rejected before requesting the epoch reward.
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "unknown consensus fault type", func() {
+			rt.Call(actor.a.ReportConsensusFault, &miner.ReportConsensusFaultParams{})
+		})
+		rt.Verify()
+		info := actor.getInfo(rt)
+		assert.Equal(t, abi.ChainEpoch(-1), info.ConsensusFaultElapsed)
+		assert.Equal(t, runtime.ConsensusFaultType(0), info.ConsensusFaultType)
+		actor.checkState(rt)
+	})
+
 	t.Run("Report consensus fault updates consensus fault reported field", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -3305,6 +4226,47 @@ func TestApplyRewards(t *testing.T) {
 		assert.Contains(t, msgs.Messages()[0], "DeadlineCronActive == false")
 	})
 
+	t.Run("alternate vesting schedule changes when funds unlock", func(t *testing.T) {
+		require.NoError(t, miner.SetRewardVestingSpec(miner.VestSpec{
+			InitialDelay: abi.ChainEpoch(0),
+			VestPeriod:   abi.ChainEpoch(10 * builtin.EpochsInDay),
+			StepDuration: abi.ChainEpoch(1 * builtin.EpochsInDay),
+			Quantization: abi.ChainEpoch(1 * builtin.EpochsInDay),
+		}))
+		defer func() {
+			require.NoError(t, miner.SetRewardVestingSpec(miner.DefaultRewardVestingSpec))
+		}()
+
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		amt := abi.NewTokenAmount(1_000_000)
+		actor.applyRewards(rt, amt, big.Zero())
+		st := getState(rt)
+		vestingFunds, err := st.LoadVestingFunds(adt.AsStore(rt))
+		require.NoError(t, err)
+		require.Len(t, vestingFunds.Funds, 10)
+
+		// Everything has unlocked once the shortened vesting period has elapsed,
+		// long before the default schedule would have released it.
+		lockedAmt, _ := miner.LockedRewardFromReward(amt)
+		end := vestingFunds.Funds[len(vestingFunds.Funds)-1].Epoch
+		assert.Less(t, int64(end-rt.Epoch()), int64(miner.DefaultRewardVestingSpec.VestPeriod))
+		vested, err := st.CheckVestedFunds(adt.AsStore(rt), end)
+		require.NoError(t, err)
+		assert.True(t, vested.LessThan(lockedAmt))
+		vested, err = st.CheckVestedFunds(adt.AsStore(rt), end+1)
+		require.NoError(t, err)
+		assert.Equal(t, lockedAmt, vested)
+	})
+
+	t.Run("rejects invalid vesting schedule", func(t *testing.T) {
+		spec := miner.DefaultRewardVestingSpec
+		spec.StepDuration = 0
+		assert.Error(t, miner.SetRewardVestingSpec(spec))
+		assert.Equal(t, miner.DefaultRewardVestingSpec, miner.RewardVestingSpec)
+	})
+
 	t.Run("penalty is burnt", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -3855,6 +4817,56 @@ func (h *actorHarness) checkSectorProven(rt *mock.Runtime, sectorNum abi.SectorN
 	rt.Verify()
 }
 
+func (h *actorHarness) sectorHealth(rt *mock.Runtime) *miner.SectorHealthReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.SectorHealth, nil).(*miner.SectorHealthReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *actorHarness) sectorsAtRisk(rt *mock.Runtime) *miner.SectorsAtRiskReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.SectorsAtRisk, nil).(*miner.SectorsAtRiskReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *actorHarness) batchDeclareFaults(rt *mock.Runtime, params *miner.DeclareFaultsParams, expectedPower miner.PowerPair) *miner.BatchDeclareFaultsReturn {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+	if !expectedPower.IsZero() {
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdateClaimedPower, &power.UpdateClaimedPowerParams{
+			RawByteDelta:         expectedPower.Raw.Neg(),
+			QualityAdjustedDelta: expectedPower.QA.Neg(),
+		}, big.Zero(), nil, exitcode.Ok)
+	}
+	ret := rt.Call(h.a.BatchDeclareFaults, params).(*miner.BatchDeclareFaultsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *actorHarness) topUpSectorPledge(rt *mock.Runtime, topUps ...miner.SectorPledgeTopUp) {
+	total := big.Zero()
+	for _, topUp := range topUps {
+		total = big.Add(total, topUp.Amount)
+	}
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+	rt.SetBalance(big.Add(rt.Balance(), total))
+	rt.SetReceived(total)
+	rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &total, big.Zero(), nil, exitcode.Ok)
+
+	rt.Call(h.a.TopUpSectorPledge, &miner.TopUpSectorPledgeParams{TopUps: topUps})
+	rt.Verify()
+}
+
+func (h *actorHarness) deadlineExpirations(rt *mock.Runtime, dlIdx uint64) *miner.DeadlineExpirationsReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.DeadlineExpirations, &miner.DeadlineExpirationsParams{Deadline: dlIdx}).(*miner.DeadlineExpirationsReturn)
+	rt.Verify()
+	return ret
+}
+
 func (h *actorHarness) changeMultiAddrs(rt *mock.Runtime, newAddrs []abi.Multiaddrs) {
 	param := &miner.ChangeMultiaddrsParams{NewMultiaddrs: newAddrs}
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -3891,6 +4903,13 @@ func (h *actorHarness) controlAddresses(rt *mock.Runtime) (owner, worker addr.Ad
 	return ret.Owner, ret.Worker, ret.ControlAddrs
 }
 
+func (h *actorHarness) minerAddresses(rt *mock.Runtime) *miner.MinerAddressesReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.MinerAddresses, nil).(*miner.MinerAddressesReturn)
+	rt.Verify()
+	return ret
+}
+
 // Options for preCommitSector behaviour.
 // Default zero values should let everything be ok.
 type preCommitConf struct {
@@ -4162,21 +5181,26 @@ func (h *actorHarness) proveCommitAggregateSector(rt *mock.Runtime, conf proveCo
 func (h *actorHarness) confirmSectorProofsValidInternal(rt *mock.Runtime, conf proveCommitConf, precommits ...*miner.SectorPreCommitOnChainInfo) {
 	// Prepare for and receive call to ConfirmSectorProofsValid.
 	var validPrecommits []*miner.SectorPreCommitOnChainInfo
+	var sectorDeals []market.SectorDeals
+	activated := bitfield.New()
 	for _, precommit := range precommits {
-		validPrecommits = append(validPrecommits, precommit)
 		if len(precommit.Info.DealIDs) > 0 {
-			vdParams := market.ActivateDealsParams{
+			sectorDeals = append(sectorDeals, market.SectorDeals{
 				DealIDs:      precommit.Info.DealIDs,
 				SectorExpiry: precommit.Info.Expiration,
+			})
+			// A sector whose deals fail to validate is omitted from the activated set.
+			if _, found := conf.verifyDealsExit[precommit.Info.SectorNumber]; found {
+				continue
 			}
-			exit, found := conf.verifyDealsExit[precommit.Info.SectorNumber]
-			if found {
-				validPrecommits = validPrecommits[:len(validPrecommits)-1] // pop
-			} else {
-				exit = exitcode.Ok
-			}
-			rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.ActivateDeals, &vdParams, big.Zero(), nil, exit)
+			activated.Set(uint64(len(sectorDeals) - 1))
 		}
+		validPrecommits = append(validPrecommits, precommit)
+	}
+	if len(sectorDeals) > 0 {
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.BatchActivateDeals,
+			&market.BatchActivateDealsParams{Sectors: sectorDeals}, big.Zero(),
+			&market.BatchActivateDealsReturn{Activated: activated}, exitcode.Ok)
 	}
 
 	// expected pledge is the sum of initial pledges
@@ -4593,24 +5617,35 @@ func (h *actorHarness) declareFaults(rt *mock.Runtime, faultSectorInfos ...*mine
 }
 
 func (h *actorHarness) declareRecoveries(rt *mock.Runtime, deadlineIdx uint64, partitionIdx uint64, recoverySectors bitfield.BitField, expectedDebtRepaid abi.TokenAmount) {
-	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
-	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
-
-	if expectedDebtRepaid.GreaterThan(big.Zero()) {
-		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedDebtRepaid, nil, exitcode.Ok)
-	}
-
 	// Calculate params from faulted sector infos
 	params := &miner.DeclareFaultsRecoveredParams{Recoveries: []miner.RecoveryDeclaration{{
 		Deadline:  deadlineIdx,
 		Partition: partitionIdx,
 		Sectors:   recoverySectors,
 	}}}
+	h.declareRecoveriesWithParams(rt, params, expectedDebtRepaid)
+}
+
+func (h *actorHarness) declareRecoveriesWithParams(rt *mock.Runtime, params *miner.DeclareFaultsRecoveredParams, expectedDebtRepaid abi.TokenAmount) {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	if expectedDebtRepaid.GreaterThan(big.Zero()) {
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedDebtRepaid, nil, exitcode.Ok)
+	}
 
 	rt.Call(h.a.DeclareFaultsRecovered, params)
 	rt.Verify()
 }
 
+func (h *actorHarness) previewDeclareFaultsRecovered(rt *mock.Runtime, params *miner.DeclareFaultsRecoveredParams) *miner.PreviewDeclareFaultsRecoveredReturn {
+	rt.ExpectValidateCallerAny()
+	expectQueryNetworkInfo(rt, h)
+	ret := rt.Call(h.a.PreviewDeclareFaultsRecovered, params).(*miner.PreviewDeclareFaultsRecoveredReturn)
+	rt.Verify()
+	return ret
+}
+
 func (h *actorHarness) extendSectors(rt *mock.Runtime, params *miner.ExtendSectorExpirationParams) {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -4645,6 +5680,13 @@ func (h *actorHarness) extendSectors(rt *mock.Runtime, params *miner.ExtendSecto
 	rt.Verify()
 }
 
+func (h *actorHarness) aggregateProveCommitBounds(rt *mock.Runtime) *miner.AggregateProveCommitBoundsReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.AggregateProveCommitBounds, nil).(*miner.AggregateProveCommitBoundsReturn)
+	rt.Verify()
+	return ret
+}
+
 func (h *actorHarness) terminateSectors(rt *mock.Runtime, sectors bitfield.BitField, expectedFee abi.TokenAmount) (miner.PowerPair, abi.TokenAmount) {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -4669,18 +5711,20 @@ func (h *actorHarness) terminateSectors(rt *mock.Runtime, sectors bitfield.BitFi
 		pledgeDelta = big.Sum(pledgeDelta, expectedFee.Neg())
 	}
 	// notify change to initial pledge
+	releasedPledge := big.Zero()
 	if len(sectorInfos) > 0 {
 		for _, sector := range sectorInfos {
 			pledgeDelta = big.Add(pledgeDelta, sector.InitialPledge.Neg())
+			releasedPledge = big.Add(releasedPledge, sector.InitialPledge)
 		}
 	}
 	if !pledgeDelta.Equals(big.Zero()) {
 		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &pledgeDelta, big.Zero(), nil, exitcode.Ok)
 	}
-	if len(dealIDs) > 0 {
+	for len(dealIDs) > 0 {
 		size := len(dealIDs)
-		if size > cbg.MaxLength {
-			size = cbg.MaxLength
+		if size > miner.DealTerminationBatchSize {
+			size = miner.DealTerminationBatchSize
 		}
 		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.OnMinerSectorsTerminate, &market.OnMinerSectorsTerminateParams{
 			Epoch:   rt.Epoch(),
@@ -4716,9 +5760,12 @@ func (h *actorHarness) terminateSectors(rt *mock.Runtime, sectors bitfield.BitFi
 	require.NoError(h.t, err)
 
 	params := &miner.TerminateSectorsParams{Terminations: declarations}
-	rt.Call(h.a.TerminateSectors, params)
+	ret := rt.Call(h.a.TerminateSectors, params).(*miner.TerminateSectorsReturn)
 	rt.Verify()
 
+	assert.True(h.t, ret.Done)
+	rt.ExpectLogsContain(fmt.Sprintf("terminated %d sectors: released pledge %s, charged termination fee %s",
+		len(sectorInfos), releasedPledge, expectedFee))
 	return sectorPower.Neg(), pledgeDelta
 }
 
@@ -4974,8 +6021,7 @@ func (h *actorHarness) setMultiaddrs(rt *mock.Runtime, newMultiaddrs ...abi.Mult
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
 
-	ret := rt.Call(h.a.ChangeMultiaddrs, &params)
-	assert.Nil(h.t, ret)
+	rt.Call(h.a.ChangeMultiaddrs, &params)
 	rt.Verify()
 
 	var st miner.State
@@ -4986,6 +6032,12 @@ func (h *actorHarness) setMultiaddrs(rt *mock.Runtime, newMultiaddrs ...abi.Mult
 	assert.Equal(h.t, newMultiaddrs, info.Multiaddrs)
 }
 
+func mustMultiaddr(t testing.TB, s string) abi.Multiaddrs {
+	ma, err := multiaddr.NewMultiaddr(s)
+	require.NoError(t, err)
+	return ma.Bytes()
+}
+
 //
 // Higher-level orchestration
 //
@@ -83,6 +83,15 @@ const DeclarationsMax = AddressedPartitionsMax
 // This limits the amount of state to be read in a single message execution.
 const AddressedSectorsMax = 25_000 // PARAM_SPEC
 
+// The maximum number of sectors that may be declared recovered in a single message.
+// Declarations beyond this are deferred rather than processed.
+var DeclaredRecoveriesMax = uint64(AddressedSectorsMax)
+
+// The maximum number of deal IDs sent to the market actor in a single deal-termination notification.
+// Terminations involving more deals are notified in successive batches of at most this size, which
+// bounds the size and execution cost of each notification.
+const DealTerminationBatchSize = 512 // PARAM_SPEC
+
 // Libp2p peer info limits.
 const (
 	// MaxPeerIDLength is the maximum length allowed for any on-chain peer ID.
@@ -91,6 +100,12 @@ const (
 
 	// MaxMultiaddrData is the maximum amount of data that can be stored in multiaddrs.
 	MaxMultiaddrData = 1024 // PARAM_SPEC
+
+	// MaxMultiaddrSize is the maximum length allowed for any single on-chain multiaddr.
+	MaxMultiaddrSize = 256 // PARAM_SPEC
+
+	// MaxMultiaddrCount is the maximum number of multiaddrs a miner may register.
+	MaxMultiaddrCount = 32 // PARAM_SPEC
 )
 
 // Maximum number of control addresses a miner may register.
@@ -278,14 +293,37 @@ type VestSpec struct {
 	Quantization abi.ChainEpoch // Maximum precision of vesting table (limits cardinality of table).
 }
 
-// The vesting schedule for total rewards (block reward + gas reward) earned by a block producer.
-var RewardVestingSpec = VestSpec{ // PARAM_SPEC
+// The mainnet vesting schedule for total rewards (block reward + gas reward) earned by a block producer.
+var DefaultRewardVestingSpec = VestSpec{ // PARAM_SPEC
 	InitialDelay: abi.ChainEpoch(0),
 	VestPeriod:   abi.ChainEpoch(180 * builtin.EpochsInDay),
 	StepDuration: abi.ChainEpoch(1 * builtin.EpochsInDay),
 	Quantization: 12 * builtin.EpochsInHour,
 }
 
+// The vesting schedule applied to rewards by ApplyRewards.
+// This is always DefaultRewardVestingSpec on mainnet, and may only be changed with SetRewardVestingSpec.
+var RewardVestingSpec = DefaultRewardVestingSpec
+
+// Configures an alternate reward vesting schedule.
+// This exists for devnets and economic simulation, and must never be called by a mainnet node.
+func SetRewardVestingSpec(spec VestSpec) error {
+	if spec.InitialDelay < 0 {
+		return fmt.Errorf("vesting initial delay %d must not be negative", spec.InitialDelay)
+	}
+	if spec.VestPeriod <= 0 {
+		return fmt.Errorf("vesting period %d must be positive", spec.VestPeriod)
+	}
+	if spec.StepDuration <= 0 {
+		return fmt.Errorf("vesting step duration %d must be positive", spec.StepDuration)
+	}
+	if spec.Quantization <= 0 {
+		return fmt.Errorf("vesting quantization %d must be positive", spec.Quantization)
+	}
+	RewardVestingSpec = spec
+	return nil
+}
+
 // When an actor reports a consensus fault, they earn a share of the penalty paid by the miner.
 func RewardForConsensusSlashReport(epochReward abi.TokenAmount) abi.TokenAmount {
 	return big.Div(epochReward,
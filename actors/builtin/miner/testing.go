@@ -12,6 +12,7 @@ import (
 )
 
 type DealSummary struct {
+	SectorNumber     abi.SectorNumber
 	SectorStart      abi.ChainEpoch
 	SectorExpiration abi.ChainEpoch
 }
@@ -23,6 +24,8 @@ type StateSummary struct {
 	Deals               map[abi.DealID]DealSummary
 	WindowPoStProofType abi.RegisteredPoStProof
 	DeadlineCronActive  bool
+	InitialPledge       abi.TokenAmount
+	LockedFunds         abi.TokenAmount
 }
 
 // Checks internal invariants of init state.
@@ -35,6 +38,8 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 		FaultyPower:         NewPowerPairZero(),
 		WindowPoStProofType: 0,
 		DeadlineCronActive:  st.DeadlineCronActive,
+		InitialPledge:       st.InitialPledge,
+		LockedFunds:         st.LockedFunds,
 	}
 
 	// Load data from linked structures.
@@ -78,7 +83,12 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 				"on chain sector's sector number has not been allocated %d", sno)
 
 			for _, dealID := range sector.DealIDs {
+				// A deal's space is counted once, towards the weight of the single sector containing it.
+				if prev, found := minerSummary.Deals[dealID]; found {
+					acc.Addf("deal %d included in sector %d and sector %d", dealID, prev.SectorNumber, sno)
+				}
 				minerSummary.Deals[dealID] = DealSummary{
+					SectorNumber:     abi.SectorNumber(sno),
 					SectorStart:      sector.Activation,
 					SectorExpiration: sector.Expiration,
 				}
@@ -225,6 +225,13 @@ func (pca Actor) UpdateChannelState(rt runtime.Runtime, params *UpdateChannelSta
 			}
 		}
 
+		// A lane's redeemed amount may never decrease. A voucher for less than the lane has already
+		// redeemed is stale or erroneous, even if it would otherwise be covered by the channel balance.
+		if sv.Amount.LessThan(laneState.Redeemed) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "voucher amount %v for lane %d is less than already redeemed amount %v",
+				sv.Amount, laneId, laneState.Redeemed)
+		}
+
 		// The next section actually calculates the payment amounts to update the payment channel state
 		// 1. (optional) sum already redeemed value of all merging lanes
 		redeemedFromOthers := big.Zero()
@@ -417,6 +417,56 @@ func TestActor_UpdateChannelStateRedeem(t *testing.T) {
 		rt.Verify()
 		actor.checkState(rt)
 	})
+
+	t.Run("redeeming voucher fails if lane amount regresses", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 3)
+		var st1 State
+		rt.GetState(&st1)
+
+		// Lane 2 has redeemed 3, so a voucher for 2 on that lane is stale.
+		ucp := &UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Amount = big.NewInt(2)
+
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st1.From, st1.To)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, actor.payer, voucherBytes(t, &ucp.Sv), nil)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "voucher amount 2 for lane 2 is less than already redeemed amount 3", func() {
+			rt.Call(actor.UpdateChannelState, ucp)
+		})
+		rt.Verify()
+
+		var st2 State
+		rt.GetState(&st2)
+		assert.Equal(t, st1, st2)
+		actor.checkState(rt)
+	})
+
+	t.Run("redeeming voucher succeeds with equal or increasing lane amount", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 1)
+
+		for _, amt := range []int64{1, 5} {
+			var st1 State
+			rt.GetState(&st1)
+
+			ucp := &UpdateChannelStateParams{Sv: *sv}
+			ucp.Sv.Amount = big.NewInt(amt)
+
+			rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+			rt.ExpectValidateCallerAddr(st1.From, st1.To)
+			rt.ExpectVerifySignature(*ucp.Sv.Signature, actor.payer, voucherBytes(t, &ucp.Sv), nil)
+			ret := rt.Call(actor.UpdateChannelState, ucp)
+			require.Nil(t, ret)
+			rt.Verify()
+
+			var st2 State
+			rt.GetState(&st2)
+			ls := getLaneState(t, rt, st2.LaneStates, sv.Lane)
+			assert.Equal(t, big.NewInt(amt), ls.Redeemed)
+			assert.Equal(t, big.NewInt(amt), st2.ToSend)
+			sv.Nonce++
+		}
+		actor.checkState(rt)
+	})
 }
 
 func TestActor_UpdateChannelStateMergeSuccess(t *testing.T) {
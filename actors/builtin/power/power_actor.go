@@ -22,6 +22,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime/proof"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/smoothing"
 )
 
 type Runtime = runtime.Runtime
@@ -45,6 +46,9 @@ func (a Actor) Exports() []interface{} {
 		7:                         nil, // deprecated
 		8:                         a.SubmitPoRepForBulkVerify,
 		9:                         a.CurrentTotalPower,
+		10:                        a.CurrentPledgeBase,
+		11:                        a.ReconcileClaim,
+		12:                        a.MinerClaim,
 	}
 }
 
@@ -133,12 +137,13 @@ func (a Actor) CreateMiner(rt Runtime, params *CreateMinerParams) *CreateMinerRe
 	)
 	builtin.RequireSuccess(rt, code, "failed to init new actor")
 
+	claim := Claim{params.WindowPoStProofType, abi.NewStoragePower(0), abi.NewStoragePower(0)}
 	var st State
 	rt.StateTransaction(&st, func() {
 		claims, err := adt.AsMap(adt.AsStore(rt), st.Claims, builtin.DefaultHamtBitwidth)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
 
-		err = setClaim(claims, addresses.IDAddress, &Claim{params.WindowPoStProofType, abi.NewStoragePower(0), abi.NewStoragePower(0)})
+		err = setClaim(claims, addresses.IDAddress, &claim)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put power in claimed table while creating miner")
 
 		st.MinerCount += 1
@@ -150,6 +155,9 @@ func (a Actor) CreateMiner(rt Runtime, params *CreateMinerParams) *CreateMinerRe
 		st.Claims, err = claims.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush claims")
 	})
+	// The return value predates claims being reported, so the initial claim is logged instead.
+	rt.Log(rtt.INFO, "created miner %v with initial claim: proof type %v, raw %v, qa %v", addresses.IDAddress,
+		claim.WindowPoStProofType, claim.RawBytePower, claim.QualityAdjPower)
 	return &CreateMinerReturn{
 		IDAddress:     addresses.IDAddress,
 		RobustAddress: addresses.RobustAddress,
@@ -335,6 +343,113 @@ func (a Actor) CurrentTotalPower(rt Runtime, _ *abi.EmptyValue) *CurrentTotalPow
 	}
 }
 
+type CurrentPledgeBaseReturn struct {
+	CirculatingSupply       abi.TokenAmount
+	PledgeCollateral        abi.TokenAmount
+	QualityAdjPowerSmoothed smoothing.FilterEstimate
+}
+
+// Returns the inputs to pledge computations which are sourced from the network at this epoch:
+// the circulating supply and the total pledge and smoothed power recorded by the power actor.
+// Pledge and power are frozen during the cron tick before this epoch, consistent with CurrentTotalPower.
+func (a Actor) CurrentPledgeBase(rt Runtime, _ *abi.EmptyValue) *CurrentPledgeBaseReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	var st State
+	rt.StateReadonly(&st)
+
+	return &CurrentPledgeBaseReturn{
+		CirculatingSupply:       rt.TotalFilCircSupply(),
+		PledgeCollateral:        st.ThisEpochPledgeCollateral,
+		QualityAdjPowerSmoothed: st.ThisEpochQAPowerSmoothed,
+	}
+}
+
+// Returns the power claimed by a miner, which is registered with zero power when the miner is created.
+func (a Actor) MinerClaim(rt Runtime, minerAddr *addr.Address) *Claim {
+	rt.ValidateImmediateCallerAcceptAny()
+	miner, ok := rt.ResolveAddress(*minerAddr)
+	if !ok {
+		rt.Abortf(exitcode.ErrIllegalArgument, "failed to resolve miner address %v", *minerAddr)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	claim, found, err := st.GetClaim(adt.AsStore(rt), miner)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claim for miner %v", miner)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no claim for miner %v", miner)
+	}
+	return claim
+}
+
+type ReconcileClaimParams struct {
+	Miner addr.Address
+	// The authoritative power of the miner's sectors, which replaces its claimed power.
+	RawBytePower    abi.StoragePower
+	QualityAdjPower abi.StoragePower
+}
+
+type ReconcileClaimReturn struct {
+	// The claim before reconciliation.
+	PreviousClaim Claim
+}
+
+// Replaces a miner's claimed power with an authoritative value, adjusting the network totals to match.
+// This corrects a claim which has drifted from the power of the miner's sectors, and may only be
+// invoked by network governance, the verified registry's root key.
+func (a Actor) ReconcileClaim(rt Runtime, params *ReconcileClaimParams) *ReconcileClaimReturn {
+	var rootKey addr.Address
+	code := rt.Send(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RootKey, nil, big.Zero(), &rootKey)
+	builtin.RequireSuccess(rt, code, "failed to query root key")
+	rt.ValidateImmediateCallerIs(rootKey)
+
+	minerAddr, ok := rt.ResolveAddress(params.Miner)
+	if !ok {
+		rt.Abortf(exitcode.ErrIllegalArgument, "failed to resolve miner address %v", params.Miner)
+	}
+	if params.RawBytePower.LessThan(big.Zero()) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "negative raw byte power %v", params.RawBytePower)
+	}
+	// Quality adjustment may increase power by at most the verified deal multiplier, and never decreases it.
+	maxQAPower := big.Div(big.Mul(params.RawBytePower, builtin.VerifiedDealWeightMultiplier), builtin.QualityBaseMultiplier)
+	if params.QualityAdjPower.LessThan(params.RawBytePower) || params.QualityAdjPower.GreaterThan(maxQAPower) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "quality adjusted power %v out of bounds [%v, %v]",
+			params.QualityAdjPower, params.RawBytePower, maxQAPower)
+	}
+
+	var st State
+	var previous Claim
+	rt.StateTransaction(&st, func() {
+		claims, err := adt.AsMap(adt.AsStore(rt), st.Claims, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
+
+		claim, found, err := getClaim(claims, minerAddr)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claim")
+		if !found {
+			rt.Abortf(exitcode.ErrNotFound, "no claim for miner %v", minerAddr)
+		}
+		previous = *claim
+
+		sectorSize, err := claim.WindowPoStProofType.SectorSize()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get sector size of miner %v", minerAddr)
+		if remainder := big.Mod(params.RawBytePower, big.NewIntUnsigned(uint64(sectorSize))); !remainder.IsZero() {
+			rt.Abortf(exitcode.ErrIllegalArgument, "raw byte power %v not a multiple of sector size %d", params.RawBytePower, sectorSize)
+		}
+
+		rawDelta := big.Sub(params.RawBytePower, claim.RawBytePower)
+		qaDelta := big.Sub(params.QualityAdjPower, claim.QualityAdjPower)
+		err = st.addToClaim(claims, minerAddr, rawDelta, qaDelta)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update power raw %s, qa %s", rawDelta, qaDelta)
+
+		st.Claims, err = claims.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush claims")
+	})
+
+	rt.Log(rtt.WARN, "reconciled claim of miner %v from raw %v, qa %v to raw %v, qa %v", minerAddr,
+		previous.RawBytePower, previous.QualityAdjPower, params.RawBytePower, params.QualityAdjPower)
+	return &ReconcileClaimReturn{PreviousClaim: previous}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Method utility functions
 ////////////////////////////////////////////////////////////////////////////////
@@ -484,6 +599,7 @@ func (a Actor) processDeferredCronEvents(rt Runtime, rewret reward.ThisEpochRewa
 		claims, err := adt.AsMap(adt.AsStore(rt), st.Claims, builtin.DefaultHamtBitwidth)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
 
+		// Events are processed in order of epoch, then miner ID, then enrollment.
 		for epoch := st.FirstCronEpoch; epoch <= rtEpoch; epoch++ {
 			epochEvents, err := loadCronEvents(events, epoch)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load cron events at %v", epoch)
@@ -3,6 +3,7 @@ package power
 import (
 	"fmt"
 	"reflect"
+	"sort"
 
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
@@ -298,14 +299,37 @@ func (st *State) updateSmoothedEstimate(delta abi.ChainEpoch) {
 	st.ThisEpochQAPowerSmoothed = filterQAPower.NextEstimate(st.ThisEpochQualityAdjPower, delta)
 }
 
+// Loads the events enrolled for an epoch, ordered by miner ID. A miner's events are ordered by enrollment.
+// Processing order is thus independent of the order in which different miners enrolled.
 func loadCronEvents(mmap *adt.Multimap, epoch abi.ChainEpoch) ([]CronEvent, error) {
 	var events []CronEvent
+	var minerIDs []uint64
 	var ev CronEvent
 	err := mmap.ForEach(epochKey(epoch), &ev, func(i int64) error {
+		id, err := addr.IDFromAddress(ev.MinerAddr)
+		if err != nil {
+			return xerrors.Errorf("cron event for non-ID miner address %v: %w", ev.MinerAddr, err)
+		}
 		events = append(events, ev)
+		minerIDs = append(minerIDs, id)
 		return nil
 	})
-	return events, err
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return minerIDs[order[i]] < minerIDs[order[j]]
+	})
+	sorted := make([]CronEvent, len(events))
+	for i, idx := range order {
+		sorted[i] = events[idx]
+	}
+	return sorted, nil
 }
 
 func setClaim(claims *adt.Map, a addr.Address, claim *Claim) error {
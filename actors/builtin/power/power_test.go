@@ -77,6 +77,40 @@ func TestConstruction(t *testing.T) {
 	})
 }
 
+func TestMinerClaim(t *testing.T) {
+	actor := newHarness(t)
+	owner := tutil.NewIDAddr(t, 101)
+	miner := tutil.NewIDAddr(t, 103)
+	actr := tutil.NewActorAddr(t, "actor")
+
+	builder := mock.NewBuilder(builtin.StoragePowerActorAddr).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	t.Run("zero claim registered on creation", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.createMiner(rt, owner, owner, miner, actr, abi.PeerID("miner"), nil,
+			abi.RegisteredPoStProof_StackedDrgWindow32GiBV1, abi.NewTokenAmount(10))
+
+		rt.AddIDAddress(actr, miner)
+		expected := &power.Claim{abi.RegisteredPoStProof_StackedDrgWindow32GiBV1, big.Zero(), big.Zero()}
+		assert.Equal(t, expected, actor.minerClaim(rt, miner))
+		// The claim may also be queried by the miner's robust address.
+		assert.Equal(t, expected, actor.minerClaim(rt, actr))
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for a miner with no claim", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.Actor.MinerClaim, &miner)
+		})
+		rt.Verify()
+	})
+}
+
 func TestCreateMinerFailures(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	peer := abi.PeerID("miner")
@@ -538,6 +572,170 @@ func TestUpdatePledgeTotal(t *testing.T) {
 	})
 }
 
+func TestCurrentPledgeBase(t *testing.T) {
+	actor := newHarness(t)
+	owner := tutil.NewIDAddr(t, 101)
+	miner := tutil.NewIDAddr(t, 111)
+	builder := mock.NewBuilder(builtin.StoragePowerActorAddr).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	t.Run("returns the network inputs to initial pledge", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		circSupply := big.Mul(big.NewInt(1e9), big.NewInt(1e18))
+		rt.SetCirculatingSupply(circSupply)
+
+		// Before any cron tick the pledge is zero and the smoothed power is the initial estimate.
+		ret := actor.currentPledgeBase(rt)
+		assert.Equal(t, circSupply, ret.CirculatingSupply)
+		assert.Equal(t, big.Zero(), ret.PledgeCollateral)
+		assert.Equal(t, smoothing.NewEstimate(power.InitialQAPowerEstimatePosition, power.InitialQAPowerEstimateVelocity),
+			ret.QualityAdjPowerSmoothed)
+
+		// Pledge and power updates are not reflected until the next cron tick.
+		powerUnit, err := builtin.ConsensusMinerMinPower(abi.RegisteredPoStProof_StackedDrgWindow2KiBV1)
+		require.NoError(t, err)
+		actor.createMinerBasic(rt, owner, owner, miner)
+		actor.updateClaimedPower(rt, miner, powerUnit, powerUnit)
+		actor.updatePledgeTotal(rt, miner, abi.NewTokenAmount(1e18))
+		assert.Equal(t, ret, actor.currentPledgeBase(rt))
+
+		actor.onEpochTickEnd(rt, 0, powerUnit, nil, nil)
+		afterTick := actor.currentPledgeBase(rt)
+		assert.Equal(t, abi.NewTokenAmount(1e18), afterTick.PledgeCollateral)
+		assert.NotEqual(t, ret.QualityAdjPowerSmoothed, afterTick.QualityAdjPowerSmoothed)
+
+		// The returned values reproduce the pledge a miner computes from the network state.
+		st := getState(rt)
+		assert.Equal(t, st.ThisEpochPledgeCollateral, afterTick.PledgeCollateral)
+		assert.Equal(t, st.ThisEpochQAPowerSmoothed, afterTick.QualityAdjPowerSmoothed)
+		totals := actor.currentPowerTotal(rt)
+		sectorPower := abi.NewStoragePower(32 << 30)
+		expected := mineract.InitialPledgeForPower(sectorPower, actor.thisEpochBaselinePower, actor.thisEpochRewardSmoothed,
+			totals.QualityAdjPowerSmoothed, rt.TotalFilCircSupply())
+		pledge := mineract.InitialPledgeForPower(sectorPower, actor.thisEpochBaselinePower, actor.thisEpochRewardSmoothed,
+			afterTick.QualityAdjPowerSmoothed, afterTick.CirculatingSupply)
+		assert.Equal(t, expected, pledge)
+		assert.True(t, pledge.GreaterThan(big.Zero()))
+
+		// A further pledge is reflected after the following tick, as is a change in circulating supply.
+		actor.updatePledgeTotal(rt, miner, abi.NewTokenAmount(5e17))
+		actor.onEpochTickEnd(rt, 1, powerUnit, nil, nil)
+		rt.SetCirculatingSupply(big.Mul(big.NewInt(2), circSupply))
+		ret = actor.currentPledgeBase(rt)
+		assert.Equal(t, big.Mul(big.NewInt(2), circSupply), ret.CirculatingSupply)
+		assert.Equal(t, abi.NewTokenAmount(15e17), ret.PledgeCollateral)
+		assert.NotEqual(t, afterTick.QualityAdjPowerSmoothed, ret.QualityAdjPowerSmoothed)
+		actor.checkState(rt)
+	})
+}
+
+func TestReconcileClaim(t *testing.T) {
+	actor := newHarness(t)
+	owner := tutil.NewIDAddr(t, 101)
+	miner1 := tutil.NewIDAddr(t, 111)
+	miner2 := tutil.NewIDAddr(t, 112)
+	builder := mock.NewBuilder(builtin.StoragePowerActorAddr).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	powerUnit, err := builtin.ConsensusMinerMinPower(actor.windowPoStProof)
+	require.NoError(t, err)
+	mul := func(a big.Int, b int64) big.Int {
+		return big.Mul(a, big.NewInt(b))
+	}
+
+	setup := func(t *testing.T) *mock.Runtime {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.createMinerBasic(rt, owner, owner, miner1)
+		actor.createMinerBasic(rt, owner, owner, miner2)
+		actor.updateClaimedPower(rt, miner2, powerUnit, powerUnit)
+		return rt
+	}
+
+	t.Run("corrects an overstated claim", func(t *testing.T) {
+		rt := setup(t)
+		// The miner's sectors have one power unit, but a bug has credited it with three.
+		actor.updateClaimedPower(rt, miner1, mul(powerUnit, 3), mul(powerUnit, 6))
+		actor.expectTotalPowerEager(rt, mul(powerUnit, 4), mul(powerUnit, 7))
+
+		ret := actor.reconcileClaim(rt, miner1, powerUnit, mul(powerUnit, 2))
+		assert.Equal(t, mul(powerUnit, 3), ret.PreviousClaim.RawBytePower)
+		assert.Equal(t, mul(powerUnit, 6), ret.PreviousClaim.QualityAdjPower)
+
+		claim := actor.getClaim(rt, miner1)
+		assert.Equal(t, powerUnit, claim.RawBytePower)
+		assert.Equal(t, mul(powerUnit, 2), claim.QualityAdjPower)
+		assert.Equal(t, actor.windowPoStProof, claim.WindowPoStProofType)
+		actor.expectTotalPowerEager(rt, mul(powerUnit, 2), mul(powerUnit, 3))
+		actor.expectMinersAboveMinPower(rt, 2)
+		actor.checkState(rt)
+	})
+
+	t.Run("corrects a claim across the consensus minimum", func(t *testing.T) {
+		rt := setup(t)
+		// The miner's sectors have one power unit, but a bug has lost all but one sector of it.
+		sectorSize, err := actor.windowPoStProof.SectorSize()
+		require.NoError(t, err)
+		sectorPower := big.NewIntUnsigned(uint64(sectorSize))
+		actor.updateClaimedPower(rt, miner1, sectorPower, sectorPower)
+		actor.expectMinersAboveMinPower(rt, 1)
+
+		actor.reconcileClaim(rt, miner1, powerUnit, powerUnit)
+		actor.expectMinersAboveMinPower(rt, 2)
+		st := getState(rt)
+		assert.Equal(t, mul(powerUnit, 2), st.TotalRawBytePower)
+		assert.Equal(t, mul(powerUnit, 2), st.TotalBytesCommitted)
+
+		actor.reconcileClaim(rt, miner1, big.Zero(), big.Zero())
+		actor.expectMinersAboveMinPower(rt, 1)
+		st = getState(rt)
+		assert.Equal(t, powerUnit, st.TotalRawBytePower)
+		assert.Equal(t, powerUnit, st.TotalBytesCommitted)
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects invalid reconciliation", func(t *testing.T) {
+		rt := setup(t)
+		sectorSize, err := actor.windowPoStProof.SectorSize()
+		require.NoError(t, err)
+		sectorPower := big.NewIntUnsigned(uint64(sectorSize))
+
+		for _, tc := range []struct {
+			name     string
+			miner    addr.Address
+			raw, qa  abi.StoragePower
+			code     exitcode.ExitCode
+			expected string
+		}{
+			{"unknown miner", tutil.NewIDAddr(t, 999), powerUnit, powerUnit, exitcode.ErrNotFound, "no claim"},
+			{"negative power", miner1, sectorPower.Neg(), sectorPower.Neg(), exitcode.ErrIllegalArgument, "negative raw byte power"},
+			{"quality adjusted power below raw", miner1, powerUnit, big.Sub(powerUnit, sectorPower), exitcode.ErrIllegalArgument, "out of bounds"},
+			{"quality adjusted power above maximum", miner1, sectorPower, mul(sectorPower, 11), exitcode.ErrIllegalArgument, "out of bounds"},
+			{"partial sector", miner1, big.Add(powerUnit, big.NewInt(1)), mul(powerUnit, 2), exitcode.ErrIllegalArgument, "not a multiple of sector size"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				rt.SetCaller(actor.rootKey, builtin.AccountActorCodeID)
+				actor.expectQueryRootKey(rt)
+				rt.ExpectValidateCallerAddr(actor.rootKey)
+				rt.ExpectAbortContainsMessage(tc.code, tc.expected, func() {
+					rt.Call(actor.ReconcileClaim, &power.ReconcileClaimParams{Miner: tc.miner, RawBytePower: tc.raw, QualityAdjPower: tc.qa})
+				})
+				rt.Verify()
+			})
+		}
+
+		// Only the root key may reconcile claims.
+		rt.SetCaller(owner, builtin.AccountActorCodeID)
+		actor.expectQueryRootKey(rt)
+		rt.ExpectValidateCallerAddr(actor.rootKey)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.ReconcileClaim, &power.ReconcileClaimParams{Miner: miner1, RawBytePower: powerUnit, QualityAdjPower: powerUnit})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
 func TestCron(t *testing.T) {
 	actor := newHarness(t)
 	miner1 := tutil.NewIDAddr(t, 101)
@@ -640,6 +838,50 @@ func TestCron(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("events for an epoch are processed in order of miner ID then enrollment", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.createMinerBasic(rt, owner, owner, miner1)
+		actor.createMinerBasic(rt, owner, owner, miner2)
+
+		rt.SetEpoch(1)
+		actor.enrollCronEvent(rt, miner2, 2, []byte{0x2, 0x1})
+		actor.enrollCronEvent(rt, miner1, 2, []byte{0x1, 0x1})
+		actor.enrollCronEvent(rt, miner2, 2, []byte{0x2, 0x2})
+		actor.enrollCronEvent(rt, miner1, 2, []byte{0x1, 0x2})
+
+		expectedRawBytePower := big.NewInt(0)
+		rt.SetEpoch(2)
+		rt.ExpectValidateCallerAddr(builtin.CronActorAddr)
+		expectQueryNetworkInfo(rt, actor)
+		st := getState(rt)
+
+		for _, evt := range []struct {
+			miner   addr.Address
+			payload []byte
+		}{
+			{miner1, []byte{0x1, 0x1}},
+			{miner1, []byte{0x1, 0x2}},
+			{miner2, []byte{0x2, 0x1}},
+			{miner2, []byte{0x2, 0x2}},
+		} {
+			params := builtin.DeferredCronEventParams{
+				EventPayload:            evt.payload,
+				RewardSmoothed:          actor.thisEpochRewardSmoothed,
+				QualityAdjPowerSmoothed: st.ThisEpochQAPowerSmoothed,
+			}
+			rt.ExpectSend(evt.miner, builtin.MethodsMiner.OnDeferredCronEvent, &params, big.Zero(), nil, exitcode.Ok)
+		}
+
+		rt.ExpectSend(builtin.RewardActorAddr, builtin.MethodsReward.UpdateNetworkKPI, &expectedRawBytePower, big.Zero(), nil, exitcode.Ok)
+		rt.SetCaller(builtin.CronActorAddr, builtin.CronActorCodeID)
+		rt.ExpectBatchVerifySeals(nil, nil, nil)
+
+		rt.Call(actor.Actor.CronTick, nil)
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
 	t.Run("event scheduled in past called next round", func(t *testing.T) {
 		rt := builder.Build(t)
 		actor.constructAndVerify(rt)
@@ -1145,6 +1387,7 @@ type spActorHarness struct {
 	windowPoStProof         abi.RegisteredPoStProof
 	thisEpochBaselinePower  big.Int
 	thisEpochRewardSmoothed smoothing.FilterEstimate
+	rootKey                 addr.Address
 }
 
 func newHarness(t *testing.T) *spActorHarness {
@@ -1157,6 +1400,7 @@ func newHarness(t *testing.T) *spActorHarness {
 		windowPoStProof:         abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
 		thisEpochBaselinePower:  abi.NewStoragePower(1 << 50),
 		thisEpochRewardSmoothed: smoothing.TestingConstantEstimate(rwd),
+		rootKey:                 tutil.NewIDAddr(t, 80),
 	}
 }
 
@@ -1253,8 +1497,10 @@ func (h *spActorHarness) createMiner(rt *mock.Runtime, owner, worker, miner, rob
 		ConstructorParams: initCreateMinerBytes(h.t, owner, worker, peer, multiaddrs, windowPoStProofType),
 	}
 	rt.ExpectSend(builtin.InitActorAddr, builtin.MethodsInit.Exec, msgParams, value, createMinerRet, 0)
-	rt.Call(h.Actor.CreateMiner, createMinerParams)
+	ret := rt.Call(h.Actor.CreateMiner, createMinerParams)
 	rt.Verify()
+	require.Equal(h.t, createMinerRet, ret)
+	rt.ExpectLogsContain(fmt.Sprintf("created miner %v with initial claim: proof type %v, raw 0, qa 0", miner, windowPoStProofType))
 
 	cl := h.getClaim(rt, miner)
 	require.True(h.t, cl.RawBytePower.IsZero())
@@ -1263,6 +1509,13 @@ func (h *spActorHarness) createMiner(rt *mock.Runtime, owner, worker, miner, rob
 
 }
 
+func (h *spActorHarness) minerClaim(rt *mock.Runtime, miner addr.Address) *power.Claim {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.Actor.MinerClaim, &miner).(*power.Claim)
+	rt.Verify()
+	return ret
+}
+
 func (h *spActorHarness) getClaim(rt *mock.Runtime, a addr.Address) *power.Claim {
 	var st power.State
 	rt.GetState(&st)
@@ -1376,6 +1629,32 @@ func (h *spActorHarness) currentPowerTotal(rt *mock.Runtime) *power.CurrentTotal
 	return ret
 }
 
+func (h *spActorHarness) currentPledgeBase(rt *mock.Runtime) *power.CurrentPledgeBaseReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.CurrentPledgeBase, nil).(*power.CurrentPledgeBaseReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *spActorHarness) reconcileClaim(rt *mock.Runtime, miner addr.Address, raw, qa abi.StoragePower) *power.ReconcileClaimReturn {
+	params := power.ReconcileClaimParams{
+		Miner:           miner,
+		RawBytePower:    raw,
+		QualityAdjPower: qa,
+	}
+	rt.SetCaller(h.rootKey, builtin.AccountActorCodeID)
+	h.expectQueryRootKey(rt)
+	rt.ExpectValidateCallerAddr(h.rootKey)
+	ret := rt.Call(h.ReconcileClaim, &params).(*power.ReconcileClaimReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *spActorHarness) expectQueryRootKey(rt *mock.Runtime) {
+	rootKey := h.rootKey
+	rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RootKey, nil, big.Zero(), &rootKey, exitcode.Ok)
+}
+
 func (h *spActorHarness) enrollCronEvent(rt *mock.Runtime, miner addr.Address, epoch abi.ChainEpoch, payload []byte) {
 	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
 	rt.SetCaller(miner, builtin.StorageMinerActorCodeID)
@@ -20,9 +20,10 @@ type ClaimsByAddress map[address.Address]Claim
 type ProofsByAddress map[address.Address][]proof.SealVerifyInfo
 
 type StateSummary struct {
-	Crons  CronEventsByAddress
-	Claims ClaimsByAddress
-	Proofs ProofsByAddress
+	Crons                 CronEventsByAddress
+	Claims                ClaimsByAddress
+	Proofs                ProofsByAddress
+	TotalPledgeCollateral abi.TokenAmount
 }
 
 // Checks internal invariants of power state.
@@ -49,9 +50,10 @@ func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.M
 	proofs := CheckProofValidationInvariants(st, store, claims, acc)
 
 	return &StateSummary{
-		Crons:  crons,
-		Claims: claims,
-		Proofs: proofs,
+		Crons:                 crons,
+		Claims:                claims,
+		Proofs:                proofs,
+		TotalPledgeCollateral: st.TotalPledgeCollateral,
 	}, acc
 }
 
@@ -246,3 +246,545 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	}
 	return nil
 }
+
+var lengthBufCumulativeRewardReturn = []byte{130}
+
+func (t *CumulativeRewardReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufCumulativeRewardReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.TotalStoragePowerReward (big.Int) (struct)
+	if err := t.TotalStoragePowerReward.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *CumulativeRewardReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = CumulativeRewardReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.TotalStoragePowerReward (big.Int) (struct)
+
+	{
+
+		if err := t.TotalStoragePowerReward.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.TotalStoragePowerReward: %w", err)
+		}
+
+	}
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufThisEpochRewardBreakdownReturn = []byte{131}
+
+func (t *ThisEpochRewardBreakdownReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufThisEpochRewardBreakdownReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.SimpleReward (big.Int) (struct)
+	if err := t.SimpleReward.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.BaselineReward (big.Int) (struct)
+	if err := t.BaselineReward.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ThisEpochRewardBreakdownReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = ThisEpochRewardBreakdownReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.SimpleReward (big.Int) (struct)
+
+	{
+
+		if err := t.SimpleReward.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.SimpleReward: %w", err)
+		}
+
+	}
+	// t.BaselineReward (big.Int) (struct)
+
+	{
+
+		if err := t.BaselineReward.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.BaselineReward: %w", err)
+		}
+
+	}
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufRewardFilterStateReturn = []byte{133}
+
+func (t *RewardFilterStateReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRewardFilterStateReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.PositionEstimate (big.Int) (struct)
+	if err := t.PositionEstimate.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.VelocityEstimate (big.Int) (struct)
+	if err := t.VelocityEstimate.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Alpha (big.Int) (struct)
+	if err := t.Alpha.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Beta (big.Int) (struct)
+	if err := t.Beta.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *RewardFilterStateReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = RewardFilterStateReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 5 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.PositionEstimate (big.Int) (struct)
+
+	{
+
+		if err := t.PositionEstimate.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.PositionEstimate: %w", err)
+		}
+
+	}
+	// t.VelocityEstimate (big.Int) (struct)
+
+	{
+
+		if err := t.VelocityEstimate.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.VelocityEstimate: %w", err)
+		}
+
+	}
+	// t.Alpha (big.Int) (struct)
+
+	{
+
+		if err := t.Alpha.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Alpha: %w", err)
+		}
+
+	}
+	// t.Beta (big.Int) (struct)
+
+	{
+
+		if err := t.Beta.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Beta: %w", err)
+		}
+
+	}
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufLatestEpochRewardReturn = []byte{131}
+
+func (t *LatestEpochRewardReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufLatestEpochRewardReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Reward (big.Int) (struct)
+	if err := t.Reward.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.BaselinePower (big.Int) (struct)
+	if err := t.BaselinePower.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *LatestEpochRewardReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = LatestEpochRewardReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	// t.Reward (big.Int) (struct)
+
+	{
+
+		if err := t.Reward.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Reward: %w", err)
+		}
+
+	}
+	// t.BaselinePower (big.Int) (struct)
+
+	{
+
+		if err := t.BaselinePower.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.BaselinePower: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufMintingDecayScheduleReturn = []byte{133}
+
+func (t *MintingDecayScheduleReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufMintingDecayScheduleReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Lambda (big.Int) (struct)
+	if err := t.Lambda.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ExpLamSubOne (big.Int) (struct)
+	if err := t.ExpLamSubOne.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.SimpleTotal (big.Int) (struct)
+	if err := t.SimpleTotal.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.BaselineTotal (big.Int) (struct)
+	if err := t.BaselineTotal.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *MintingDecayScheduleReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = MintingDecayScheduleReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 5 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Lambda (big.Int) (struct)
+
+	{
+
+		if err := t.Lambda.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Lambda: %w", err)
+		}
+
+	}
+	// t.ExpLamSubOne (big.Int) (struct)
+
+	{
+
+		if err := t.ExpLamSubOne.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ExpLamSubOne: %w", err)
+		}
+
+	}
+	// t.SimpleTotal (big.Int) (struct)
+
+	{
+
+		if err := t.SimpleTotal.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.SimpleTotal: %w", err)
+		}
+
+	}
+	// t.BaselineTotal (big.Int) (struct)
+
+	{
+
+		if err := t.BaselineTotal.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.BaselineTotal: %w", err)
+		}
+
+	}
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
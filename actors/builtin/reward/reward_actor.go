@@ -12,6 +12,7 @@ import (
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/smoothing"
 )
 
 // PenaltyMultiplier is the factor miner penaltys are scaled up by
@@ -25,6 +26,11 @@ func (a Actor) Exports() []interface{} {
 		2:                         a.AwardBlockReward,
 		3:                         a.ThisEpochReward,
 		4:                         a.UpdateNetworkKPI,
+		5:                         a.CumulativeReward,
+		6:                         a.ThisEpochRewardBreakdown,
+		7:                         a.RewardFilterState,
+		8:                         a.LatestEpochReward,
+		9:                         a.MintingDecaySchedule,
 	}
 }
 
@@ -154,6 +160,128 @@ func (a Actor) ThisEpochReward(rt runtime.Runtime, _ *abi.EmptyValue) *ThisEpoch
 	}
 }
 
+type CumulativeRewardReturn struct {
+	// Total block reward paid to storage miners since genesis, excluding gas rewards.
+	TotalStoragePowerReward abi.TokenAmount
+	// The epoch at which the total was read, which includes any rewards already awarded in this epoch.
+	Epoch abi.ChainEpoch
+}
+
+// Returns the cumulative block reward awarded to storage miners up to the current epoch.
+func (a Actor) CumulativeReward(rt runtime.Runtime, _ *abi.EmptyValue) *CumulativeRewardReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	return &CumulativeRewardReturn{
+		TotalStoragePowerReward: st.TotalStoragePowerReward,
+		Epoch:                   rt.CurrEpoch(),
+	}
+}
+
+type ThisEpochRewardBreakdownReturn struct {
+	// The portion of ThisEpochReward minted by the simple exponential-decay schedule.
+	SimpleReward abi.TokenAmount
+	// The portion of ThisEpochReward minted by the baseline schedule, as the network's
+	// effective time advances.
+	BaselineReward abi.TokenAmount
+	// The epoch for which the reward was computed.
+	Epoch abi.ChainEpoch
+}
+
+// Returns the simple and baseline minting components of the reward for this epoch, which
+// sum to the (unsmoothed) epoch reward.
+func (a Actor) ThisEpochRewardBreakdown(rt runtime.Runtime, _ *abi.EmptyValue) *ThisEpochRewardBreakdownReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	simple, baseline := st.ThisEpochRewardComponents()
+	return &ThisEpochRewardBreakdownReturn{
+		SimpleReward:   simple,
+		BaselineReward: baseline,
+		Epoch:          st.Epoch,
+	}
+}
+
+type RewardFilterStateReturn struct {
+	// The smoothed estimate of the per-epoch reward used in pledge and penalty calculations, as Q.128 values.
+	PositionEstimate big.Int
+	VelocityEstimate big.Int
+	// The Q.128 coefficients with which the filter incorporates each new reward.
+	Alpha big.Int
+	Beta  big.Int
+	// The epoch at which the estimate was last updated.
+	Epoch abi.ChainEpoch
+}
+
+// Returns the state of the alpha-beta filter smoothing the epoch reward, with the filter coefficients,
+// so that the smoothed estimate can be reproduced and extrapolated exactly off-chain.
+func (a Actor) RewardFilterState(rt runtime.Runtime, _ *abi.EmptyValue) *RewardFilterStateReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	return &RewardFilterStateReturn{
+		PositionEstimate: st.ThisEpochRewardSmoothed.PositionEstimate,
+		VelocityEstimate: st.ThisEpochRewardSmoothed.VelocityEstimate,
+		Alpha:            smoothing.DefaultAlpha,
+		Beta:             smoothing.DefaultBeta,
+		Epoch:            st.Epoch,
+	}
+}
+
+type LatestEpochRewardReturn struct {
+	// The most recent epoch for which the reward was computed.
+	Epoch abi.ChainEpoch
+	// The (unsmoothed) reward computed for that epoch, per unit of win count.
+	Reward abi.TokenAmount
+	// The baseline power the network targeted at that epoch.
+	BaselinePower abi.StoragePower
+}
+
+// Returns the values computed by the most recent network KPI update, so that indexers can
+// record each epoch's reward and baseline without reconstructing the minting function.
+func (a Actor) LatestEpochReward(rt runtime.Runtime, _ *abi.EmptyValue) *LatestEpochRewardReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	return &LatestEpochRewardReturn{
+		Epoch:         st.Epoch,
+		Reward:        st.ThisEpochReward,
+		BaselinePower: st.ThisEpochBaselinePower,
+	}
+}
+
+type MintingDecayScheduleReturn struct {
+	// The Q.128 rate lambda at which minting decays per epoch, such that it halves every six years.
+	Lambda big.Int
+	// The Q.128 decay constant e^lambda - 1, which scales SimpleTotal to the simple reward at epoch zero.
+	ExpLamSubOne big.Int
+	// The total supply to be minted by the simple and baseline schedules over all time.
+	SimpleTotal   abi.TokenAmount
+	BaselineTotal abi.TokenAmount
+	// The most recent epoch for which the reward was computed.
+	Epoch abi.ChainEpoch
+}
+
+// Returns the parameters of the exponential decay of minting, so that off-chain projections of the
+// reward match the actor exactly. The simple reward for epoch t is SimpleTotal * ExpLamSubOne * e^(-Lambda*t).
+func (a Actor) MintingDecaySchedule(rt runtime.Runtime, _ *abi.EmptyValue) *MintingDecayScheduleReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	return &MintingDecayScheduleReturn{
+		Lambda:        Lambda,
+		ExpLamSubOne:  ExpLamSubOne,
+		SimpleTotal:   st.SimpleTotal,
+		BaselineTotal: st.BaselineTotal,
+		Epoch:         st.Epoch,
+	}
+}
+
 // Called at the end of each epoch by the power actor (in turn by its cron hook).
 // This is only invoked for non-empty tipsets, but catches up any number of null
 // epochs to compute the next epoch reward.
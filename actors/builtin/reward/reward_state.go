@@ -4,6 +4,7 @@ import (
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 
+	"github.com/filecoin-project/specs-actors/v8/actors/util/math"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/smoothing"
 )
 
@@ -112,6 +113,13 @@ func (st *State) updateToNextEpochWithReward(currRealizedPower abi.StoragePower)
 	st.ThisEpochReward = computeReward(st.Epoch, prevRewardTheta, currRewardTheta, st.SimpleTotal, st.BaselineTotal)
 }
 
+// Splits ThisEpochReward into its simple and baseline minting components.
+// The baseline component absorbs any rounding, so the two always sum to ThisEpochReward.
+func (st *State) ThisEpochRewardComponents() (simple, baseline abi.TokenAmount) {
+	simple = big.Rsh(computeSimpleReward(st.Epoch, st.SimpleTotal), math.Precision128) // Q.128 => Q.0
+	return simple, big.Sub(st.ThisEpochReward, simple)
+}
+
 func (st *State) updateSmoothedEstimates(delta abi.ChainEpoch) {
 	filterReward := smoothing.LoadFilter(st.ThisEpochRewardSmoothed, smoothing.DefaultAlpha, smoothing.DefaultBeta)
 	st.ThisEpochRewardSmoothed = filterReward.NextEstimate(st.ThisEpochReward, delta)
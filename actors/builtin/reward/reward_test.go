@@ -11,7 +11,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/reward"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/math"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/smoothing"
 	"github.com/filecoin-project/specs-actors/v8/support/mock"
 	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
 )
@@ -233,6 +236,180 @@ func TestThisEpochReward(t *testing.T) {
 	})
 }
 
+func TestCumulativeReward(t *testing.T) {
+	actor := rewardHarness{reward.Actor{}, t}
+	builder := mock.NewBuilder(builtin.RewardActorAddr).
+		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID).
+		WithBalance(reward.StorageMiningAllocationCheck, abi.NewTokenAmount(0))
+	rt := builder.Build(t)
+	power := abi.NewStoragePower(1 << 50)
+	actor.constructAndVerify(rt, &power)
+	miner := tutil.NewIDAddr(t, 1000)
+
+	ret := actor.cumulativeReward(rt)
+	assert.Equal(t, big.Zero(), ret.TotalStoragePowerReward)
+
+	prev := ret.TotalStoragePowerReward
+	for epoch := abi.ChainEpoch(1); epoch <= 5; epoch++ {
+		rt.SetEpoch(epoch)
+		actor.updateNetworkKPI(rt, &power)
+
+		st := getState(rt)
+		expectedReward := big.Div(st.ThisEpochReward, big.NewInt(builtin.ExpectedLeadersPerEpoch))
+		rt.SetCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+		actor.awardBlockReward(rt, miner, big.Zero(), big.Zero(), 1, expectedReward)
+
+		ret = actor.cumulativeReward(rt)
+		assert.Equal(t, epoch, ret.Epoch)
+		assert.True(t, ret.TotalStoragePowerReward.GreaterThan(prev), "total %v did not increase from %v", ret.TotalStoragePowerReward, prev)
+		assert.True(t, big.Add(prev, expectedReward).Equals(ret.TotalStoragePowerReward))
+		prev = ret.TotalStoragePowerReward
+	}
+}
+
+func TestThisEpochRewardBreakdown(t *testing.T) {
+	actor := rewardHarness{reward.Actor{}, t}
+	builder := mock.NewBuilder(builtin.RewardActorAddr).
+		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	rt := builder.Build(t)
+	power := abi.NewStoragePower(1 << 50)
+	actor.constructAndVerify(rt, &power)
+
+	for epoch := abi.ChainEpoch(1); epoch <= 5; epoch++ {
+		rt.SetEpoch(epoch)
+		actor.updateNetworkKPI(rt, &power)
+
+		ret := actor.thisEpochRewardBreakdown(rt)
+		st := getState(rt)
+		assert.Equal(t, st.Epoch, ret.Epoch)
+		assert.True(t, ret.SimpleReward.GreaterThan(big.Zero()))
+		assert.True(t, ret.BaselineReward.GreaterThan(big.Zero()))
+		assert.Equal(t, st.ThisEpochReward, big.Add(ret.SimpleReward, ret.BaselineReward))
+	}
+
+	t.Run("baseline component is zero when no power is realized", func(t *testing.T) {
+		rt := builder.Build(t)
+		zero := big.Zero()
+		actor.constructAndVerify(rt, &zero)
+		rt.SetEpoch(1)
+		actor.updateNetworkKPI(rt, &zero)
+
+		ret := actor.thisEpochRewardBreakdown(rt)
+		assert.True(t, ret.BaselineReward.IsZero())
+		assert.Equal(t, getState(rt).ThisEpochReward, ret.SimpleReward)
+	})
+}
+
+func TestRewardFilterState(t *testing.T) {
+	actor := rewardHarness{reward.Actor{}, t}
+	builder := mock.NewBuilder(builtin.RewardActorAddr).
+		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	rt := builder.Build(t)
+	power := abi.NewStoragePower(1 << 50)
+	actor.constructAndVerify(rt, &power)
+
+	// Epoch 5 follows null rounds, so the filter is advanced over several epochs at once.
+	for _, epoch := range []abi.ChainEpoch{1, 2, 5} {
+		prev := actor.rewardFilterState(rt)
+		rt.SetEpoch(epoch)
+		actor.updateNetworkKPI(rt, &power)
+
+		ret := actor.rewardFilterState(rt)
+		st := getState(rt)
+		assert.Equal(t, st.Epoch, ret.Epoch)
+
+		// The exposed estimate is the one miners receive for pledge calculations.
+		estimate := smoothing.FilterEstimate{PositionEstimate: ret.PositionEstimate, VelocityEstimate: ret.VelocityEstimate}
+		thisEpochReward := actor.thisEpochReward(rt)
+		assert.Equal(t, thisEpochReward.ThisEpochRewardSmoothed, estimate)
+		qaPower := abi.NewStoragePower(32 << 30)
+		powerEstimate := smoothing.NewEstimate(power, big.Zero())
+		circSupply := big.Mul(big.NewInt(1e9), big.NewInt(1e18))
+		assert.Equal(t,
+			miner.InitialPledgeForPower(qaPower, thisEpochReward.ThisEpochBaselinePower, thisEpochReward.ThisEpochRewardSmoothed, powerEstimate, circSupply),
+			miner.InitialPledgeForPower(qaPower, thisEpochReward.ThisEpochBaselinePower, estimate, powerEstimate, circSupply))
+
+		// The prior filter state and coefficients reproduce the update from the new epoch reward.
+		filter := smoothing.LoadFilter(smoothing.FilterEstimate{PositionEstimate: prev.PositionEstimate, VelocityEstimate: prev.VelocityEstimate}, ret.Alpha, ret.Beta)
+		assert.Equal(t, estimate, filter.NextEstimate(st.ThisEpochReward, ret.Epoch-prev.Epoch))
+	}
+}
+
+func TestLatestEpochReward(t *testing.T) {
+	actor := rewardHarness{reward.Actor{}, t}
+	builder := mock.NewBuilder(builtin.RewardActorAddr).
+		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	rt := builder.Build(t)
+	startRealizedPower := abi.NewStoragePower(0)
+	actor.constructAndVerify(rt, &startRealizedPower)
+
+	ret := actor.latestEpochReward(rt)
+	assert.Equal(t, abi.ChainEpoch(0), ret.Epoch)
+	assert.Equal(t, big.MustFromString(EpochZeroReward), ret.Reward)
+	assert.Equal(t, big.Sub(reward.BaselineInitialValue, big.NewInt(1)), ret.BaselinePower)
+
+	// Epoch 5 follows null rounds, over which the baseline continues to grow.
+	power := abi.NewStoragePower(1 << 50)
+	for _, epoch := range []abi.ChainEpoch{1, 2, 5} {
+		prev := ret
+		rt.SetEpoch(epoch)
+		actor.updateNetworkKPI(rt, &power)
+
+		// The update computes the reward for the epoch following the current one.
+		ret = actor.latestEpochReward(rt)
+		assert.Equal(t, epoch+1, ret.Epoch)
+		expectedBaseline := prev.BaselinePower
+		for e := prev.Epoch; e < ret.Epoch; e++ {
+			expectedBaseline = reward.BaselinePowerFromPrev(expectedBaseline)
+		}
+		assert.Equal(t, expectedBaseline, ret.BaselinePower)
+
+		// The reward is the sum of the simple and baseline minting components for the epoch.
+		breakdown := actor.thisEpochRewardBreakdown(rt)
+		assert.Equal(t, big.Add(breakdown.SimpleReward, breakdown.BaselineReward), ret.Reward)
+		assert.Equal(t, getState(rt).ThisEpochReward, ret.Reward)
+	}
+}
+
+func TestMintingDecaySchedule(t *testing.T) {
+	actor := rewardHarness{reward.Actor{}, t}
+	builder := mock.NewBuilder(builtin.RewardActorAddr).
+		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	rt := builder.Build(t)
+	power := abi.NewStoragePower(1 << 50)
+	actor.constructAndVerify(rt, &power)
+
+	// Projects the simple reward for an epoch from the exposed schedule.
+	project := func(sched *reward.MintingDecayScheduleReturn, epoch abi.ChainEpoch) abi.TokenAmount {
+		epochLam := big.Mul(big.NewInt(int64(epoch)), sched.Lambda)                                                  // Q.128
+		decayed := big.Mul(big.Mul(sched.SimpleTotal, sched.ExpLamSubOne), big.NewFromGo(math.ExpNeg(epochLam.Int))) // Q.256
+		return big.Rsh(decayed, 2*math.Precision128)
+	}
+
+	// Epoch 5 follows null rounds.
+	for _, epoch := range []abi.ChainEpoch{1, 2, 5, 10} {
+		rt.SetEpoch(epoch)
+		actor.updateNetworkKPI(rt, &power)
+
+		sched := actor.mintingDecaySchedule(rt)
+		assert.Equal(t, getState(rt).Epoch, sched.Epoch)
+		assert.Equal(t, reward.DefaultSimpleTotal, sched.SimpleTotal)
+		assert.Equal(t, reward.DefaultBaselineTotal, sched.BaselineTotal)
+
+		// The projection matches the simple component of the reward minted by the actor.
+		breakdown := actor.thisEpochRewardBreakdown(rt)
+		assert.Equal(t, breakdown.SimpleReward, project(sched, sched.Epoch))
+	}
+
+	// Simple minting halves every six years.
+	sched := actor.mintingDecaySchedule(rt)
+	sixYears := abi.ChainEpoch(6 * 365 * builtin.EpochsInDay)
+	first := project(sched, 0)
+	halved := project(sched, sixYears)
+	diff := big.Sub(big.Mul(halved, big.NewInt(2)), first)
+	assert.True(t, diff.Abs().LessThan(big.Div(first, big.NewInt(1000))), "expected %v to be half of %v", halved, first)
+}
+
 func TestSuccessiveKPIUpdates(t *testing.T) {
 	actor := rewardHarness{reward.Actor{}, t}
 	builder := mock.NewBuilder(builtin.RewardActorAddr).
@@ -289,6 +466,13 @@ func (h *rewardHarness) awardBlockReward(rt *mock.Runtime, miner address.Address
 	rt.Verify()
 }
 
+func (h *rewardHarness) cumulativeReward(rt *mock.Runtime) *reward.CumulativeRewardReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.CumulativeReward, nil).(*reward.CumulativeRewardReturn)
+	rt.Verify()
+	return ret
+}
+
 func (h *rewardHarness) thisEpochReward(rt *mock.Runtime) *reward.ThisEpochRewardReturn {
 	rt.ExpectValidateCallerAny()
 
@@ -305,3 +489,31 @@ func getState(rt *mock.Runtime) *reward.State {
 	rt.GetState(&st)
 	return &st
 }
+
+func (h *rewardHarness) rewardFilterState(rt *mock.Runtime) *reward.RewardFilterStateReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.RewardFilterState, nil).(*reward.RewardFilterStateReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *rewardHarness) thisEpochRewardBreakdown(rt *mock.Runtime) *reward.ThisEpochRewardBreakdownReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.ThisEpochRewardBreakdown, nil).(*reward.ThisEpochRewardBreakdownReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *rewardHarness) mintingDecaySchedule(rt *mock.Runtime) *reward.MintingDecayScheduleReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.MintingDecaySchedule, nil).(*reward.MintingDecayScheduleReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *rewardHarness) latestEpochReward(rt *mock.Runtime) *reward.LatestEpochRewardReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.LatestEpochReward, nil).(*reward.LatestEpochRewardReturn)
+	rt.Verify()
+	return ret
+}
@@ -6,13 +6,15 @@ import (
 	"fmt"
 	"io"
 
+	address "github.com/filecoin-project/go-address"
+	abi "github.com/filecoin-project/go-state-types/abi"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{132}
+var lengthBufState = []byte{137}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -48,6 +50,33 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		return xerrors.Errorf("failed to write cid field t.RemoveDataCapProposalIDs: %w", err)
 	}
 
+	// t.MinVerifiedDealSize (big.Int) (struct)
+	if err := t.MinVerifiedDealSize.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.AuditLog (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.AuditLog); err != nil {
+		return xerrors.Errorf("failed to write cid field t.AuditLog: %w", err)
+	}
+
+	// t.AuditLogNext (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.AuditLogNext)); err != nil {
+		return err
+	}
+
+	// t.VerifierClientCounts (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.VerifierClientCounts); err != nil {
+		return xerrors.Errorf("failed to write cid field t.VerifierClientCounts: %w", err)
+	}
+
+	// t.MaxClientDataCap (big.Int) (struct)
+	if err := t.MaxClientDataCap.MarshalCBOR(w); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -65,7 +94,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 4 {
+	if extra != 9 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -113,6 +142,62 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		t.RemoveDataCapProposalIDs = c
 
+	}
+	// t.MinVerifiedDealSize (big.Int) (struct)
+
+	{
+
+		if err := t.MinVerifiedDealSize.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MinVerifiedDealSize: %w", err)
+		}
+
+	}
+	// t.AuditLog (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.AuditLog: %w", err)
+		}
+
+		t.AuditLog = c
+
+	}
+	// t.AuditLogNext (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.AuditLogNext = uint64(extra)
+
+	}
+	// t.VerifierClientCounts (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.VerifierClientCounts: %w", err)
+		}
+
+		t.VerifierClientCounts = c
+
+	}
+	// t.MaxClientDataCap (big.Int) (struct)
+
+	{
+
+		if err := t.MaxClientDataCap.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MaxClientDataCap: %w", err)
+		}
+
 	}
 	return nil
 }
@@ -269,31 +354,92 @@ func (t *RemoveDataCapReturn) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
-var lengthBufRemoveDataCapRequest = []byte{130}
+var lengthBufSetMinVerifiedDealSizeParams = []byte{129}
 
-func (t *RemoveDataCapRequest) MarshalCBOR(w io.Writer) error {
+func (t *SetMinVerifiedDealSizeParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufRemoveDataCapRequest); err != nil {
+	if _, err := w.Write(lengthBufSetMinVerifiedDealSizeParams); err != nil {
 		return err
 	}
 
-	// t.Verifier (address.Address) (struct)
-	if err := t.Verifier.MarshalCBOR(w); err != nil {
+	// t.MinVerifiedDealSize (big.Int) (struct)
+	if err := t.MinVerifiedDealSize.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SetMinVerifiedDealSizeParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SetMinVerifiedDealSizeParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
 		return err
 	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
 
-	// t.VerifierSignature (crypto.Signature) (struct)
-	if err := t.VerifierSignature.MarshalCBOR(w); err != nil {
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.MinVerifiedDealSize (big.Int) (struct)
+
+	{
+
+		if err := t.MinVerifiedDealSize.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MinVerifiedDealSize: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufAuditLogReturn = []byte{130}
+
+func (t *AuditLogReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAuditLogReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Entries ([]verifreg.AuditLogEntry) (slice)
+	if len(t.Entries) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Entries was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Entries))); err != nil {
 		return err
 	}
+	for _, v := range t.Entries {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.TotalEntries (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.TotalEntries)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (t *RemoveDataCapRequest) UnmarshalCBOR(r io.Reader) error {
-	*t = RemoveDataCapRequest{}
+func (t *AuditLogReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = AuditLogReturn{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -310,57 +456,77 @@ func (t *RemoveDataCapRequest) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Verifier (address.Address) (struct)
+	// t.Entries ([]verifreg.AuditLogEntry) (slice)
 
-	{
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
 
-		if err := t.Verifier.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Verifier: %w", err)
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Entries: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Entries = make([]AuditLogEntry, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v AuditLogEntry
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
 		}
 
+		t.Entries[i] = v
 	}
-	// t.VerifierSignature (crypto.Signature) (struct)
+
+	// t.TotalEntries (uint64) (uint64)
 
 	{
 
-		if err := t.VerifierSignature.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.VerifierSignature: %w", err)
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
 		}
+		t.TotalEntries = uint64(extra)
 
 	}
 	return nil
 }
 
-var lengthBufRemoveDataCapProposal = []byte{131}
+var lengthBufRemoveVerifierAndReclaimParams = []byte{130}
 
-func (t *RemoveDataCapProposal) MarshalCBOR(w io.Writer) error {
+func (t *RemoveVerifierAndReclaimParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufRemoveDataCapProposal); err != nil {
-		return err
-	}
-
-	// t.VerifiedClient (address.Address) (struct)
-	if err := t.VerifiedClient.MarshalCBOR(w); err != nil {
+	if _, err := w.Write(lengthBufRemoveVerifierAndReclaimParams); err != nil {
 		return err
 	}
 
-	// t.DataCapAmount (big.Int) (struct)
-	if err := t.DataCapAmount.MarshalCBOR(w); err != nil {
+	// t.Verifier (address.Address) (struct)
+	if err := t.Verifier.MarshalCBOR(w); err != nil {
 		return err
 	}
 
-	// t.RemovalProposalID (verifreg.RmDcProposalID) (struct)
-	if err := t.RemovalProposalID.MarshalCBOR(w); err != nil {
+	// t.Recipient (address.Address) (struct)
+	if err := t.Recipient.MarshalCBOR(w); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (t *RemoveDataCapProposal) UnmarshalCBOR(r io.Reader) error {
-	*t = RemoveDataCapProposal{}
+func (t *RemoveVerifierAndReclaimParams) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveVerifierAndReclaimParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -373,64 +539,104 @@ func (t *RemoveDataCapProposal) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 3 {
+	if extra != 2 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.VerifiedClient (address.Address) (struct)
+	// t.Verifier (address.Address) (struct)
 
 	{
 
-		if err := t.VerifiedClient.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.VerifiedClient: %w", err)
+		if err := t.Verifier.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Verifier: %w", err)
 		}
 
 	}
-	// t.DataCapAmount (big.Int) (struct)
+	// t.Recipient (address.Address) (struct)
 
 	{
 
-		if err := t.DataCapAmount.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.DataCapAmount: %w", err)
+		if err := t.Recipient.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Recipient: %w", err)
 		}
 
 	}
-	// t.RemovalProposalID (verifreg.RmDcProposalID) (struct)
+	return nil
+}
+
+var lengthBufRemoveVerifierAndReclaimReturn = []byte{129}
+
+func (t *RemoveVerifierAndReclaimReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveVerifierAndReclaimReturn); err != nil {
+		return err
+	}
+
+	// t.Reclaimed (big.Int) (struct)
+	if err := t.Reclaimed.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RemoveVerifierAndReclaimReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveVerifierAndReclaimReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Reclaimed (big.Int) (struct)
 
 	{
 
-		if err := t.RemovalProposalID.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.RemovalProposalID: %w", err)
+		if err := t.Reclaimed.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Reclaimed: %w", err)
 		}
 
 	}
 	return nil
 }
 
-var lengthBufRmDcProposalID = []byte{129}
+var lengthBufTotalDataCapReturn = []byte{130}
 
-func (t *RmDcProposalID) MarshalCBOR(w io.Writer) error {
+func (t *TotalDataCapReturn) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufRmDcProposalID); err != nil {
+	if _, err := w.Write(lengthBufTotalDataCapReturn); err != nil {
 		return err
 	}
 
-	scratch := make([]byte, 9)
-
-	// t.ProposalID (uint64) (uint64)
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ProposalID)); err != nil {
+	// t.VerifierDataCap (big.Int) (struct)
+	if err := t.VerifierDataCap.MarshalCBOR(w); err != nil {
 		return err
 	}
 
+	// t.ClientDataCap (big.Int) (struct)
+	if err := t.ClientDataCap.MarshalCBOR(w); err != nil {
+		return err
+	}
 	return nil
 }
 
-func (t *RmDcProposalID) UnmarshalCBOR(r io.Reader) error {
-	*t = RmDcProposalID{}
+func (t *TotalDataCapReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = TotalDataCapReturn{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -443,22 +649,865 @@ func (t *RmDcProposalID) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 1 {
+	if extra != 2 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.ProposalID (uint64) (uint64)
+	// t.VerifierDataCap (big.Int) (struct)
 
 	{
 
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-		if err != nil {
-			return err
+		if err := t.VerifierDataCap.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.VerifierDataCap: %w", err)
 		}
-		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+
+	}
+	// t.ClientDataCap (big.Int) (struct)
+
+	{
+
+		if err := t.ClientDataCap.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ClientDataCap: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufCanUseBytesReturn = []byte{130}
+
+func (t *CanUseBytesReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufCanUseBytesReturn); err != nil {
+		return err
+	}
+
+	// t.Allowed (bool) (bool)
+	if err := cbg.WriteBool(w, t.Allowed); err != nil {
+		return err
+	}
+
+	// t.RemainingCap (big.Int) (struct)
+	if err := t.RemainingCap.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *CanUseBytesReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = CanUseBytesReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Allowed (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.Allowed = false
+	case 21:
+		t.Allowed = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	// t.RemainingCap (big.Int) (struct)
+
+	{
+
+		if err := t.RemainingCap.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.RemainingCap: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufVerifierClientCountReturn = []byte{129}
+
+func (t *VerifierClientCountReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufVerifierClientCountReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Count (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Count)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *VerifierClientCountReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = VerifierClientCountReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Count (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Count = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufRemoveDataCapRequest = []byte{130}
+
+func (t *RemoveDataCapRequest) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveDataCapRequest); err != nil {
+		return err
+	}
+
+	// t.Verifier (address.Address) (struct)
+	if err := t.Verifier.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.VerifierSignature (crypto.Signature) (struct)
+	if err := t.VerifierSignature.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RemoveDataCapRequest) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveDataCapRequest{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Verifier (address.Address) (struct)
+
+	{
+
+		if err := t.Verifier.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Verifier: %w", err)
+		}
+
+	}
+	// t.VerifierSignature (crypto.Signature) (struct)
+
+	{
+
+		if err := t.VerifierSignature.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.VerifierSignature: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufRemoveDataCapProposal = []byte{131}
+
+func (t *RemoveDataCapProposal) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveDataCapProposal); err != nil {
+		return err
+	}
+
+	// t.VerifiedClient (address.Address) (struct)
+	if err := t.VerifiedClient.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.DataCapAmount (big.Int) (struct)
+	if err := t.DataCapAmount.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.RemovalProposalID (verifreg.RmDcProposalID) (struct)
+	if err := t.RemovalProposalID.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RemoveDataCapProposal) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveDataCapProposal{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.VerifiedClient (address.Address) (struct)
+
+	{
+
+		if err := t.VerifiedClient.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.VerifiedClient: %w", err)
+		}
+
+	}
+	// t.DataCapAmount (big.Int) (struct)
+
+	{
+
+		if err := t.DataCapAmount.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.DataCapAmount: %w", err)
+		}
+
+	}
+	// t.RemovalProposalID (verifreg.RmDcProposalID) (struct)
+
+	{
+
+		if err := t.RemovalProposalID.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.RemovalProposalID: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufRmDcProposalID = []byte{129}
+
+func (t *RmDcProposalID) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRmDcProposalID); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.ProposalID (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ProposalID)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *RmDcProposalID) UnmarshalCBOR(r io.Reader) error {
+	*t = RmDcProposalID{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.ProposalID (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.ProposalID = uint64(extra)
+
+	}
+	return nil
+}
+
+var lengthBufAuditLogEntry = []byte{132}
+
+func (t *AuditLogEntry) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAuditLogEntry); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Action (verifreg.AuditAction) (int64)
+	if t.Action >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Action)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Action-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.Caller (address.Address) (struct)
+	if err := t.Caller.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Subject (address.Address) (struct)
+	if err := t.Subject.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *AuditLogEntry) UnmarshalCBOR(r io.Reader) error {
+	*t = AuditLogEntry{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 4 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Action (verifreg.AuditAction) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Action = AuditAction(extraI)
+	}
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	// t.Caller (address.Address) (struct)
+
+	{
+
+		if err := t.Caller.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Caller: %w", err)
+		}
+
+	}
+	// t.Subject (address.Address) (struct)
+
+	{
+
+		if err := t.Subject.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Subject: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufRemoveVerifiersParams = []byte{129}
+
+func (t *RemoveVerifiersParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveVerifiersParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Verifiers ([]address.Address) (slice)
+	if len(t.Verifiers) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Verifiers was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Verifiers))); err != nil {
+		return err
+	}
+	for _, v := range t.Verifiers {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *RemoveVerifiersParams) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveVerifiersParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Verifiers ([]address.Address) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Verifiers: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Verifiers = make([]address.Address, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v address.Address
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Verifiers[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufRemoveVerifiersReturn = []byte{130}
+
+func (t *RemoveVerifiersReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRemoveVerifiersReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Removed ([]address.Address) (slice)
+	if len(t.Removed) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Removed was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Removed))); err != nil {
+		return err
+	}
+	for _, v := range t.Removed {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	// t.Skipped ([]address.Address) (slice)
+	if len(t.Skipped) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Skipped was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Skipped))); err != nil {
+		return err
+	}
+	for _, v := range t.Skipped {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *RemoveVerifiersReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveVerifiersReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Removed ([]address.Address) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Removed: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Removed = make([]address.Address, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v address.Address
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Removed[i] = v
+	}
+
+	// t.Skipped ([]address.Address) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Skipped: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Skipped = make([]address.Address, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v address.Address
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Skipped[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufUseBytesReturn = []byte{131}
+
+func (t *UseBytesReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufUseBytesReturn); err != nil {
+		return err
+	}
+
+	// t.RemainingCap (big.Int) (struct)
+	if err := t.RemainingCap.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ClientRemoved (bool) (bool)
+	if err := cbg.WriteBool(w, t.ClientRemoved); err != nil {
+		return err
+	}
+
+	// t.Consumed (big.Int) (struct)
+	if err := t.Consumed.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *UseBytesReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = UseBytesReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.RemainingCap (big.Int) (struct)
+
+	{
+
+		if err := t.RemainingCap.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.RemainingCap: %w", err)
+		}
+
+	}
+	// t.ClientRemoved (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.ClientRemoved = false
+	case 21:
+		t.ClientRemoved = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	// t.Consumed (big.Int) (struct)
+
+	{
+
+		if err := t.Consumed.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Consumed: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufSetMaxClientDataCapParams = []byte{129}
+
+func (t *SetMaxClientDataCapParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSetMaxClientDataCapParams); err != nil {
+		return err
+	}
+
+	// t.MaxClientDataCap (big.Int) (struct)
+	if err := t.MaxClientDataCap.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SetMaxClientDataCapParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SetMaxClientDataCapParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.MaxClientDataCap (big.Int) (struct)
+
+	{
+
+		if err := t.MaxClientDataCap.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.MaxClientDataCap: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufRestoreBytesReturn = []byte{129}
+
+func (t *RestoreBytesReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRestoreBytesReturn); err != nil {
+		return err
+	}
+
+	// t.Restored (big.Int) (struct)
+	if err := t.Restored.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RestoreBytesReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = RestoreBytesReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Restored (big.Int) (struct)
+
+	{
+
+		if err := t.Restored.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Restored: %w", err)
 		}
-		t.ProposalID = uint64(extra)
 
 	}
 	return nil
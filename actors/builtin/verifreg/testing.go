@@ -4,6 +4,7 @@ import (
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	cbg "github.com/whyrusleeping/cbor-gen"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
@@ -57,6 +58,8 @@ func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.M
 		acc.RequireNoError(err, "error iterating clients")
 	}
 
+	acc.Require(st.MaxClientDataCap.GreaterThanEqual(big.Zero()), "max client DataCap %v is negative", st.MaxClientDataCap)
+
 	// Check verifiers and clients are disjoint.
 	for v := range allVerifiers { //nolint:nomaprange
 		_, found := allClients[v]
@@ -64,6 +67,35 @@ func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.M
 	}
 	// No need to iterate all clients; any overlap must have been one of all verifiers.
 
+	// Check audit log
+	if entries, err := st.LoadAuditLog(store); err != nil {
+		acc.Addf("error loading audit log: %v", err)
+	} else {
+		expected := st.AuditLogNext
+		if expected > AuditLogMaxEntries {
+			expected = AuditLogMaxEntries
+		}
+		acc.Require(uint64(len(entries)) == expected, "audit log has %d entries, expected %d of %d recorded",
+			len(entries), expected, st.AuditLogNext)
+	}
+
+	// Check verifier client counts
+	if counts, err := adt.AsMap(store, st.VerifierClientCounts, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading verifier client counts: %v", err)
+	} else {
+		var count cbg.CborInt
+		err = counts.ForEach(&count, func(key string) error {
+			verifier, err := addr.NewFromBytes([]byte(key))
+			if err != nil {
+				return err
+			}
+			acc.Require(verifier.Protocol() == addr.ID, "counted verifier %v should have ID protocol", verifier)
+			acc.Require(count > 0, "verifier %v client count %d is not positive", verifier, count)
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating verifier client counts")
+	}
+
 	return &StateSummary{
 		Verifiers: allVerifiers,
 		Clients:   allClients,
@@ -1,6 +1,8 @@
 package verifreg
 
 import (
+	"bytes"
+
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/cbor"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	rtt "github.com/filecoin-project/go-state-types/rt"
 	verifreg0 "github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
@@ -25,6 +28,15 @@ func (a Actor) Exports() []interface{} {
 		5:                         a.UseBytes,
 		6:                         a.RestoreBytes,
 		7:                         a.RemoveVerifiedClientDataCap,
+		8:                         a.SetMinVerifiedDealSize,
+		9:                         a.AuditLog,
+		10:                        a.RemoveVerifierAndReclaim,
+		11:                        a.TotalDataCap,
+		12:                        a.CanUseBytes,
+		13:                        a.VerifierClientCount,
+		14:                        a.RemoveVerifiers,
+		15:                        a.SetMaxClientDataCap,
+		16:                        a.RootKey,
 	}
 }
 
@@ -66,15 +78,16 @@ func (a Actor) Constructor(rt runtime.Runtime, rootKey *addr.Address) *abi.Empty
 type AddVerifierParams = verifreg0.AddVerifierParams
 
 func (a Actor) AddVerifier(rt runtime.Runtime, params *AddVerifierParams) *abi.EmptyValue {
-	if params.Allowance.LessThan(MinVerifiedDealSize) {
-		rt.Abortf(exitcode.ErrIllegalArgument, "Allowance %d below MinVerifiedDealSize for add verifier %v", params.Allowance, params.Address)
+	var st State
+	rt.StateReadonly(&st)
+	if params.Allowance.LessThan(st.MinVerifiedDealSize) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "Allowance %d below MinVerifiedDealSize %d for add verifier %v", params.Allowance,
+			st.MinVerifiedDealSize, params.Address)
 	}
 
 	verifier, err := builtin.ResolveToIDAddr(rt, params.Address)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve verifier address %v to ID address", params.Address)
 
-	var st State
-	rt.StateReadonly(&st)
 	rt.ValidateImmediateCallerIs(st.RootKey)
 
 	if verifier == st.RootKey {
@@ -94,11 +107,25 @@ func (a Actor) AddVerifier(rt runtime.Runtime, params *AddVerifierParams) *abi.E
 			rt.Abortf(exitcode.ErrIllegalArgument, "verified client %v cannot become a verifier", verifier)
 		}
 
-		err = verifiers.Put(abi.AddrKey(verifier), &params.Allowance)
+		previous, replaced, err := verifiers.Replace(abi.AddrKey(verifier), &params.Allowance)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verifier")
+		if replaced {
+			var previousCap DataCap
+			err = previousCap.UnmarshalCBOR(bytes.NewReader(previous))
+			builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to decode previous cap of verifier %v", verifier)
+			rt.Log(rtt.INFO, "replaced remaining cap %v of verifier %v with allowance %v", previousCap, verifier, params.Allowance)
+		}
 
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+
+		err = st.RecordAuditLogEntry(adt.AsStore(rt), &AuditLogEntry{
+			Action:  AuditActionAddVerifier,
+			Epoch:   rt.CurrEpoch(),
+			Caller:  rt.Caller(),
+			Subject: verifier,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record audit log entry")
 	})
 
 	return nil
@@ -116,17 +143,139 @@ func (a Actor) RemoveVerifier(rt runtime.Runtime, verifierAddr *addr.Address) *a
 		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers, builtin.DefaultHamtBitwidth)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
 
-		found, err := verifiers.TryDelete(abi.AddrKey(verifier))
+		_, found, err := verifiers.TryDelete(abi.AddrKey(verifier))
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove verifier")
 		builtin.RequireParam(rt, found, "no such verifier %v", verifierAddr)
 
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+
+		err = st.RecordAuditLogEntry(adt.AsStore(rt), &AuditLogEntry{
+			Action:  AuditActionRemoveVerifier,
+			Epoch:   rt.CurrEpoch(),
+			Caller:  rt.Caller(),
+			Subject: verifier,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record audit log entry")
 	})
 
 	return nil
 }
 
+type RemoveVerifiersParams struct {
+	Verifiers []addr.Address
+}
+
+type RemoveVerifiersReturn struct {
+	// ID addresses of the verifiers removed, in the order given.
+	Removed []addr.Address
+	// Addresses given that were not verifiers, as given.
+	Skipped []addr.Address
+}
+
+// Removes a list of verifiers like RemoveVerifier, in a single transaction.
+// Addresses that are not verifiers, including those that do not resolve to an actor, are skipped and reported
+// rather than failing the call.
+func (a Actor) RemoveVerifiers(rt runtime.Runtime, params *RemoveVerifiersParams) *RemoveVerifiersReturn {
+	var st State
+	rt.StateReadonly(&st)
+	rt.ValidateImmediateCallerIs(st.RootKey)
+
+	ret := RemoveVerifiersReturn{Removed: []addr.Address{}, Skipped: []addr.Address{}}
+	rt.StateTransaction(&st, func() {
+		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+
+		for _, verifierAddr := range params.Verifiers {
+			verifier, ok := rt.ResolveAddress(verifierAddr)
+			if !ok {
+				ret.Skipped = append(ret.Skipped, verifierAddr)
+				continue
+			}
+			_, found, err := verifiers.TryDelete(abi.AddrKey(verifier))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove verifier %v", verifier)
+			if !found {
+				ret.Skipped = append(ret.Skipped, verifierAddr)
+				continue
+			}
+			ret.Removed = append(ret.Removed, verifier)
+
+			err = st.RecordAuditLogEntry(adt.AsStore(rt), &AuditLogEntry{
+				Action:  AuditActionRemoveVerifier,
+				Epoch:   rt.CurrEpoch(),
+				Caller:  rt.Caller(),
+				Subject: verifier,
+			})
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record audit log entry")
+		}
+
+		st.Verifiers, err = verifiers.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+	})
+
+	return &ret
+}
+
+type RemoveVerifierAndReclaimParams struct {
+	Verifier addr.Address
+	// An existing verifier, designated by governance, to be credited with the removed verifier's remaining cap.
+	// This may not be the root key, which holds no cap of its own.
+	Recipient addr.Address
+}
+
+type RemoveVerifierAndReclaimReturn struct {
+	Reclaimed DataCap
+}
+
+// Removes a verifier like RemoveVerifier, but credits its remaining cap to another verifier rather than
+// discarding it. RemoveVerifier itself is unchanged and still discards the cap.
+// The cap cannot be reclaimed to the root key: the root key cannot be a verifier and the state has no allowance
+// for it, so the cap is instead reclaimed to a verifier the root key designates, e.g. one held by governance.
+func (a Actor) RemoveVerifierAndReclaim(rt runtime.Runtime, params *RemoveVerifierAndReclaimParams) *RemoveVerifierAndReclaimReturn {
+	verifier, err := builtin.ResolveToIDAddr(rt, params.Verifier)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve verifier address %v to ID address", params.Verifier)
+	recipient, err := builtin.ResolveToIDAddr(rt, params.Recipient)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve recipient address %v to ID address", params.Recipient)
+
+	var st State
+	rt.StateReadonly(&st)
+	rt.ValidateImmediateCallerIs(st.RootKey)
+	builtin.RequireParam(rt, verifier != recipient, "verifier %v cannot reclaim its own cap", params.Verifier)
+	builtin.RequireParam(rt, recipient != st.RootKey, "root key %v cannot hold reclaimed cap", params.Recipient)
+
+	var reclaimed DataCap
+	rt.StateTransaction(&st, func() {
+		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+
+		found, err := verifiers.Pop(abi.AddrKey(verifier), &reclaimed)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove verifier")
+		builtin.RequireParam(rt, found, "no such verifier %v", params.Verifier)
+
+		var recipientCap DataCap
+		found, err = verifiers.Get(abi.AddrKey(recipient), &recipientCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier %v", recipient)
+		builtin.RequireParam(rt, found, "recipient %v is not a verifier", params.Recipient)
+
+		recipientCap = big.Add(recipientCap, reclaimed)
+		err = verifiers.Put(abi.AddrKey(recipient), &recipientCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verifier cap (%d) for %v", recipientCap, recipient)
+
+		st.Verifiers, err = verifiers.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+
+		err = st.RecordAuditLogEntry(adt.AsStore(rt), &AuditLogEntry{
+			Action:  AuditActionRemoveVerifier,
+			Epoch:   rt.CurrEpoch(),
+			Caller:  rt.Caller(),
+			Subject: verifier,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record audit log entry")
+	})
+
+	return &RemoveVerifierAndReclaimReturn{Reclaimed: reclaimed}
+}
+
 //type AddVerifiedClientParams struct {
 //	Address   addr.Address
 //	Allowance DataCap
@@ -137,15 +286,16 @@ func (a Actor) AddVerifiedClient(rt runtime.Runtime, params *AddVerifiedClientPa
 	// The caller will be verified by checking the verifiers table below.
 	rt.ValidateImmediateCallerAcceptAny()
 
-	if params.Allowance.LessThan(MinVerifiedDealSize) {
-		rt.Abortf(exitcode.ErrIllegalArgument, "allowance %d below MinVerifiedDealSize for add verified client %v", params.Allowance, params.Address)
+	var st State
+	rt.StateReadonly(&st)
+	if params.Allowance.LessThan(st.MinVerifiedDealSize) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "allowance %d below MinVerifiedDealSize %d for add verified client %v", params.Allowance,
+			st.MinVerifiedDealSize, params.Address)
 	}
 
 	client, err := builtin.ResolveToIDAddr(rt, params.Address)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve verified client address %v", params.Address)
 
-	var st State
-	rt.StateReadonly(&st)
 	if st.RootKey == client {
 		rt.Abortf(exitcode.ErrIllegalArgument, "Rootkey cannot be added as a verified client")
 	}
@@ -193,14 +343,31 @@ func (a Actor) AddVerifiedClient(rt runtime.Runtime, params *AddVerifiedClientPa
 		} else {
 			clientCap = params.Allowance
 		}
+		if st.exceedsMaxClientDataCap(clientCap) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "client %v DataCap %d would exceed maximum %d", client, clientCap, st.MaxClientDataCap)
+		}
 		err = verifiedClients.Put(abi.AddrKey(client), &clientCap)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verified client %v with cap %d", client, clientCap)
 
+		// Only adding a client that isn't already verified counts as onboarding it.
+		if !found {
+			err = st.incrementVerifierClientCount(adt.AsStore(rt), verifier)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update client count for verifier %v", verifier)
+		}
+
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
 
 		st.VerifiedClients, err = verifiedClients.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+
+		err = st.RecordAuditLogEntry(adt.AsStore(rt), &AuditLogEntry{
+			Action:  AuditActionAddVerifiedClient,
+			Epoch:   rt.CurrEpoch(),
+			Caller:  verifier,
+			Subject: client,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record audit log entry")
 	})
 
 	return nil
@@ -212,21 +379,33 @@ func (a Actor) AddVerifiedClient(rt runtime.Runtime, params *AddVerifiedClientPa
 //}
 type UseBytesParams = verifreg0.UseBytesParams
 
+type UseBytesReturn struct {
+	// The client's DataCap after the deal's bytes are deducted, zero if the client was removed.
+	RemainingCap DataCap
+	// Whether the client was removed because its remaining DataCap fell below the minimum deal size,
+	// so it is no longer verified.
+	ClientRemoved bool
+	// The DataCap deducted from the client. If the client was removed this exceeds the deal size by
+	// the remainder forfeited by the client.
+	Consumed DataCap
+}
+
 // Called by StorageMarketActor during PublishStorageDeals.
 // Do not allow partially verified deals (DealSize must be greater than equal to allowed cap).
 // Delete VerifiedClient if remaining DataCap is smaller than minimum VerifiedDealSize.
-func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *abi.EmptyValue {
+func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *UseBytesReturn {
 	rt.ValidateImmediateCallerIs(builtin.StorageMarketActorAddr)
 
 	client, err := builtin.ResolveToIDAddr(rt, params.Address)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve verified client address %v", params.Address)
 
-	if params.DealSize.LessThan(MinVerifiedDealSize) {
-		rt.Abortf(exitcode.ErrIllegalArgument, "VerifiedDealSize: %d below minimum in UseBytes", params.DealSize)
-	}
-
+	ret := UseBytesReturn{RemainingCap: big.Zero(), Consumed: params.DealSize}
 	var st State
 	rt.StateTransaction(&st, func() {
+		if params.DealSize.LessThan(st.MinVerifiedDealSize) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "VerifiedDealSize: %d below minimum %d in UseBytes", params.DealSize, st.MinVerifiedDealSize)
+		}
+
 		verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients, builtin.DefaultHamtBitwidth)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
 
@@ -243,7 +422,7 @@ func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *abi.EmptyVa
 		}
 
 		newVcCap := big.Sub(vcCap, params.DealSize)
-		if newVcCap.LessThan(MinVerifiedDealSize) {
+		if newVcCap.LessThan(st.MinVerifiedDealSize) {
 			// Delete entry if remaining DataCap is less than MinVerifiedDealSize.
 			// Will be restored later if the deal did not get activated with a ProvenSector.
 			//
@@ -251,16 +430,22 @@ func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *abi.EmptyVa
 			// See: https://github.com/filecoin-project/specs-actors/issues/727
 			err = verifiedClients.Delete(abi.AddrKey(client))
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete verified client %v", client)
+			ret.ClientRemoved = true
+			ret.Consumed = vcCap
 		} else {
 			err = verifiedClients.Put(abi.AddrKey(client), &newVcCap)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", client, newVcCap)
+			ret.RemainingCap = newVcCap
 		}
 
 		st.VerifiedClients, err = verifiedClients.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
 	})
 
-	return nil
+	if ret.ClientRemoved {
+		rt.Log(rtt.INFO, "removed verified client %v with remaining DataCap below minimum deal size", client)
+	}
+	return &ret
 }
 
 //type RestoreBytesParams struct {
@@ -269,20 +454,25 @@ func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *abi.EmptyVa
 //}
 type RestoreBytesParams = verifreg0.RestoreBytesParams
 
+type RestoreBytesReturn struct {
+	// The DataCap restored to the client.
+	Restored DataCap
+}
+
 // Called by HandleInitTimeoutDeals from StorageMarketActor when a VerifiedDeal fails to init.
 // Restore allowable cap for the client, creating new entry if the client has been deleted.
-func (a Actor) RestoreBytes(rt runtime.Runtime, params *RestoreBytesParams) *abi.EmptyValue {
+func (a Actor) RestoreBytes(rt runtime.Runtime, params *RestoreBytesParams) *RestoreBytesReturn {
 	rt.ValidateImmediateCallerIs(builtin.StorageMarketActorAddr)
 
-	if params.DealSize.LessThan(MinVerifiedDealSize) {
-		rt.Abortf(exitcode.ErrIllegalArgument, "Below minimum VerifiedDealSize requested in RestoreBytes: %d", params.DealSize)
+	var st State
+	rt.StateReadonly(&st)
+	if params.DealSize.LessThan(st.MinVerifiedDealSize) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "Below minimum VerifiedDealSize %d requested in RestoreBytes: %d", st.MinVerifiedDealSize, params.DealSize)
 	}
 
 	client, err := builtin.ResolveToIDAddr(rt, params.Address)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve verified client addr %v", params.Address)
 
-	var st State
-	rt.StateReadonly(&st)
 	if st.RootKey == client {
 		rt.Abortf(exitcode.ErrIllegalArgument, "Cannot restore allowance for Rootkey")
 	}
@@ -309,6 +499,9 @@ func (a Actor) RestoreBytes(rt runtime.Runtime, params *RestoreBytesParams) *abi
 		}
 
 		newVcCap := big.Add(vcCap, params.DealSize)
+		if st.exceedsMaxClientDataCap(newVcCap) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "client %v DataCap %d would exceed maximum %d", client, newVcCap, st.MaxClientDataCap)
+		}
 		err = verifiedClients.Put(abi.AddrKey(client), &newVcCap)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put verified client %v with %v", client, newVcCap)
 
@@ -316,7 +509,7 @@ func (a Actor) RestoreBytes(rt runtime.Runtime, params *RestoreBytesParams) *abi
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
 	})
 
-	return nil
+	return &RestoreBytesReturn{Restored: params.DealSize}
 }
 
 type RemoveDataCapParams struct {
@@ -404,3 +597,161 @@ func (a Actor) RemoveVerifiedClientDataCap(rt runtime.Runtime, params *RemoveDat
 		DataCapRemoved: removedDataCapAmount,
 	}
 }
+
+type SetMinVerifiedDealSizeParams struct {
+	MinVerifiedDealSize abi.StoragePower
+}
+
+// Sets the minimum verified deal size, which also bounds verifier and client allowances.
+// Existing allowances and deals are unaffected; the new minimum applies to subsequent calls.
+func (a Actor) SetMinVerifiedDealSize(rt runtime.Runtime, params *SetMinVerifiedDealSizeParams) *abi.EmptyValue {
+	builtin.RequireParam(rt, params.MinVerifiedDealSize.GreaterThan(big.Zero()),
+		"min verified deal size %d must be positive", params.MinVerifiedDealSize)
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		rt.ValidateImmediateCallerIs(st.RootKey)
+		st.MinVerifiedDealSize = params.MinVerifiedDealSize
+	})
+	return nil
+}
+
+type SetMaxClientDataCapParams struct {
+	MaxClientDataCap DataCap
+}
+
+// Sets the maximum DataCap a single verified client may hold, or removes the ceiling if zero.
+// Clients already holding more are unaffected, but may not be granted or restored more until below the ceiling.
+func (a Actor) SetMaxClientDataCap(rt runtime.Runtime, params *SetMaxClientDataCapParams) *abi.EmptyValue {
+	builtin.RequireParam(rt, params.MaxClientDataCap.GreaterThanEqual(big.Zero()),
+		"max client DataCap %d must not be negative", params.MaxClientDataCap)
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		rt.ValidateImmediateCallerIs(st.RootKey)
+		st.MaxClientDataCap = params.MaxClientDataCap
+	})
+	return nil
+}
+
+type AuditLogReturn struct {
+	// The retained entries, oldest first.
+	Entries []AuditLogEntry
+	// The total number of entries ever recorded, including those no longer retained.
+	TotalEntries uint64
+}
+
+// Returns the most recent administrative actions recorded in the audit log: additions and removals of verifiers,
+// and additions of verified clients. Parameter changes such as SetMinVerifiedDealSize are not recorded.
+func (a Actor) AuditLog(rt runtime.Runtime, _ *abi.EmptyValue) *AuditLogReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	entries, err := st.LoadAuditLog(adt.AsStore(rt))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load audit log")
+	return &AuditLogReturn{
+		Entries:      entries,
+		TotalEntries: st.AuditLogNext,
+	}
+}
+
+type TotalDataCapReturn struct {
+	// The sum of remaining DataCap across all verifiers.
+	VerifierDataCap DataCap
+	// The sum of remaining DataCap across all verified clients.
+	ClientDataCap DataCap
+}
+
+// Returns the total DataCap held by verifiers and, separately, by verified clients.
+func (a Actor) TotalDataCap(rt runtime.Runtime, _ *abi.EmptyValue) *TotalDataCapReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	verifierCap, clientCap, err := st.TotalDataCap(adt.AsStore(rt))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to sum data cap")
+	return &TotalDataCapReturn{
+		VerifierDataCap: verifierCap,
+		ClientDataCap:   clientCap,
+	}
+}
+
+type CanUseBytesReturn struct {
+	// Whether UseBytes with the same parameters would succeed.
+	Allowed bool
+	// The client's DataCap after the bytes would be used, or its current DataCap if they would not.
+	// Zero if the client's entry would be removed, or the client is not verified.
+	RemainingCap DataCap
+}
+
+// Checks whether the client could use the given number of bytes of DataCap for a deal, without using them.
+// Failures that UseBytes would abort with are reported as not allowed.
+func (a Actor) CanUseBytes(rt runtime.Runtime, params *UseBytesParams) *CanUseBytesReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	ret := &CanUseBytesReturn{Allowed: false, RemainingCap: big.Zero()}
+	// Unlike UseBytes, don't create an account for an address that doesn't resolve, it can't be a verified client.
+	client, found := rt.ResolveAddress(params.Address)
+	if !found {
+		return ret
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
+
+	var vcCap DataCap
+	found, err = verifiedClients.Get(abi.AddrKey(client), &vcCap)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", client)
+	if !found {
+		return ret
+	}
+	builtin.RequireState(rt, vcCap.GreaterThanEqual(big.Zero()), "negative cap for client %v: %v", client, vcCap)
+
+	ret.RemainingCap = vcCap
+	if params.DealSize.LessThan(st.MinVerifiedDealSize) || params.DealSize.GreaterThan(vcCap) {
+		return ret
+	}
+
+	ret.Allowed = true
+	ret.RemainingCap = big.Sub(vcCap, params.DealSize)
+	if ret.RemainingCap.LessThan(st.MinVerifiedDealSize) {
+		// UseBytes deletes the client's entry when its remaining DataCap falls below MinVerifiedDealSize.
+		ret.RemainingCap = big.Zero()
+	}
+	return ret
+}
+
+type VerifierClientCountReturn struct {
+	// The number of clients the verifier has onboarded.
+	Count uint64
+}
+
+// Returns the number of clients a verifier has onboarded. The count is retained after the verifier is removed,
+// and is zero for an address that has never onboarded a client.
+func (a Actor) VerifierClientCount(rt runtime.Runtime, verifierAddr *addr.Address) *VerifierClientCountReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	// Don't create an account for an address that doesn't resolve, it can't have onboarded any clients.
+	verifier, found := rt.ResolveAddress(*verifierAddr)
+	if !found {
+		return &VerifierClientCountReturn{Count: 0}
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	count, err := st.VerifierClientCount(adt.AsStore(rt), verifier)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load client count for verifier %v", verifier)
+	return &VerifierClientCountReturn{Count: count}
+}
+
+// Returns the root key, which administers verifiers and acts for network governance.
+func (a Actor) RootKey(rt runtime.Runtime, _ *abi.EmptyValue) *addr.Address {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	return &st.RootKey
+}
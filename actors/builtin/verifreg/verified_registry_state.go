@@ -6,10 +6,12 @@ import (
 	"github.com/filecoin-project/go-address"
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
 	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
 	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
@@ -42,25 +44,193 @@ type State struct {
 	//specific client. Unique proposal ids ensure that removal proposals cannot be replayed.√
 	// AddrPairKey is constructed as <verifier address, client address>, both using ID addresses.
 	RemoveDataCapProposalIDs cid.Cid // HAMT[AddrPairKey]RmDcProposalID
+
+	// The minimum size of a verified deal, and of any allowance granted to a verifier or client.
+	// Initialized to MinVerifiedDealSize and adjustable by the root key holder.
+	MinVerifiedDealSize abi.StoragePower
+
+	// The most recent administrative actions, keyed by sequence number.
+	// Only the addition and removal of verifiers and the addition of verified clients are recorded (see AuditAction);
+	// changes to MinVerifiedDealSize and MaxClientDataCap are not.
+	// Holds at most AuditLogMaxEntries entries; older entries are deleted as new ones are recorded.
+	AuditLog cid.Cid // AMT[uint64]AuditLogEntry
+
+	// The sequence number of the next audit log entry, which is also the total number of entries ever recorded.
+	AuditLogNext uint64
+
+	// The number of clients each verifier has onboarded, i.e. added while they were not already verified.
+	// Counts are preserved when a verifier is removed, so they resume if the verifier is added again.
+	VerifierClientCounts cid.Cid // HAMT[addr.Address]CborInt
+
+	// The maximum DataCap a single verified client may hold, enforced when DataCap is added or restored.
+	// Zero means no ceiling, which is the initial value. Adjustable by the root key holder.
+	MaxClientDataCap DataCap
 }
 
+// Initial value of the minimum verified deal size.
 var MinVerifiedDealSize = abi.NewStoragePower(1 << 20)
 
+// The maximum number of entries retained in the audit log.
+const AuditLogMaxEntries = 256
+
+// Bitwidth of the audit log AMT.
+const AuditLogAmtBitwidth = 5
+
+// An administrative action recorded in the audit log.
+type AuditAction int64
+
+const (
+	AuditActionAddVerifier AuditAction = iota
+	AuditActionRemoveVerifier
+	AuditActionAddVerifiedClient
+)
+
+type AuditLogEntry struct {
+	Action AuditAction
+	Epoch  abi.ChainEpoch
+	// ID address of the party performing the action: the root key holder for verifier changes, or
+	// the verifier adding a client.
+	Caller addr.Address
+	// ID address of the verifier or client acted upon.
+	Subject addr.Address
+}
+
 // rootKeyAddress comes from genesis.
 func ConstructState(store adt.Store, rootKeyAddress addr.Address) (*State, error) {
 	emptyMapCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to create empty map: %w", err)
 	}
+	emptyAuditLogCid, err := adt.StoreEmptyArray(store, AuditLogAmtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty audit log: %w", err)
+	}
 
 	return &State{
 		RootKey:                  rootKeyAddress,
 		Verifiers:                emptyMapCid,
 		VerifiedClients:          emptyMapCid,
 		RemoveDataCapProposalIDs: emptyMapCid,
+		MinVerifiedDealSize:      MinVerifiedDealSize,
+		AuditLog:                 emptyAuditLogCid,
+		AuditLogNext:             0,
+		VerifierClientCounts:     emptyMapCid,
+		MaxClientDataCap:         big.Zero(),
 	}, nil
 }
 
+// Appends an entry to the audit log, deleting the oldest entry if the log is full.
+func (st *State) RecordAuditLogEntry(store adt.Store, entry *AuditLogEntry) error {
+	log, err := adt.AsArray(store, st.AuditLog, AuditLogAmtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("failed to load audit log: %w", err)
+	}
+	if err := log.Set(st.AuditLogNext, entry); err != nil {
+		return xerrors.Errorf("failed to record audit log entry %d: %w", st.AuditLogNext, err)
+	}
+	if st.AuditLogNext >= AuditLogMaxEntries {
+		evicted := st.AuditLogNext - AuditLogMaxEntries
+		if err := log.Delete(evicted); err != nil {
+			return xerrors.Errorf("failed to evict audit log entry %d: %w", evicted, err)
+		}
+	}
+	st.AuditLogNext++
+	if st.AuditLog, err = log.Root(); err != nil {
+		return xerrors.Errorf("failed to flush audit log: %w", err)
+	}
+	return nil
+}
+
+// Returns the retained audit log entries, oldest first.
+func (st *State) LoadAuditLog(store adt.Store) ([]AuditLogEntry, error) {
+	log, err := adt.AsArray(store, st.AuditLog, AuditLogAmtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load audit log: %w", err)
+	}
+	entries := make([]AuditLogEntry, 0, log.Length())
+	var entry AuditLogEntry
+	err = log.ForEach(&entry, func(_ int64) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to iterate audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Returns the number of clients a verifier has onboarded, zero if it has never onboarded one.
+func (st *State) VerifierClientCount(store adt.Store, verifier addr.Address) (uint64, error) {
+	counts, err := adt.AsMap(store, st.VerifierClientCounts, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to load verifier client counts: %w", err)
+	}
+	var count cbg.CborInt
+	found, err := counts.Get(abi.AddrKey(verifier), &count)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to get client count for verifier %v: %w", verifier, err)
+	}
+	if !found {
+		return 0, nil
+	}
+	return uint64(count), nil
+}
+
+// Increments the number of clients a verifier has onboarded.
+func (st *State) incrementVerifierClientCount(store adt.Store, verifier addr.Address) error {
+	counts, err := adt.AsMap(store, st.VerifierClientCounts, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("failed to load verifier client counts: %w", err)
+	}
+	var count cbg.CborInt
+	if _, err := counts.Get(abi.AddrKey(verifier), &count); err != nil {
+		return xerrors.Errorf("failed to get client count for verifier %v: %w", verifier, err)
+	}
+	count++
+	if err := counts.Put(abi.AddrKey(verifier), &count); err != nil {
+		return xerrors.Errorf("failed to put client count for verifier %v: %w", verifier, err)
+	}
+	if st.VerifierClientCounts, err = counts.Root(); err != nil {
+		return xerrors.Errorf("failed to flush verifier client counts: %w", err)
+	}
+	return nil
+}
+
+// Checks whether a verified client's DataCap would exceed the ceiling, if one is set.
+func (st *State) exceedsMaxClientDataCap(clientCap DataCap) bool {
+	return !st.MaxClientDataCap.IsZero() && clientCap.GreaterThan(st.MaxClientDataCap)
+}
+
+// Returns the sum of DataCap held by all verifiers and by all verified clients.
+func (st *State) TotalDataCap(store adt.Store) (DataCap, DataCap, error) {
+	verifierCap, err := sumDataCap(store, st.Verifiers)
+	if err != nil {
+		return big.Zero(), big.Zero(), xerrors.Errorf("failed to sum verifier data cap: %w", err)
+	}
+	clientCap, err := sumDataCap(store, st.VerifiedClients)
+	if err != nil {
+		return big.Zero(), big.Zero(), xerrors.Errorf("failed to sum client data cap: %w", err)
+	}
+	return verifierCap, clientCap, nil
+}
+
+func sumDataCap(store adt.Store, root cid.Cid) (DataCap, error) {
+	caps, err := adt.AsMap(store, root, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return big.Zero(), err
+	}
+	total := big.Zero()
+	var dcap DataCap
+	err = caps.ForEach(&dcap, func(_ string) error {
+		total = big.Add(total, dcap)
+		return nil
+	})
+	if err != nil {
+		return big.Zero(), err
+	}
+	return total, nil
+}
+
 // A verifier who wants to send/agree to a RemoveDataCapRequest should sign a RemoveDataCapProposal and send the signed proposal to the root key holder.
 type RemoveDataCapProposal struct {
 	// VerifiedClient is the client address to remove the DataCap from
@@ -1,6 +1,7 @@
 package verifreg_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -42,6 +43,8 @@ func TestConstruction(t *testing.T) {
 		assert.Equal(t, emptyMap, state.VerifiedClients)
 		assert.Equal(t, emptyMap, state.Verifiers)
 		assert.Equal(t, raddr, state.RootKey)
+		assert.Equal(t, verifreg.MinVerifiedDealSize, state.MinVerifiedDealSize)
+		assert.Equal(t, big.Zero(), state.MaxClientDataCap)
 		actor.checkState(rt)
 	})
 
@@ -149,6 +152,17 @@ func TestAddVerifier(t *testing.T) {
 		ac.checkState(rt)
 	})
 
+	t.Run("adding an existing verifier replaces its remaining cap", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, va, allowance)
+
+		newAllowance := big.Add(allowance, big.NewInt(1))
+		ac.addVerifier(rt, va, newAllowance)
+		rt.ExpectLogsContain(fmt.Sprintf("replaced remaining cap %v of verifier %v with allowance %v", allowance, va, newAllowance))
+		assert.Equal(t, newAllowance, ac.getVerifierCap(rt, va))
+		ac.checkState(rt)
+	})
+
 	t.Run("successfully add a verifier after resolving to ID address", func(t *testing.T) {
 		rt, ac := basicVerifRegSetup(t, root)
 
@@ -168,6 +182,7 @@ func TestAddVerifier(t *testing.T) {
 func TestRemoveVerifier(t *testing.T) {
 	root := tutil.NewIDAddr(t, 101)
 	va := tutil.NewIDAddr(t, 201)
+	vb := tutil.NewIDAddr(t, 202)
 	allowance := big.Add(verifreg.MinVerifiedDealSize, big.NewInt(42))
 
 	t.Run("fails when caller is not the root key", func(t *testing.T) {
@@ -218,6 +233,130 @@ func TestRemoveVerifier(t *testing.T) {
 		ac.assertVerifierRemoved(rt, verifierIdAddr)
 		ac.checkState(rt)
 	})
+
+	t.Run("removal without reclamation discards remaining cap", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, va, allowance)
+		ac.addNewVerifier(rt, vb, allowance)
+
+		ac.removeVerifier(rt, va)
+		assert.Equal(t, allowance, ac.getVerifierCap(rt, vb))
+		assert.Equal(t, allowance, ac.totalDataCap(rt).VerifierDataCap)
+		ac.checkState(rt)
+	})
+
+	t.Run("removal with reclamation credits remaining cap to recipient", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, va, allowance)
+		ac.addNewVerifier(rt, vb, allowance)
+
+		// Partially spend the removed verifier's cap.
+		clientAllowance := verifreg.MinVerifiedDealSize
+		ac.addVerifiedClient(rt, va, tutil.NewIDAddr(t, 301), clientAllowance, clientAllowance)
+
+		reclaimed := ac.removeVerifierAndReclaim(rt, va, vb)
+		assert.Equal(t, big.Sub(allowance, clientAllowance), reclaimed)
+		assert.Equal(t, big.Add(allowance, reclaimed), ac.getVerifierCap(rt, vb))
+		// No verifier cap is lost.
+		assert.Equal(t, big.Sub(big.Mul(big.NewInt(2), allowance), clientAllowance), ac.totalDataCap(rt).VerifierDataCap)
+		ac.checkState(rt)
+	})
+
+	t.Run("reclamation fails when recipient is the root key", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, va, allowance)
+
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.SetCaller(ac.rootkey, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "cannot hold reclaimed cap", func() {
+			rt.Call(ac.RemoveVerifierAndReclaim, &verifreg.RemoveVerifierAndReclaimParams{Verifier: va, Recipient: ac.rootkey})
+		})
+		rt.Reset()
+		assert.Equal(t, allowance, ac.getVerifierCap(rt, va))
+		ac.checkState(rt)
+	})
+
+	t.Run("reclamation fails when recipient is not a verifier", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, va, allowance)
+
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.SetCaller(ac.rootkey, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.RemoveVerifierAndReclaim, &verifreg.RemoveVerifierAndReclaimParams{Verifier: va, Recipient: vb})
+		})
+		rt.Reset()
+		assert.Equal(t, allowance, ac.getVerifierCap(rt, va))
+		ac.checkState(rt)
+	})
+
+	t.Run("reclamation fails when recipient is the removed verifier", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, va, allowance)
+
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.SetCaller(ac.rootkey, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.RemoveVerifierAndReclaim, &verifreg.RemoveVerifierAndReclaimParams{Verifier: va, Recipient: va})
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("reclamation fails when caller is not the root key", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, va, allowance)
+		ac.addNewVerifier(rt, vb, allowance)
+
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.SetCaller(tutil.NewIDAddr(t, 501), builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(ac.RemoveVerifierAndReclaim, &verifreg.RemoveVerifierAndReclaimParams{Verifier: va, Recipient: vb})
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("bulk removal removes verifiers and skips non-verifiers", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		vc := tutil.NewIDAddr(t, 503)
+		vcNonID := tutil.NewBLSAddr(t, 3)
+		rt.AddIDAddress(vcNonID, vc)
+		ac.addNewVerifier(rt, va, allowance)
+		ac.addNewVerifier(rt, vb, allowance)
+		ac.addNewVerifier(rt, vc, allowance)
+		logLength := len(ac.auditLog(rt).Entries)
+
+		notVerifier := tutil.NewIDAddr(t, 504)
+		unresolvable := tutil.NewBLSAddr(t, 4)
+		ret := ac.removeVerifiers(rt, va, notVerifier, vcNonID, unresolvable, va)
+		assert.Equal(t, []address.Address{va, vc}, ret.Removed)
+		assert.Equal(t, []address.Address{notVerifier, unresolvable, va}, ret.Skipped)
+
+		ac.assertVerifierRemoved(rt, va)
+		ac.assertVerifierRemoved(rt, vc)
+		assert.Equal(t, allowance, ac.getVerifierCap(rt, vb))
+
+		// Each removal is recorded in the audit log.
+		entries := ac.auditLog(rt).Entries
+		require.Len(t, entries, logLength+2)
+		for i, removed := range ret.Removed {
+			assert.Equal(t, verifreg.AuditActionRemoveVerifier, entries[logLength+i].Action)
+			assert.Equal(t, removed, entries[logLength+i].Subject)
+		}
+		ac.checkState(rt)
+	})
+
+	t.Run("bulk removal fails when caller is not the root key", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, va, allowance)
+
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.SetCaller(tutil.NewIDAddr(t, 501), builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(ac.RemoveVerifiers, &verifreg.RemoveVerifiersParams{Verifiers: []address.Address{va}})
+		})
+		assert.Equal(t, allowance, ac.getVerifierCap(rt, va))
+		ac.checkState(rt)
+	})
 }
 
 func TestAddVerifiedClient(t *testing.T) {
@@ -510,6 +649,25 @@ func TestUseBytes(t *testing.T) {
 		ac.checkState(rt)
 	})
 
+	t.Run("removal of a verified client is reported", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		clientAllowance := big.Add(verifreg.MinVerifiedDealSize, big.NewInt(1))
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifierAddr, clientAddr, vallow, clientAllowance)
+		clientIdAddr, found := rt.GetIdAddr(clientAddr)
+		require.True(t, found)
+
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+		ret := rt.Call(ac.UseBytes, &verifreg.UseBytesParams{Address: clientAddr, DealSize: verifreg.MinVerifiedDealSize}).(*verifreg.UseBytesReturn)
+		rt.Verify()
+
+		assert.True(t, ret.ClientRemoved)
+		assert.EqualValues(t, big.Zero(), ret.RemainingCap)
+		rt.ExpectLogsContain(fmt.Sprintf("removed verified client %v", clientIdAddr))
+		ac.assertClientRemoved(rt, clientIdAddr)
+		ac.checkState(rt)
+	})
+
 	t.Run("successfully consume deal for verified client and then fail on next attempt because it has been removed", func(t *testing.T) {
 		rt, ac := basicVerifRegSetup(t, root)
 		clientAllowance := big.Sum(verifreg.MinVerifiedDealSize, big.NewInt(1))
@@ -749,6 +907,435 @@ func TestRestoreBytes(t *testing.T) {
 	})
 }
 
+func TestSetMinVerifiedDealSize(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifierAddr := tutil.NewIDAddr(t, 201)
+	clientAddr := tutil.NewIDAddr(t, 301)
+	lowered := big.Div(verifreg.MinVerifiedDealSize, big.NewInt(4))
+	belowDefault := big.Add(lowered, big.NewInt(1))
+
+	t.Run("lowering the minimum allows previously rejected sizes", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		// Rejected at the default minimum.
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "below MinVerifiedDealSize", func() {
+			ac.addVerifier(rt, verifierAddr, belowDefault)
+		})
+
+		ac.setMinVerifiedDealSize(rt, lowered)
+		assert.Equal(t, lowered, ac.state(rt).MinVerifiedDealSize)
+
+		// Allowances, deal sizes and restorations below the default are now accepted.
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifierAddr, clientAddr, belowDefault, big.Mul(belowDefault, big.NewInt(2)))
+		ac.useBytes(rt, clientAddr, belowDefault, &capExpectation{expectedCap: belowDefault})
+		ac.useBytes(rt, clientAddr, belowDefault, &capExpectation{removed: true})
+		ac.restoreBytes(rt, clientAddr, belowDefault, &capExpectation{expectedCap: belowDefault})
+
+		// Sizes below the new minimum are still rejected.
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "below minimum", func() {
+			ac.useBytes(rt, clientAddr, big.Sub(lowered, big.NewInt(1)), nil)
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("raising the minimum rejects previously accepted sizes", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.setMinVerifiedDealSize(rt, big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(2)))
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "below MinVerifiedDealSize", func() {
+			ac.addVerifier(rt, verifierAddr, verifreg.MinVerifiedDealSize)
+		})
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when caller is not the root key", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.SetCaller(tutil.NewIDAddr(t, 501), builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(ac.SetMinVerifiedDealSize, &verifreg.SetMinVerifiedDealSizeParams{MinVerifiedDealSize: lowered})
+		})
+		assert.Equal(t, verifreg.MinVerifiedDealSize, ac.state(rt).MinVerifiedDealSize)
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when minimum is not positive", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.SetCaller(ac.rootkey, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.SetMinVerifiedDealSize, &verifreg.SetMinVerifiedDealSizeParams{MinVerifiedDealSize: big.Zero()})
+		})
+		ac.checkState(rt)
+	})
+}
+
+func TestSetMaxClientDataCap(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifierAddr := tutil.NewIDAddr(t, 201)
+	clientAddr := tutil.NewIDAddr(t, 301)
+	minSize := verifreg.MinVerifiedDealSize
+	ceiling := big.Mul(minSize, big.NewInt(3))
+
+	t.Run("no ceiling by default", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		large := big.Mul(minSize, big.NewInt(100))
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifierAddr, clientAddr, minSize, large)
+		ac.restoreBytes(rt, clientAddr, minSize, &capExpectation{expectedCap: big.Add(large, minSize)})
+		ac.checkState(rt)
+	})
+
+	t.Run("adding DataCap up to the ceiling succeeds and beyond it fails", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.setMaxClientDataCap(rt, ceiling)
+
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifierAddr, clientAddr, ceiling, big.Mul(minSize, big.NewInt(2)))
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, minSize, ceiling)
+
+		rt.SetCaller(verifierAddr, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "would exceed maximum", func() {
+			rt.Call(ac.AddVerifiedClient, mkClientParams(clientAddr, minSize))
+		})
+		rt.Verify()
+
+		// The verifier's cap is not consumed by the rejected allocation.
+		assert.Equal(t, ceiling, ac.getClientCap(rt, clientAddr))
+		assert.Equal(t, big.Mul(minSize, big.NewInt(2)), ac.getVerifierCap(rt, verifierAddr))
+		ac.checkState(rt)
+	})
+
+	t.Run("a new client is limited by the ceiling", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.setMaxClientDataCap(rt, ceiling)
+		ac.addVerifier(rt, verifierAddr, big.Mul(ceiling, big.NewInt(2)))
+
+		rt.SetCaller(verifierAddr, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "would exceed maximum", func() {
+			rt.Call(ac.AddVerifiedClient, mkClientParams(clientAddr, big.Add(ceiling, big.NewInt(1))))
+		})
+		rt.Verify()
+		ac.assertClientRemoved(rt, clientAddr)
+		ac.checkState(rt)
+	})
+
+	t.Run("restoring bytes up to the ceiling succeeds and beyond it fails", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.setMaxClientDataCap(rt, ceiling)
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifierAddr, clientAddr, minSize, ceiling)
+
+		reduced := big.Sub(ceiling, minSize)
+		ac.useBytes(rt, clientAddr, minSize, &capExpectation{expectedCap: reduced})
+		ac.restoreBytes(rt, clientAddr, minSize, &capExpectation{expectedCap: ceiling})
+
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMinerActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "would exceed maximum", func() {
+			rt.Call(ac.RestoreBytes, &verifreg.RestoreBytesParams{Address: clientAddr, DealSize: minSize})
+		})
+		rt.Verify()
+		assert.Equal(t, ceiling, ac.getClientCap(rt, clientAddr))
+		ac.checkState(rt)
+	})
+
+	t.Run("removing the ceiling allows DataCap beyond it", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.setMaxClientDataCap(rt, ceiling)
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifierAddr, clientAddr, minSize, ceiling)
+
+		ac.setMaxClientDataCap(rt, big.Zero())
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, minSize, big.Add(ceiling, minSize))
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when caller is not the root key", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.SetCaller(tutil.NewIDAddr(t, 501), builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(ac.SetMaxClientDataCap, &verifreg.SetMaxClientDataCapParams{MaxClientDataCap: ceiling})
+		})
+		assert.Equal(t, big.Zero(), ac.state(rt).MaxClientDataCap)
+		ac.checkState(rt)
+	})
+
+	t.Run("fails when ceiling is negative", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.SetCaller(ac.rootkey, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.SetMaxClientDataCap, &verifreg.SetMaxClientDataCapParams{MaxClientDataCap: big.NewInt(-1)})
+		})
+		ac.checkState(rt)
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifierAddr := tutil.NewIDAddr(t, 201)
+	clientAddr := tutil.NewIDAddr(t, 301)
+	allowance := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(2))
+
+	t.Run("empty after construction", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		log := ac.auditLog(rt)
+		assert.Empty(t, log.Entries)
+		assert.Equal(t, uint64(0), log.TotalEntries)
+		ac.checkState(rt)
+	})
+
+	t.Run("administrative actions append entries", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.SetEpoch(10)
+		ac.addVerifier(rt, verifierAddr, allowance)
+		rt.SetEpoch(11)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, verifreg.MinVerifiedDealSize, verifreg.MinVerifiedDealSize)
+		rt.SetEpoch(12)
+		ac.removeVerifier(rt, verifierAddr)
+
+		// Using data cap is not an administrative action.
+		ac.useBytes(rt, clientAddr, verifreg.MinVerifiedDealSize, &capExpectation{removed: true})
+
+		log := ac.auditLog(rt)
+		assert.Equal(t, uint64(3), log.TotalEntries)
+		assert.Equal(t, []verifreg.AuditLogEntry{
+			{Action: verifreg.AuditActionAddVerifier, Epoch: 10, Caller: root, Subject: verifierAddr},
+			{Action: verifreg.AuditActionAddVerifiedClient, Epoch: 11, Caller: verifierAddr, Subject: clientAddr},
+			{Action: verifreg.AuditActionRemoveVerifier, Epoch: 12, Caller: root, Subject: verifierAddr},
+		}, log.Entries)
+		ac.checkState(rt)
+	})
+
+	t.Run("failed actions are not recorded", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		rt.ExpectValidateCallerAddr(ac.rootkey)
+		rt.SetCaller(ac.rootkey, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.RemoveVerifier, &verifierAddr)
+		})
+		rt.Reset()
+
+		log := ac.auditLog(rt)
+		assert.Empty(t, log.Entries)
+		assert.Equal(t, uint64(0), log.TotalEntries)
+		ac.checkState(rt)
+	})
+
+	t.Run("oldest entries are evicted past the cap", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		// Repeatedly add the same verifier, recording one entry per epoch.
+		total := verifreg.AuditLogMaxEntries + 10
+		for i := 0; i < total; i++ {
+			rt.SetEpoch(abi.ChainEpoch(i))
+			ac.addVerifier(rt, verifierAddr, allowance)
+		}
+
+		log := ac.auditLog(rt)
+		assert.Equal(t, uint64(total), log.TotalEntries)
+		require.Len(t, log.Entries, verifreg.AuditLogMaxEntries)
+		assert.Equal(t, abi.ChainEpoch(10), log.Entries[0].Epoch)
+		assert.Equal(t, abi.ChainEpoch(total-1), log.Entries[len(log.Entries)-1].Epoch)
+		ac.checkState(rt)
+	})
+}
+
+func TestTotalDataCap(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifier1 := tutil.NewIDAddr(t, 201)
+	verifier2 := tutil.NewIDAddr(t, 202)
+	client1 := tutil.NewIDAddr(t, 301)
+	client2 := tutil.NewIDAddr(t, 302)
+
+	t.Run("zero after construction", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+
+		ret := ac.totalDataCap(rt)
+		assert.Equal(t, big.Zero(), ret.VerifierDataCap)
+		assert.Equal(t, big.Zero(), ret.ClientDataCap)
+		ac.checkState(rt)
+	})
+
+	t.Run("sums caps of verifiers and clients separately", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		min := verifreg.MinVerifiedDealSize
+
+		ac.addVerifier(rt, verifier1, big.Mul(min, big.NewInt(10)))
+		ac.addVerifier(rt, verifier2, big.Mul(min, big.NewInt(7)))
+		ac.addVerifiedClient(rt, verifier1, client1, big.Mul(min, big.NewInt(3)), big.Mul(min, big.NewInt(3)))
+		ac.addVerifiedClient(rt, verifier2, client2, big.Mul(min, big.NewInt(2)), big.Mul(min, big.NewInt(2)))
+		ac.useBytes(rt, client2, min, &capExpectation{expectedCap: min})
+
+		ret := ac.totalDataCap(rt)
+		// Verifiers hold (10 - 3) + (7 - 2); clients hold 3 + (2 - 1).
+		assert.Equal(t, big.Mul(min, big.NewInt(12)), ret.VerifierDataCap)
+		assert.Equal(t, big.Mul(min, big.NewInt(4)), ret.ClientDataCap)
+
+		// The totals match the sums of individual caps.
+		assert.Equal(t, big.Add(ac.getVerifierCap(rt, verifier1), ac.getVerifierCap(rt, verifier2)), ret.VerifierDataCap)
+		assert.Equal(t, big.Add(ac.getClientCap(rt, client1), ac.getClientCap(rt, client2)), ret.ClientDataCap)
+		ac.checkState(rt)
+	})
+}
+
+func TestVerifierClientCount(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifier1 := tutil.NewIDAddr(t, 201)
+	verifier2 := tutil.NewIDAddr(t, 202)
+	client1 := tutil.NewIDAddr(t, 301)
+	client2 := tutil.NewIDAddr(t, 302)
+	client3 := tutil.NewIDAddr(t, 303)
+	min := verifreg.MinVerifiedDealSize
+
+	t.Run("zero for a verifier without clients", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifier1, big.Mul(min, big.NewInt(10)))
+
+		assert.Equal(t, uint64(0), ac.verifierClientCount(rt, verifier1))
+		// An address that has never been a verifier, or doesn't resolve, also has no clients.
+		assert.Equal(t, uint64(0), ac.verifierClientCount(rt, verifier2))
+		assert.Equal(t, uint64(0), ac.verifierClientCount(rt, tutil.NewBLSAddr(t, 1)))
+		ac.checkState(rt)
+	})
+
+	t.Run("counts clients onboarded by each verifier", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifier1, big.Mul(min, big.NewInt(10)))
+		ac.addVerifier(rt, verifier2, big.Mul(min, big.NewInt(10)))
+
+		ac.addVerifiedClient(rt, verifier1, client1, min, min)
+		ac.addVerifiedClient(rt, verifier1, client2, min, min)
+		ac.addVerifiedClient(rt, verifier2, client3, min, min)
+
+		assert.Equal(t, uint64(2), ac.verifierClientCount(rt, verifier1))
+		assert.Equal(t, uint64(1), ac.verifierClientCount(rt, verifier2))
+		ac.checkState(rt)
+	})
+
+	t.Run("adding cap to an existing client does not count", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifier1, big.Mul(min, big.NewInt(10)))
+		ac.addVerifier(rt, verifier2, big.Mul(min, big.NewInt(10)))
+
+		ac.addVerifiedClient(rt, verifier1, client1, min, min)
+		ac.addVerifiedClient(rt, verifier1, client1, min, big.Mul(min, big.NewInt(2)))
+		ac.addVerifiedClient(rt, verifier2, client1, min, big.Mul(min, big.NewInt(3)))
+
+		assert.Equal(t, uint64(1), ac.verifierClientCount(rt, verifier1))
+		assert.Equal(t, uint64(0), ac.verifierClientCount(rt, verifier2))
+
+		// Once the client has used all its cap it is no longer verified, so adding it again counts.
+		ac.useBytes(rt, client1, big.Mul(min, big.NewInt(3)), &capExpectation{removed: true})
+		ac.addVerifiedClient(rt, verifier2, client1, min, min)
+		assert.Equal(t, uint64(1), ac.verifierClientCount(rt, verifier2))
+		ac.checkState(rt)
+	})
+
+	t.Run("count is preserved when the verifier is removed", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifier1, big.Mul(min, big.NewInt(10)))
+		ac.addVerifier(rt, verifier2, big.Mul(min, big.NewInt(10)))
+		ac.addVerifiedClient(rt, verifier1, client1, min, min)
+		ac.addVerifiedClient(rt, verifier1, client2, min, min)
+		ac.addVerifiedClient(rt, verifier2, client3, min, min)
+
+		ac.removeVerifierAndReclaim(rt, verifier2, verifier1)
+		ac.removeVerifier(rt, verifier1)
+		assert.Equal(t, uint64(2), ac.verifierClientCount(rt, verifier1))
+		assert.Equal(t, uint64(1), ac.verifierClientCount(rt, verifier2))
+
+		// A re-added verifier resumes counting from its previous total.
+		ac.addVerifier(rt, verifier1, big.Mul(min, big.NewInt(10)))
+		ac.useBytes(rt, client1, min, &capExpectation{removed: true})
+		ac.addVerifiedClient(rt, verifier1, client1, min, min)
+		assert.Equal(t, uint64(3), ac.verifierClientCount(rt, verifier1))
+		ac.checkState(rt)
+	})
+}
+
+func TestCanUseBytes(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifier := tutil.NewIDAddr(t, 201)
+	client := tutil.NewIDAddr(t, 301)
+	min := verifreg.MinVerifiedDealSize
+	clientCap := big.Mul(min, big.NewInt(3))
+
+	setup := func(t *testing.T) (*mock.Runtime, *verifRegActorTestHarness) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifier, big.Mul(min, big.NewInt(10)))
+		ac.addVerifiedClient(rt, verifier, client, clientCap, clientCap)
+		return rt, ac
+	}
+
+	t.Run("sufficient cap reports remaining cap without using it", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		ret := ac.canUseBytes(rt, client, min)
+		assert.True(t, ret.Allowed)
+		assert.Equal(t, big.Sub(clientCap, min), ret.RemainingCap)
+		assert.Equal(t, clientCap, ac.getClientCap(rt, client))
+
+		// The dry run agrees with actually using the bytes.
+		ac.useBytes(rt, client, min, &capExpectation{expectedCap: ret.RemainingCap})
+		ac.checkState(rt)
+	})
+
+	t.Run("remaining cap is zero when the client would be removed", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		ret := ac.canUseBytes(rt, client, clientCap)
+		assert.True(t, ret.Allowed)
+		assert.Equal(t, big.Zero(), ret.RemainingCap)
+		assert.Equal(t, clientCap, ac.getClientCap(rt, client))
+		ac.checkState(rt)
+	})
+
+	t.Run("insufficient cap is not allowed", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		ret := ac.canUseBytes(rt, client, big.Add(clientCap, big.NewInt(1)))
+		assert.False(t, ret.Allowed)
+		assert.Equal(t, clientCap, ret.RemainingCap)
+
+		// Deals below the minimum size are not allowed either.
+		ret = ac.canUseBytes(rt, client, big.Sub(min, big.NewInt(1)))
+		assert.False(t, ret.Allowed)
+		assert.Equal(t, clientCap, ret.RemainingCap)
+		ac.checkState(rt)
+	})
+
+	t.Run("unknown client is not allowed", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		ret := ac.canUseBytes(rt, tutil.NewIDAddr(t, 302), min)
+		assert.False(t, ret.Allowed)
+		assert.Equal(t, big.Zero(), ret.RemainingCap)
+
+		// A client address that does not resolve is unknown too.
+		ret = ac.canUseBytes(rt, tutil.NewBLSAddr(t, 1), min)
+		assert.False(t, ret.Allowed)
+		ac.checkState(rt)
+	})
+}
+
+func TestRootKey(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	rt, ac := basicVerifRegSetup(t, root)
+
+	rt.SetCaller(tutil.NewIDAddr(t, 501), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(ac.RootKey, nil).(*address.Address)
+	rt.Verify()
+	assert.Equal(t, root, *ret)
+	ac.checkState(rt)
+}
+
 type verifRegActorTestHarness struct {
 	rootkey address.Address
 	verifreg.Actor
@@ -846,6 +1433,77 @@ func (h *verifRegActorTestHarness) removeVerifier(rt *mock.Runtime, verifier add
 	h.assertVerifierRemoved(rt, verifier)
 }
 
+func (h *verifRegActorTestHarness) removeVerifierAndReclaim(rt *mock.Runtime, verifier, recipient address.Address) verifreg.DataCap {
+	rt.ExpectValidateCallerAddr(h.rootkey)
+
+	rt.SetCaller(h.rootkey, builtin.VerifiedRegistryActorCodeID)
+	params := verifreg.RemoveVerifierAndReclaimParams{Verifier: verifier, Recipient: recipient}
+	ret := rt.Call(h.RemoveVerifierAndReclaim, &params).(*verifreg.RemoveVerifierAndReclaimReturn)
+	rt.Verify()
+
+	h.assertVerifierRemoved(rt, verifier)
+	return ret.Reclaimed
+}
+
+func (h *verifRegActorTestHarness) removeVerifiers(rt *mock.Runtime, verifiers ...address.Address) *verifreg.RemoveVerifiersReturn {
+	rt.ExpectValidateCallerAddr(h.rootkey)
+
+	rt.SetCaller(h.rootkey, builtin.VerifiedRegistryActorCodeID)
+	ret := rt.Call(h.RemoveVerifiers, &verifreg.RemoveVerifiersParams{Verifiers: verifiers}).(*verifreg.RemoveVerifiersReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *verifRegActorTestHarness) setMinVerifiedDealSize(rt *mock.Runtime, size abi.StoragePower) {
+	rt.ExpectValidateCallerAddr(h.rootkey)
+
+	rt.SetCaller(h.rootkey, builtin.VerifiedRegistryActorCodeID)
+	ret := rt.Call(h.SetMinVerifiedDealSize, &verifreg.SetMinVerifiedDealSizeParams{MinVerifiedDealSize: size})
+	rt.Verify()
+
+	require.Nil(h.t, ret)
+	assert.Equal(h.t, size, h.state(rt).MinVerifiedDealSize)
+}
+
+func (h *verifRegActorTestHarness) setMaxClientDataCap(rt *mock.Runtime, ceiling verifreg.DataCap) {
+	rt.ExpectValidateCallerAddr(h.rootkey)
+
+	rt.SetCaller(h.rootkey, builtin.VerifiedRegistryActorCodeID)
+	ret := rt.Call(h.SetMaxClientDataCap, &verifreg.SetMaxClientDataCapParams{MaxClientDataCap: ceiling})
+	rt.Verify()
+
+	require.Nil(h.t, ret)
+	assert.Equal(h.t, ceiling, h.state(rt).MaxClientDataCap)
+}
+
+func (h *verifRegActorTestHarness) auditLog(rt *mock.Runtime) *verifreg.AuditLogReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.AuditLog, nil).(*verifreg.AuditLogReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *verifRegActorTestHarness) totalDataCap(rt *mock.Runtime) *verifreg.TotalDataCapReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.TotalDataCap, nil).(*verifreg.TotalDataCapReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *verifRegActorTestHarness) canUseBytes(rt *mock.Runtime, client address.Address, dealSize verifreg.DataCap) *verifreg.CanUseBytesReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.CanUseBytes, &verifreg.UseBytesParams{Address: client, DealSize: dealSize}).(*verifreg.CanUseBytesReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *verifRegActorTestHarness) verifierClientCount(rt *mock.Runtime, verifier address.Address) uint64 {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.VerifierClientCount, &verifier).(*verifreg.VerifierClientCountReturn)
+	rt.Verify()
+	return ret.Count
+}
+
 type capExpectation struct {
 	expectedCap verifreg.DataCap
 	removed     bool
@@ -857,18 +1515,33 @@ func (h *verifRegActorTestHarness) useBytes(rt *mock.Runtime, a address.Address,
 
 	param := &verifreg.UseBytesParams{Address: a, DealSize: dealSize}
 
-	ret := rt.Call(h.UseBytes, param)
+	// the cap before use, if any, is reported consumed if the client is removed
+	var st verifreg.State
+	rt.GetState(&st)
+	clients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+	var prevCap verifreg.DataCap
+	if clientIdAddr, found := rt.GetIdAddr(a); found {
+		_, err = clients.Get(abi.AddrKey(clientIdAddr), &prevCap)
+		require.NoError(h.t, err)
+	}
+
+	ret := rt.Call(h.UseBytes, param).(*verifreg.UseBytesReturn)
 	rt.Verify()
-	assert.Nil(h.t, ret)
 
 	clientIdAddr, found := rt.GetIdAddr(a)
 	require.True(h.t, found)
 
-	// assert client cap now
+	// assert client cap now, and that the return reports it along with the cap consumed
+	assert.Equal(h.t, expectedCap.removed, ret.ClientRemoved)
 	if expectedCap.removed {
 		h.assertClientRemoved(rt, clientIdAddr)
+		assert.EqualValues(h.t, big.Zero(), ret.RemainingCap)
+		assert.EqualValues(h.t, prevCap, ret.Consumed)
 	} else {
+		assert.EqualValues(h.t, dealSize, ret.Consumed)
 		assert.EqualValues(h.t, expectedCap.expectedCap, h.getClientCap(rt, clientIdAddr))
+		assert.EqualValues(h.t, expectedCap.expectedCap, ret.RemainingCap)
 	}
 }
 
@@ -878,9 +1551,9 @@ func (h *verifRegActorTestHarness) restoreBytes(rt *mock.Runtime, a address.Addr
 
 	// call RestoreBytes
 	param := &verifreg.RestoreBytesParams{Address: a, DealSize: dealSize}
-	ret := rt.Call(h.RestoreBytes, param)
+	ret := rt.Call(h.RestoreBytes, param).(*verifreg.RestoreBytesReturn)
 	rt.Verify()
-	assert.Nil(h.t, ret)
+	assert.EqualValues(h.t, dealSize, ret.Restored)
 
 	clientIdAddr, found := rt.GetIdAddr(a)
 	require.True(h.t, found)
@@ -8,6 +8,7 @@ import (
 	cbor "github.com/ipfs/go-ipld-cbor"
 
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
 
 	market7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/market"
 
@@ -41,6 +42,21 @@ func (m marketMigrator) migrateState(ctx context.Context, store cbor.IpldStore,
 		return nil, err
 	}
 
+	emptyAutoWithdrawDeals, err := adt.StoreEmptyMap(wrappedStore, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	dataCapLedgerCidOut, err := BuildDataCapLedger(ctx, wrappedStore, inState.Proposals, inState.States)
+	if err != nil {
+		return nil, err
+	}
+
+	emptyClientAgents, err := adt.StoreEmptyMap(wrappedStore, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
 	outState := market.State{
 		Proposals:                     proposalsCidOut,
 		States:                        inState.States,
@@ -50,9 +66,19 @@ func (m marketMigrator) migrateState(ctx context.Context, store cbor.IpldStore,
 		NextID:                        inState.NextID,
 		DealOpsByEpoch:                inState.DealOpsByEpoch,
 		LastCron:                      inState.LastCron,
+		LastCronTick: market.CronTickSummary{
+			Epoch:              market.EpochUndefined,
+			AmountSlashed:      big.Zero(),
+			PaymentTransferred: big.Zero(),
+		},
 		TotalClientLockedCollateral:   inState.TotalClientLockedCollateral,
 		TotalProviderLockedCollateral: inState.TotalProviderLockedCollateral,
 		TotalClientStorageFee:         inState.TotalClientStorageFee,
+		AutoWithdrawDeals:             emptyAutoWithdrawDeals,
+		DataCapLedger:                 dataCapLedgerCidOut,
+		ClientAgents:                  emptyClientAgents,
+		DealMinDuration:               market.DealMinDuration,
+		DealMaxDuration:               market.DealMaxDuration,
 	}
 
 	newHead, err := store.Put(ctx, &outState)
@@ -169,6 +195,55 @@ func UpdatePendingProposals(ctx context.Context, store adt.Store, changedProposa
 	return pendingProposalsCid, nil
 }
 
+// BuildDataCapLedger records the DataCap consumed by each verified deal that has not yet activated, so that its
+// restoration can be reconciled if it times out. The DataCap consumed by such deals was not recorded before this
+// migration, so it is taken to be the deal's piece size.
+func BuildDataCapLedger(ctx context.Context, store adt.Store, proposalsRoot cid.Cid, statesRoot cid.Cid) (cid.Cid, error) {
+	states, err := adt.AsArray(store, statesRoot, market7.StatesAmtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	proposals, err := adt.AsArray(store, proposalsRoot, market7.ProposalsAmtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	ledger, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	var dealprop7 market7.DealProposal
+	err = proposals.ForEach(&dealprop7, func(key int64) error {
+		if !dealprop7.VerifiedDeal {
+			return nil
+		}
+
+		// a deal with a state has been activated
+		var dealstate market7.DealState
+		has, err := states.Get(uint64(key), &dealstate)
+		if err != nil {
+			return err
+		}
+		if has {
+			return nil
+		}
+
+		return ledger.Put(abi.UIntKey(uint64(key)), &market.DataCapLedgerEntry{
+			Client:       dealprop7.Client,
+			Consumed:     big.NewIntUnsigned(uint64(dealprop7.PieceSize)),
+			Restored:     big.Zero(),
+			RestoreEpoch: market.EpochUndefined,
+		})
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	return ledger.Root()
+}
+
 // An adt.Map key that just preserves the underlying string.
 type StringKey string
 
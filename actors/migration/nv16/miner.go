@@ -0,0 +1,84 @@
+package nv16
+
+import (
+	"context"
+
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+
+	miner7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/miner"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+)
+
+type minerMigrator struct {
+	OutCodeCID cid.Cid
+}
+
+func (m minerMigrator) migrateState(ctx context.Context, store cbor.IpldStore, in actorMigrationInput) (*actorMigrationResult, error) {
+	var inState miner7.State
+	if err := store.Get(ctx, in.head, &inState); err != nil {
+		return nil, err
+	}
+
+	infoOut, err := migrateMinerInfo(ctx, store, inState.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	outState := miner.State{
+		Info:                       infoOut,
+		PreCommitDeposits:          inState.PreCommitDeposits,
+		LockedFunds:                inState.LockedFunds,
+		VestingFunds:               inState.VestingFunds,
+		FeeDebt:                    inState.FeeDebt,
+		InitialPledge:              inState.InitialPledge,
+		PreCommittedSectors:        inState.PreCommittedSectors,
+		PreCommittedSectorsCleanUp: inState.PreCommittedSectorsCleanUp,
+		AllocatedSectors:           inState.AllocatedSectors,
+		Sectors:                    inState.Sectors,
+		ProvingPeriodStart:         inState.ProvingPeriodStart,
+		CurrentDeadline:            inState.CurrentDeadline,
+		Deadlines:                  inState.Deadlines,
+		EarlyTerminations:          inState.EarlyTerminations,
+		DeadlineCronActive:         inState.DeadlineCronActive,
+	}
+
+	newHead, err := store.Put(ctx, &outState)
+	return &actorMigrationResult{
+		newCodeCID: m.OutCodeCID,
+		newHead:    newHead,
+	}, err
+}
+
+// Re-encodes miner info with the consensus fault type, which was not recorded before this version.
+func migrateMinerInfo(ctx context.Context, store cbor.IpldStore, c cid.Cid) (cid.Cid, error) {
+	var oldInfo miner7.MinerInfo
+	if err := store.Get(ctx, c, &oldInfo); err != nil {
+		return cid.Undef, err
+	}
+
+	var newWorkerKey *miner.WorkerKeyChange
+	if oldInfo.PendingWorkerKey != nil {
+		newWorkerKey = &miner.WorkerKeyChange{
+			NewWorker:   oldInfo.PendingWorkerKey.NewWorker,
+			EffectiveAt: oldInfo.PendingWorkerKey.EffectiveAt,
+		}
+	}
+
+	newInfo := miner.MinerInfo{
+		Owner:                      oldInfo.Owner,
+		Worker:                     oldInfo.Worker,
+		ControlAddresses:           oldInfo.ControlAddresses,
+		PendingWorkerKey:           newWorkerKey,
+		PeerId:                     oldInfo.PeerId,
+		Multiaddrs:                 oldInfo.Multiaddrs,
+		WindowPoStProofType:        oldInfo.WindowPoStProofType,
+		SectorSize:                 oldInfo.SectorSize,
+		WindowPoStPartitionSectors: oldInfo.WindowPoStPartitionSectors,
+		ConsensusFaultElapsed:      oldInfo.ConsensusFaultElapsed,
+		ConsensusFaultType:         0,
+		PendingOwnerAddress:        oldInfo.PendingOwnerAddress,
+	}
+	return store.Put(ctx, &newInfo)
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/rt"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ipld2 "github.com/filecoin-project/specs-actors/v2/support/ipld"
+	power7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/power"
+	verifreg7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/verifreg"
+	vm7 "github.com/filecoin-project/specs-actors/v7/support/vm"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/exported"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/migration/nv16"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestDataCapLedgerMigration(t *testing.T) {
+	ctx := context.Background()
+	log := nv16.TestLogger{TB: t}
+	bs := ipld2.NewSyncBlockStoreInMemory()
+	v := vm7.NewVMWithSingletons(ctx, t, bs)
+	adtStore := adt.WrapStore(ctx, cbor.NewCborStore(bs))
+
+	addrs := vm7.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker, verifier, client := addrs[0], addrs[1], addrs[2]
+	clientID, found := v.NormalizeAddress(client)
+	require.True(t, found)
+
+	params := power7.CreateMinerParams{
+		Owner:               worker,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+	ret := vm7.ApplyOk(t, v, worker, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &params)
+	minerAddrs, ok := ret.(*power7.CreateMinerReturn)
+	require.True(t, ok)
+
+	vm7.ApplyOk(t, v, vm7.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier,
+		&verifreg7.AddVerifierParams{Address: verifier, Allowance: abi.NewStoragePower(32 << 40)})
+	vm7.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifiedClient,
+		&verifreg7.AddVerifiedClientParams{Address: client, Allowance: abi.NewStoragePower(32 << 40)})
+
+	collateral := big.Mul(big.NewInt(64), vm.FIL)
+	vm7.ApplyOk(t, v, client, builtin.StorageMarketActorAddr, collateral, builtin.MethodsMarket.AddBalance, &client)
+	vm7.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, collateral, builtin.MethodsMarket.AddBalance, &minerAddrs.IDAddress)
+
+	// one pending verified deal and one pending unverified deal
+	dealStart := v.GetEpoch() + miner.MaxProveCommitDuration[abi.RegisteredSealProof_StackedDrg32GiBV1_1]
+	verifiedDeal := publishDealv7(t, v, worker, client, minerAddrs.IDAddress, "verified", 1<<30, true, dealStart, 365*builtin.EpochsInDay).IDs[0]
+	unverifiedDeal := publishDealv7(t, v, worker, client, minerAddrs.IDAddress, "unverified", 1<<30, false, dealStart, 365*builtin.EpochsInDay).IDs[0]
+
+	nextRoot, err := nv16.MigrateStateTree(ctx, adtStore, makeTestManifest(t, adtStore), v.StateRoot(), v.GetEpoch(), nv16.Config{MaxWorkers: 1}, log, nv16.NewMemMigrationCache())
+	require.NoError(t, err)
+
+	lookup := map[cid.Cid]rt.VMActor{}
+	for _, ba := range exported.BuiltinActors() {
+		lookup[ba.Code()] = ba
+	}
+	v8, err := vm.NewVMAtEpoch(ctx, lookup, adtStore, nextRoot, v.GetEpoch()+1)
+	require.NoError(t, err)
+
+	// the pending verified deal's DataCap is recorded as consumed
+	var marketState market.State
+	require.NoError(t, v8.GetState(builtin.StorageMarketActorAddr, &marketState))
+	assert.Equal(t, market.EpochUndefined, marketState.LastCronTick.Epoch)
+	ledger, err := adt.AsMap(adtStore, marketState.DataCapLedger, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+
+	var entry market.DataCapLedgerEntry
+	found, err = ledger.Get(abi.UIntKey(uint64(verifiedDeal)), &entry)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, clientID, entry.Client)
+	assert.Equal(t, big.NewInt(1<<30), entry.Consumed)
+	assert.Equal(t, big.Zero(), entry.Restored)
+	assert.Equal(t, market.EpochUndefined, entry.RestoreEpoch)
+
+	found, err = ledger.Get(abi.UIntKey(uint64(unverifiedDeal)), nil)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
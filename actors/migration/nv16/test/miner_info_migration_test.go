@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/rt"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ipld2 "github.com/filecoin-project/specs-actors/v2/support/ipld"
+	miner7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/miner"
+	power7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/power"
+	vm7 "github.com/filecoin-project/specs-actors/v7/support/vm"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/exported"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/migration/nv16"
+	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestMinerInfoMigration(t *testing.T) {
+	ctx := context.Background()
+	log := nv16.TestLogger{TB: t}
+	bs := ipld2.NewSyncBlockStoreInMemory()
+	v := vm7.NewVMWithSingletons(ctx, t, bs)
+	adtStore := adt.WrapStore(ctx, cbor.NewCborStore(bs))
+
+	addrs := vm7.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker := addrs[0]
+
+	params := power7.CreateMinerParams{
+		Owner:               worker,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+	ret := vm7.ApplyOk(t, v, worker, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &params)
+	minerAddrs, ok := ret.(*power7.CreateMinerReturn)
+	require.True(t, ok)
+
+	var miner7State miner7.State
+	require.NoError(t, v.GetState(minerAddrs.IDAddress, &miner7State))
+	oldInfo, err := miner7State.GetInfo(adtStore)
+	require.NoError(t, err)
+
+	nextRoot, err := nv16.MigrateStateTree(ctx, adtStore, makeTestManifest(t, adtStore), v.StateRoot(), v.GetEpoch(), nv16.Config{MaxWorkers: 1}, log, nv16.NewMemMigrationCache())
+	require.NoError(t, err)
+
+	lookup := map[cid.Cid]rt.VMActor{}
+	for _, ba := range exported.BuiltinActors() {
+		lookup[ba.Code()] = ba
+	}
+	v8, err := vm.NewVMAtEpoch(ctx, lookup, adtStore, nextRoot, v.GetEpoch()+1)
+	require.NoError(t, err)
+
+	var minerState miner.State
+	require.NoError(t, v8.GetState(minerAddrs.IDAddress, &minerState))
+	info, err := minerState.GetInfo(adtStore)
+	require.NoError(t, err)
+
+	// Existing fields are carried over and no consensus fault type is recorded.
+	assert.Equal(t, oldInfo.Owner, info.Owner)
+	assert.Equal(t, oldInfo.Worker, info.Worker)
+	assert.Equal(t, oldInfo.PeerId, info.PeerId)
+	assert.Equal(t, oldInfo.WindowPoStProofType, info.WindowPoStProofType)
+	assert.Equal(t, oldInfo.SectorSize, info.SectorSize)
+	assert.Equal(t, oldInfo.ConsensusFaultElapsed, info.ConsensusFaultElapsed)
+	assert.Equal(t, runtime.ConsensusFaultType(0), info.ConsensusFaultType)
+
+	// Other state is unchanged.
+	assert.Equal(t, miner7State.Sectors, minerState.Sectors)
+	assert.Equal(t, miner7State.Deadlines, minerState.Deadlines)
+	assert.Equal(t, miner7State.ProvingPeriodStart, minerState.ProvingPeriodStart)
+}
@@ -93,15 +93,13 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, actorsManifest
 
 	// simple code migrations
 	var simpleMigrations = map[string]cid.Cid{
-		"init":             builtin7.InitActorCodeID,
-		"cron":             builtin7.CronActorCodeID,
-		"account":          builtin7.AccountActorCodeID,
-		"storagepower":     builtin7.StoragePowerActorCodeID,
-		"storageminer":     builtin7.StorageMinerActorCodeID,
-		"paymentchannel":   builtin7.PaymentChannelActorCodeID,
-		"multisig":         builtin7.MultisigActorCodeID,
-		"reward":           builtin7.RewardActorCodeID,
-		"verifiedregistry": builtin7.VerifiedRegistryActorCodeID,
+		"init":           builtin7.InitActorCodeID,
+		"cron":           builtin7.CronActorCodeID,
+		"account":        builtin7.AccountActorCodeID,
+		"storagepower":   builtin7.StoragePowerActorCodeID,
+		"paymentchannel": builtin7.PaymentChannelActorCodeID,
+		"multisig":       builtin7.MultisigActorCodeID,
+		"reward":         builtin7.RewardActorCodeID,
 	}
 
 	for name, code7Cid := range simpleMigrations { //nolint:nomaprange
@@ -124,6 +122,16 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, actorsManifest
 		return cid.Undef, xerrors.Errorf("code cid for market actor not found in manifest")
 	}
 	migrations[builtin7.StorageMarketActorCodeID] = marketMigrator{market8Cid}
+	verifreg8Cid, ok := manifest.Get("verifiedregistry")
+	if !ok {
+		return cid.Undef, xerrors.Errorf("code cid for verified registry actor not found in manifest")
+	}
+	migrations[builtin7.VerifiedRegistryActorCodeID] = verifregMigrator{verifreg8Cid}
+	miner8Cid, ok := manifest.Get("storageminer")
+	if !ok {
+		return cid.Undef, xerrors.Errorf("code cid for miner actor not found in manifest")
+	}
+	migrations[builtin7.StorageMinerActorCodeID] = minerMigrator{miner8Cid}
 
 	if len(migrations)+len(deferredCodeIDs) != len(exported.BuiltinActors()) {
 		return cid.Undef, xerrors.Errorf("incomplete migration specification with %d code CIDs", len(migrations))
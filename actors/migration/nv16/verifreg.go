@@ -0,0 +1,54 @@
+package nv16
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-state-types/big"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+
+	verifreg7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/verifreg"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+type verifregMigrator struct {
+	OutCodeCID cid.Cid
+}
+
+func (m verifregMigrator) migrateState(ctx context.Context, store cbor.IpldStore, in actorMigrationInput) (*actorMigrationResult, error) {
+	var inState verifreg7.State
+	if err := store.Get(ctx, in.head, &inState); err != nil {
+		return nil, err
+	}
+
+	emptyAuditLog, err := adt.StoreEmptyArray(adt.WrapStore(ctx, store), verifreg.AuditLogAmtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	emptyMap, err := adt.StoreEmptyMap(adt.WrapStore(ctx, store), builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	outState := verifreg.State{
+		RootKey:                  inState.RootKey,
+		Verifiers:                inState.Verifiers,
+		VerifiedClients:          inState.VerifiedClients,
+		RemoveDataCapProposalIDs: inState.RemoveDataCapProposalIDs,
+		MinVerifiedDealSize:      verifreg.MinVerifiedDealSize,
+		AuditLog:                 emptyAuditLog,
+		AuditLogNext:             0,
+		VerifierClientCounts:     emptyMap,
+		MaxClientDataCap:         big.Zero(),
+	}
+
+	newHead, err := store.Put(ctx, &outState)
+	return &actorMigrationResult{
+		newCodeCID: m.OutCodeCID,
+		newHead:    newHead,
+	}, err
+}
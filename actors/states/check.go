@@ -142,6 +142,7 @@ func CheckStateInvariants(tree *Tree, expectedBalanceTotal abi.TokenAmount, prio
 	//
 
 	CheckMinersAgainstPower(acc, minerSummaries, powerSummary)
+	CheckPledgeAgainstMiners(acc, minerSummaries, powerSummary)
 	CheckDealStatesAgainstSectors(acc, minerSummaries, marketSummary)
 
 	_ = initSummary
@@ -201,6 +202,21 @@ func CheckMinersAgainstPower(acc *builtin.MessageAccumulator, minerSummaries map
 	}
 }
 
+// Checks the power actor's total pledge collateral against the pledge held by all miners.
+// Miners report changes to both their initial pledge and their locked (vesting) funds to the power actor.
+func CheckPledgeAgainstMiners(acc *builtin.MessageAccumulator, minerSummaries map[addr.Address]*miner.StateSummary, powerSummary *power.StateSummary) {
+	initialPledge := big.Zero()
+	lockedFunds := big.Zero()
+	for _, minerSummary := range minerSummaries { // nolint:nomaprange
+		initialPledge = big.Add(initialPledge, minerSummary.InitialPledge)
+		lockedFunds = big.Add(lockedFunds, minerSummary.LockedFunds)
+	}
+	minerPledge := big.Add(initialPledge, lockedFunds)
+	acc.Require(powerSummary.TotalPledgeCollateral.Equals(minerPledge),
+		"power total pledge collateral %v does not match miners' initial pledge %v plus locked funds %v",
+		powerSummary.TotalPledgeCollateral, initialPledge, lockedFunds)
+}
+
 func CheckDealStatesAgainstSectors(acc *builtin.MessageAccumulator, minerSummaries map[addr.Address]*miner.StateSummary, marketSummary *market.StateSummary) {
 	// Check that all active deals are included within a non-terminated sector.
 	// We cannot check that all deals referenced within a sector are in the market, because deals
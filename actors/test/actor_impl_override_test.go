@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+var legacyMinerCodeID = tutil.MakeCID("fil/test/storageminer-legacy", nil)
+
+// A miner actor from before the MinerAddresses method was introduced.
+type legacyMinerActor struct {
+	miner.Actor
+}
+
+func (a legacyMinerActor) Exports() []interface{} {
+	return a.Actor.Exports()[:builtin.MethodsMiner.MinerAddresses]
+}
+
+func (a legacyMinerActor) Code() cid.Cid {
+	return legacyMinerCodeID
+}
+
+func TestMixedMinerVersions(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker1, worker2 := addrs[0], addrs[1]
+	current := createMiner(t, v, worker1, worker1, abi.RegisteredPoStProof_StackedDrgWindow32GiBV1, big.Zero())
+	legacy := createMiner(t, v, worker2, worker2, abi.RegisteredPoStProof_StackedDrgWindow32GiBV1, big.Zero())
+
+	// Install the legacy implementation in a derived VM and downgrade one miner to it.
+	base := v
+	v, err := base.WithEpoch(base.GetEpoch() + 1)
+	require.NoError(t, err)
+	v.SetActorImpl(legacyMinerCodeID, legacyMinerActor{})
+	require.NoError(t, v.SetActorCode(ctx, legacy.IDAddress, legacyMinerCodeID))
+
+	act, found, err := v.GetActor(legacy.IDAddress)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, legacyMinerCodeID, act.Code)
+	act, found, err = v.GetActor(current.IDAddress)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, builtin.StorageMinerActorCodeID, act.Code)
+
+	// The VM the overriding VM was derived from is unaffected.
+	_, ok := base.GetActorImpls()[legacyMinerCodeID]
+	assert.False(t, ok)
+
+	// Both versions handle methods they have in common, against the same state schema.
+	peer := &miner.ChangePeerIDParams{NewID: abi.PeerID("new peer")}
+	vm.ApplyOk(t, v, worker1, current.RobustAddress, big.Zero(), builtin.MethodsMiner.ChangePeerID, peer)
+	vm.ApplyOk(t, v, worker2, legacy.RobustAddress, big.Zero(), builtin.MethodsMiner.ChangePeerID, peer)
+	for _, minerAddr := range []address.Address{current.IDAddress, legacy.IDAddress} {
+		var st miner.State
+		require.NoError(t, v.GetState(minerAddr, &st))
+		info, err := st.GetInfo(v.Store())
+		require.NoError(t, err)
+		assert.Equal(t, peer.NewID, info.PeerId)
+	}
+
+	// Only the current version has the newer method.
+	ret := vm.ApplyOk(t, v, worker1, current.RobustAddress, big.Zero(), builtin.MethodsMiner.MinerAddresses, nil)
+	workerID, _ := v.NormalizeAddress(worker1)
+	assert.Equal(t, workerID, ret.(*miner.MinerAddressesReturn).Worker)
+	vm.ApplyCode(t, v, worker2, legacy.RobustAddress, big.Zero(), builtin.MethodsMiner.MinerAddresses, nil, exitcode.SysErrInvalidMethod)
+}
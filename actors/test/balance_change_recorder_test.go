@@ -0,0 +1,60 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestBalanceChangeRecorder(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	client := addrs[0]
+	clientID, ok := v.NormalizeAddress(client)
+	require.True(t, ok)
+
+	recorder := vm.NewBalanceChangeRecorder()
+	v.SetBalanceChangeRecorder(recorder)
+
+	threeFIL := big.Mul(big.NewInt(3), vm.FIL)
+	twoFIL := big.Mul(big.NewInt(2), vm.FIL)
+	vm.ApplyOk(t, v, client, builtin.StorageMarketActorAddr, threeFIL, builtin.MethodsMarket.AddBalance, &client)
+
+	added, ok := recorder.Last()
+	require.True(t, ok)
+	assert.Len(t, added.Changes, 2)
+	assert.Equal(t, threeFIL.Neg(), added.Delta(clientID))
+	assert.Equal(t, threeFIL, added.Delta(builtin.StorageMarketActorAddr))
+
+	vm.ApplyOk(t, v, client, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.WithdrawBalance,
+		&market.WithdrawBalanceParams{ProviderOrClientAddress: client, Amount: twoFIL})
+
+	withdrawn, ok := recorder.Last()
+	require.True(t, ok)
+	assert.Equal(t, builtin.MethodsMarket.WithdrawBalance, withdrawn.Method)
+	assert.Len(t, withdrawn.Changes, 2)
+	assert.Equal(t, twoFIL, withdrawn.Delta(clientID))
+	assert.Equal(t, twoFIL.Neg(), withdrawn.Delta(builtin.StorageMarketActorAddr))
+
+	// A failed message is recorded without any balance changes.
+	vm.ApplyCode(t, v, client, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.WithdrawBalance,
+		&market.WithdrawBalanceParams{ProviderOrClientAddress: client, Amount: twoFIL.Neg()}, exitcode.ErrIllegalArgument)
+	failed, ok := recorder.Last()
+	require.True(t, ok)
+	assert.Empty(t, failed.Changes)
+	assert.Equal(t, big.Zero(), failed.Delta(clientID))
+
+	assert.Len(t, recorder.Messages(), 3)
+	recorder.Reset()
+	assert.Empty(t, recorder.Messages())
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestApplyBundle(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	sender, recipient := addrs[0], addrs[1]
+
+	transfer := vm.BundleMessage{From: sender, To: recipient, Value: vm.FIL, Method: builtin.MethodSend}
+	badWithdrawal := vm.BundleMessage{From: sender, To: builtin.StorageMarketActorAddr, Value: big.Zero(),
+		Method: builtin.MethodsMarket.WithdrawBalance,
+		Params: &market.WithdrawBalanceParams{ProviderOrClientAddress: sender, Amount: vm.FIL.Neg()}}
+
+	t.Run("failure of a later message reverts earlier ones", func(t *testing.T) {
+		tv, err := v.WithEpoch(v.GetEpoch())
+		require.NoError(t, err)
+		rootBefore := tv.StateRoot()
+
+		results, err := tv.ApplyBundle([]vm.BundleMessage{transfer, badWithdrawal, transfer}, t.Name())
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, exitcode.Ok, results[0].Code)
+		assert.Equal(t, exitcode.ErrIllegalArgument, results[1].Code)
+
+		// The successful transfer and both call sequence increments are reverted.
+		assert.Equal(t, rootBefore, tv.StateRoot())
+		senderActor, found, err := tv.GetActor(sender)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, uint64(0), senderActor.CallSeqNum)
+		assert.Equal(t, big.Mul(big.NewInt(10), vm.FIL), senderActor.Balance)
+		recipientActor, found, err := tv.GetActor(recipient)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, big.Mul(big.NewInt(10), vm.FIL), recipientActor.Balance)
+	})
+
+	t.Run("all messages apply when none fail", func(t *testing.T) {
+		tv, err := v.WithEpoch(v.GetEpoch())
+		require.NoError(t, err)
+
+		results, err := tv.ApplyBundle([]vm.BundleMessage{transfer, transfer}, t.Name())
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, r := range results {
+			assert.Equal(t, exitcode.Ok, r.Code)
+		}
+
+		senderActor, found, err := tv.GetActor(sender)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, uint64(2), senderActor.CallSeqNum)
+		recipientActor, found, err := tv.GetActor(recipient)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, big.Mul(big.NewInt(12), vm.FIL), recipientActor.Balance)
+	})
+}
@@ -154,6 +154,23 @@ func TestCommitPoStFlow(t *testing.T) {
 	// Submit PoSt
 	//
 
+	t.Run("submit PoSt at computed deadline open", func(t *testing.T) {
+		dlIdx, pIdx := vm.SectorDeadline(t, v, minerAddrs.IDAddress, sectorNumber)
+		openEpoch, closeEpoch := vm.DeadlineOpenClose(t, v, minerAddrs.IDAddress, dlIdx)
+		require.Greater(t, openEpoch, v.GetEpoch())
+
+		tv, dlInfo := vm.AdvanceTillDeadlineOpen(t, v, minerAddrs.IDAddress, dlIdx)
+		assert.Equal(t, openEpoch, tv.GetEpoch())
+		assert.Equal(t, dlIdx, dlInfo.Index)
+		assert.Equal(t, openEpoch, dlInfo.Open)
+		assert.Equal(t, closeEpoch, dlInfo.Close)
+		assert.True(t, dlInfo.IsOpen())
+
+		vm.SubmitPoSt(t, tv, minerAddrs.IDAddress, worker, dlInfo, pIdx)
+		networkStats := vm.GetNetworkStats(t, tv)
+		assert.Equal(t, big.NewInt(int64(sectorSize)), networkStats.TotalBytesCommitted)
+	})
+
 	// advance to proving period
 	dlInfo, pIdx, v := vm.AdvanceTillProvingDeadline(t, v, minerAddrs.IDAddress, sectorNumber)
 	var minerState miner.State
@@ -196,6 +213,28 @@ func TestCommitPoStFlow(t *testing.T) {
 		assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
 	})
 
+	t.Run("power pledge total mismatching miner pledge is flagged", func(t *testing.T) {
+		tv, err := v.WithEpoch(v.GetEpoch())
+		require.NoError(t, err)
+
+		// Trigger cron so reward accounting is consistent and the pledge mismatch is the only violation
+		vm.ApplyOk(t, tv, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+		var powerState power.State
+		require.NoError(t, tv.GetState(builtin.StoragePowerActorAddr, &powerState))
+		powerState.TotalPledgeCollateral = big.Add(powerState.TotalPledgeCollateral, big.NewInt(1))
+		require.NoError(t, tv.SetActorState(ctx, builtin.StoragePowerActorAddr, &powerState))
+
+		stateTree, err := tv.GetStateTree()
+		require.NoError(t, err)
+		totalBalance, err := tv.GetTotalActorBalance()
+		require.NoError(t, err)
+		acc, err := states.CheckStateInvariants(stateTree, totalBalance, tv.GetEpoch())
+		require.NoError(t, err)
+		require.Len(t, acc.Messages(), 1)
+		assert.Contains(t, acc.Messages()[0], "power total pledge collateral")
+	})
+
 	t.Run("skip sector", func(t *testing.T) {
 		tv, err := v.WithEpoch(v.GetEpoch())
 		require.NoError(t, err)
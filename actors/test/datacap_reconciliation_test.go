@@ -0,0 +1,106 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/actors/states"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+// A verified deal that exhausts its client's DataCap forfeits the remainder below the minimum deal size
+// (https://github.com/filecoin-project/specs-actors/issues/727). If the deal times out, only its size is
+// restored, and the market reports the discrepancy until the client clears it.
+func TestDataCapForfeitedRemainderReconciliation(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker, verifier, verifiedClient := addrs[0], addrs[1], addrs[2]
+	clientID, found := v.NormalizeAddress(verifiedClient)
+	require.True(t, found)
+
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+	wPoStProof, err := sealProof.RegisteredWindowPoStProof()
+	require.NoError(t, err)
+	minerAddrs := createMiner(t, v, worker, worker, wPoStProof, big.Mul(big.NewInt(1_000), vm.FIL))
+
+	// grant the client DataCap for the deal plus a remainder below the minimum deal size
+	dealSize := abi.PaddedPieceSize(1 << 30)
+	dust := big.Sub(verifreg.MinVerifiedDealSize, big.NewInt(1))
+	allowance := big.Add(big.NewIntUnsigned(uint64(dealSize)), dust)
+	addVerifierParams := verifreg.AddVerifierParams{
+		Address:   verifier,
+		Allowance: abi.NewStoragePower(32 << 40),
+	}
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &addVerifierParams)
+	addClientParams := verifreg.AddVerifiedClientParams{
+		Address:   verifiedClient,
+		Allowance: allowance,
+	}
+	vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifiedClient, &addClientParams)
+
+	vm.ApplyOk(t, v, verifiedClient, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(3), vm.FIL), builtin.MethodsMarket.AddBalance, &verifiedClient)
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(64), vm.FIL), builtin.MethodsMarket.AddBalance, &minerAddrs.IDAddress)
+
+	dealStart := v.GetEpoch() + miner.MaxProveCommitDuration[sealProof]
+	deals := publishDeal(t, v, worker, verifiedClient, minerAddrs.IDAddress, "deal1", dealSize, true, dealStart, 180*builtin.EpochsInDay)
+	dealID := deals.IDs[0]
+
+	// the whole allowance is consumed and pending
+	ret := vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.DataCapReconciliation, nil).(*market.DataCapReconciliationReturn)
+	assert.Equal(t, uint64(1), ret.Pending)
+	assert.Empty(t, ret.Mismatches)
+
+	// the deal is never activated, and times out
+	timeoutEpoch := market.GenRandNextEpoch(dealStart, dealID)
+	v, _ = vm.AdvanceByDeadlineTillEpoch(t, v, minerAddrs.IDAddress, timeoutEpoch)
+	v, err = v.WithEpoch(timeoutEpoch)
+	require.NoError(t, err)
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	// only the deal's size was restored
+	var vrState verifreg.State
+	require.NoError(t, v.GetState(builtin.VerifiedRegistryActorAddr, &vrState))
+	verifiedClients, err := adt.AsMap(v.Store(), vrState.VerifiedClients, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var clientCap verifreg.DataCap
+	found, err = verifiedClients.Get(abi.AddrKey(clientID), &clientCap)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, big.NewIntUnsigned(uint64(dealSize)), clientCap)
+
+	ret = vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.DataCapReconciliation, nil).(*market.DataCapReconciliationReturn)
+	assert.Equal(t, uint64(0), ret.Pending)
+	require.Len(t, ret.Mismatches, 1)
+	assert.Equal(t, dealID, ret.Mismatches[0].DealID)
+	assert.Equal(t, clientID, ret.Mismatches[0].Client)
+	assert.Equal(t, allowance, ret.Mismatches[0].Consumed)
+	assert.Equal(t, big.NewIntUnsigned(uint64(dealSize)), ret.Mismatches[0].Restored)
+	assert.Equal(t, timeoutEpoch, ret.Mismatches[0].RestoreEpoch)
+
+	// the client clears the mismatch
+	vm.ApplyOk(t, v, verifiedClient, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.ClearDataCapMismatches,
+		&market.ClearDataCapMismatchesParams{DealIDs: []abi.DealID{dealID}})
+	ret = vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.DataCapReconciliation, nil).(*market.DataCapReconciliationReturn)
+	assert.Empty(t, ret.Mismatches)
+
+	stateTree, err := v.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v.GetEpoch())
+	require.NoError(t, err)
+	assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+}
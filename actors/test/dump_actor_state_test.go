@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xorcare/golden"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestDumpVerifregActorState(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	verifier, verifiedClient := addrs[0], addrs[1]
+	verifierIdAddr, _ := v.NormalizeAddress(verifier)
+	verifiedClientIdAddr, _ := v.NormalizeAddress(verifiedClient)
+	verifierAllowance := abi.NewStoragePower(2 * (32 << 30))
+	clientAllowance := abi.NewStoragePower(32 << 30)
+
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier,
+		&verifreg.AddVerifierParams{Address: verifier, Allowance: verifierAllowance})
+	vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifiedClient,
+		&verifreg.AddVerifiedClientParams{Address: verifiedClient, Allowance: clientAllowance})
+
+	dump, err := v.DumpActorState(builtin.VerifiedRegistryActorAddr)
+	require.NoError(t, err)
+	assert.Contains(t, dump, "verifiedregistry")
+	assert.Contains(t, dump, "verifiers (1):")
+	assert.Contains(t, dump, fmt.Sprintf("%v: %v", verifierIdAddr, big.Sub(verifierAllowance, clientAllowance)))
+	assert.Contains(t, dump, "verified clients (1):")
+	assert.Contains(t, dump, fmt.Sprintf("%v: %v", verifiedClientIdAddr, clientAllowance))
+
+	// Actors without a registered collection dumper still print their decoded state.
+	dump, err = v.DumpActorState(verifierIdAddr)
+	require.NoError(t, err)
+	assert.Contains(t, dump, "account")
+}
+
+func TestExportVerifregActorStateJSON(t *testing.T) {
+	ctx := context.Background()
+	export := func() []byte {
+		v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+		out, err := v.ExportActorStateJSON(builtin.VerifiedRegistryActorAddr)
+		require.NoError(t, err)
+		return out
+	}
+
+	// The export of a freshly constructed state is identical across independently constructed VMs.
+	out := export()
+	assert.Equal(t, string(out), string(export()))
+	golden.Assert(t, out)
+}
@@ -0,0 +1,50 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestExitCodeCoverage(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	verifier := addrs[0]
+
+	coverage := vm.NewExitCodeCoverage()
+	v.SetExitCodeCoverage(coverage)
+
+	// An allowance below the minimum is rejected as an illegal argument.
+	tooSmall := big.Sub(verifreg.MinVerifiedDealSize, big.NewInt(1))
+	vm.ApplyCode(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier,
+		&verifreg.AddVerifierParams{Address: verifier, Allowance: tooSmall}, exitcode.ErrIllegalArgument)
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier,
+		&verifreg.AddVerifierParams{Address: verifier, Allowance: verifreg.MinVerifiedDealSize})
+
+	assert.Equal(t, uint64(1), coverage.Count(builtin.VerifiedRegistryActorCodeID, builtin.MethodsVerifiedRegistry.AddVerifier, exitcode.ErrIllegalArgument))
+	assert.Equal(t, uint64(1), coverage.Count(builtin.VerifiedRegistryActorCodeID, builtin.MethodsVerifiedRegistry.AddVerifier, exitcode.Ok))
+	assert.Equal(t, uint64(0), coverage.Count(builtin.VerifiedRegistryActorCodeID, builtin.MethodsVerifiedRegistry.AddVerifier, exitcode.ErrForbidden))
+
+	// Coverage is shared with VMs derived from this one.
+	v, err := v.WithEpoch(v.GetEpoch() + 1)
+	assert.NoError(t, err)
+	vm.ApplyCode(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier,
+		&verifreg.AddVerifierParams{Address: verifier, Allowance: tooSmall}, exitcode.ErrIllegalArgument)
+	assert.Equal(t, uint64(2), coverage.Count(builtin.VerifiedRegistryActorCodeID, builtin.MethodsVerifiedRegistry.AddVerifier, exitcode.ErrIllegalArgument))
+
+	var report bytes.Buffer
+	assert.NoError(t, coverage.WriteReport(&report))
+	assert.Contains(t, report.String(), fmt.Sprintf("%s\t%d\t%s\t2\n", builtin.ActorNameByCode(builtin.VerifiedRegistryActorCodeID),
+		builtin.MethodsVerifiedRegistry.AddVerifier, exitcode.ErrIllegalArgument))
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+// Prices storage of state at a multiple of the default.
+type storageMultiplierPricelist struct {
+	vm.Pricelist
+	multiplier int64
+}
+
+func (pl storageMultiplierPricelist) OnIpldPut(dataSize int) vm.GasCharge {
+	charge := pl.Pricelist.OnIpldPut(dataSize)
+	charge.StorageGas *= pl.multiplier
+	return charge
+}
+
+func TestInjectedPricelist(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	worker := addrs[0]
+
+	// Creating a miner writes new state, so is sensitive to the price of storage.
+	params := power.CreateMinerParams{
+		Owner:               worker,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+	// Applies the message to a copy of the VM, so each measurement starts from the same state.
+	measure := func(v *vm.VM) int64 {
+		tv, err := v.WithEpoch(v.GetEpoch())
+		require.NoError(t, err)
+		result := vm.RequireApplyMessage(t, tv, worker, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params, t.Name())
+		require.Equal(t, exitcode.Ok, result.Code)
+		return result.GasCharged
+	}
+
+	defaultGas := measure(v)
+
+	v.SetPricelist(storageMultiplierPricelist{vm.DefaultPricelist(), 2})
+	doubledGas := measure(v)
+	assert.Greater(t, doubledGas, defaultGas)
+
+	// Derived VMs inherit the injected pricing.
+	derived, err := v.WithEpoch(v.GetEpoch() + 1)
+	require.NoError(t, err)
+	assert.Equal(t, v.GetPricelist(), derived.GetPricelist())
+
+	v.SetPricelist(nil)
+	assert.Equal(t, defaultGas, measure(v))
+}
+
+func TestEstimateGas(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	worker := addrs[0]
+
+	params := power.CreateMinerParams{
+		Owner:               worker,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+	rootBefore := v.StateRoot()
+	invocationsBefore := len(v.Invocations())
+	workerBefore := requireActor(t, v, worker)
+
+	estimate, err := v.EstimateGas(worker, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params)
+	require.NoError(t, err)
+	require.Equal(t, exitcode.Ok, estimate.Code)
+	assert.Greater(t, estimate.GasCharged, int64(0))
+
+	// Estimation leaves no trace in the state or the invocation record.
+	assert.Equal(t, rootBefore, v.StateRoot())
+	assert.Equal(t, invocationsBefore, len(v.Invocations()))
+	assert.Equal(t, workerBefore, requireActor(t, v, worker))
+
+	result := vm.RequireApplyMessage(t, v, worker, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params, t.Name())
+	require.Equal(t, exitcode.Ok, result.Code)
+	assert.Equal(t, estimate.GasCharged, result.GasCharged)
+	assert.Equal(t, estimate.Ret, result.Ret)
+}
+
+// Params that cannot be serialized, failing the message before it is executed.
+type unserializableParams struct{}
+
+func (unserializableParams) MarshalCBOR(io.Writer) error   { return xerrors.New("cannot serialize") }
+func (unserializableParams) UnmarshalCBOR(io.Reader) error { return xerrors.New("cannot deserialize") }
+
+func TestEstimateGasRevertsFailedMessage(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	worker := addrs[0]
+
+	rootBefore := v.StateRoot()
+	workerBefore := requireActor(t, v, worker)
+
+	// The sender's call sequence number is incremented before the params fail to serialize.
+	_, err := v.EstimateGas(worker, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, unserializableParams{})
+	require.Error(t, err)
+
+	assert.Equal(t, rootBefore, v.StateRoot())
+	assert.Equal(t, workerBefore, requireActor(t, v, worker))
+}
@@ -96,6 +96,30 @@ func TestMinerWithdraw(t *testing.T) {
 		assertAddCollateralAndWithdraw(t, ctx, v, big.Zero(), big.Zero(), threeFIL, mAddr, owner)
 	})
 
+	t.Run("withdraw balance set directly", func(t *testing.T) {
+		// Fund the miner without a transfer from the owner.
+		fiveFIL := big.Mul(big.NewInt(5), vm.FIL)
+		initialBalance := requireActor(t, v, mAddr).Balance
+		require.NoError(t, v.SetActorBalance(mAddr, big.Add(initialBalance, fiveFIL)))
+		ownerBalance := requireActor(t, v, owner).Balance
+
+		params := &miner.WithdrawBalanceParams{
+			AmountRequested: fiveFIL,
+		}
+		ret := vm.ApplyOk(t, v, owner, mAddr, big.Zero(), builtin.MethodsMiner.WithdrawBalance, params)
+		withdrawn, ok := ret.(*abi.TokenAmount)
+		require.True(t, ok)
+		assert.Equal(t, fiveFIL, *withdrawn)
+		assert.Equal(t, initialBalance, requireActor(t, v, mAddr).Balance)
+		assert.Equal(t, big.Add(ownerBalance, fiveFIL), requireActor(t, v, owner).Balance)
+	})
+
+	t.Run("setting balance outside test mode fails", func(t *testing.T) {
+		nonTest, err := vm.NewVMAtEpoch(ctx, v.ActorImpls, v.Store(), v.StateRoot(), v.GetEpoch())
+		require.NoError(t, err)
+		require.Error(t, nonTest.SetActorBalance(mAddr, vm.FIL))
+	})
+
 	t.Run("withdraw from non-owner address fails", func(t *testing.T) {
 		oneFIL := big.Mul(big.NewInt(1), vm.FIL)
 		vm.ApplyOk(t, v, worker, mAddr, oneFIL, builtin.MethodSend, nil)
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestMessageOutOfGas(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	worker := addrs[0]
+
+	// Creating a miner constructs a new actor, so is relatively expensive.
+	params := power.CreateMinerParams{
+		Owner:               worker,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+
+	// Measure the gas required on a copy of the VM, leaving the original state untouched.
+	measure, err := v.WithEpoch(v.GetEpoch())
+	require.NoError(t, err)
+	required := vm.RequireApplyMessage(t, measure, worker, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params, t.Name())
+	require.Equal(t, exitcode.Ok, required.Code)
+
+	workerBefore, found, err := v.GetActor(worker)
+	require.NoError(t, err)
+	require.True(t, found)
+	defaultLimit := v.GetGasLimit()
+
+	v.SetGasLimit(required.GasCharged / 2)
+	assert.Equal(t, required.GasCharged/2, v.GetGasLimit())
+	vm.ApplyCode(t, v, worker, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params, exitcode.SysErrOutOfGas)
+
+	// No miner was created, and only the sender's call sequence number changed.
+	var powerState power.State
+	require.NoError(t, v.GetState(builtin.StoragePowerActorAddr, &powerState))
+	assert.Equal(t, int64(0), powerState.MinerCount)
+
+	workerAfter, found, err := v.GetActor(worker)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, workerBefore.CallSeqNum+1, workerAfter.CallSeqNum)
+	assert.Equal(t, workerBefore.Balance, workerAfter.Balance)
+
+	// A message that can't pay for its own inclusion is not executed.
+	v.SetGasLimit(1)
+	vm.ApplyCode(t, v, worker, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params, exitcode.SysErrOutOfGas)
+
+	// Restoring the limit allows the message to succeed.
+	v.SetGasLimit(defaultLimit)
+	vm.ApplyOk(t, v, worker, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.CreateMiner, &params)
+	require.NoError(t, v.GetState(builtin.StoragePowerActorAddr, &powerState))
+	assert.Equal(t, int64(1), powerState.MinerCount)
+}
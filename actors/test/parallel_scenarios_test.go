@@ -0,0 +1,52 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+// Run with -race to check that concurrent scenarios share no mutable state.
+func TestRunScenariosParallel(t *testing.T) {
+	transfer := func(amount int64) func(t *testing.T, v *vm.VM) *vm.VM {
+		return func(t *testing.T, v *vm.VM) *vm.VM {
+			ctx := context.Background()
+			addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+			from, to := addrs[0], addrs[1]
+
+			value := big.Mul(big.NewInt(amount), vm.FIL)
+			vm.ApplyOk(t, v, from, to, value, builtin.MethodSend, nil)
+			v = vm.RunEpochs(t, v, 1, 10, nil)
+
+			toActor, found, err := v.GetActor(to)
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, big.Add(big.Mul(big.NewInt(10_000), vm.FIL), value), toActor.Balance)
+			return v
+		}
+	}
+
+	results := vm.RunScenariosParallel(t,
+		vm.Scenario{Name: "transfer 1", Run: transfer(1)},
+		vm.Scenario{Name: "transfer 2", Run: transfer(2)},
+		vm.Scenario{Name: "transfer 3", Run: transfer(3)},
+		vm.Scenario{Name: "transfer 1 again", Run: transfer(1)},
+	)
+	require.Len(t, results, 4)
+	for _, r := range results {
+		assert.False(t, r.Failed, "scenario %s failed", r.Name)
+		assert.Equal(t, abi.ChainEpoch(10), r.Epoch)
+	}
+
+	// Identical scenarios reach identical states, distinct ones do not.
+	assert.Equal(t, results[0].StateRoot, results[3].StateRoot)
+	assert.NotEqual(t, results[0].StateRoot, results[1].StateRoot)
+	assert.NotEqual(t, results[1].StateRoot, results[2].StateRoot)
+}
@@ -1,11 +1,16 @@
 package test
 
 import (
+	"bytes"
 	"context"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
@@ -61,6 +66,45 @@ func TestCreateMiner(t *testing.T) {
 	}.Matches(t, v.Invocations()[0])
 }
 
+func TestCreateMinerFoldedStacks(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+
+	params := power.CreateMinerParams{
+		Owner:               addrs[0],
+		Worker:              addrs[0],
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+	vm.ApplyOk(t, v, addrs[0], builtin.StoragePowerActorAddr, big.NewInt(1e10), builtin.MethodsPower.CreateMiner, &params)
+
+	var buf bytes.Buffer
+	require.NoError(t, v.WriteFoldedStacks(&buf))
+
+	// Each nested send appears as a frame below its caller, with the gas charged within that frame.
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	stacks := make(map[string]bool, len(lines))
+	var total int64
+	for _, line := range lines {
+		sep := strings.LastIndexByte(line, ' ')
+		require.Greater(t, sep, 0, "malformed line %q", line)
+		gas, err := strconv.ParseInt(line[sep+1:], 10, 64)
+		require.NoError(t, err)
+		assert.Greater(t, gas, int64(0))
+		stacks[line[:sep]] = true
+		if strings.HasPrefix(line, "fil/8/storagepower.CreateMiner") {
+			total += gas
+		}
+	}
+	assert.True(t, stacks["fil/8/storagepower.CreateMiner"])
+	assert.True(t, stacks["fil/8/storagepower.CreateMiner;fil/8/init.Exec"])
+	assert.True(t, stacks["fil/8/storagepower.CreateMiner;fil/8/init.Exec;fil/8/storageminer.Constructor"])
+
+	// Self gas of the frames below the create miner message sums to the gas charged to it.
+	assert.Equal(t, v.LastInvocation().GasUsed, total)
+}
+
 func TestCronTick(t *testing.T) {
 	ctx := context.Background()
 	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
@@ -152,3 +196,44 @@ func TestCronTick(t *testing.T) {
 			}},
 	}.Matches(t, v.Invocations()[0])
 }
+
+func TestReconcileClaimByRootKey(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), big.NewInt(1e18)), 93837778)
+	owner := addrs[0]
+
+	wPoStProof := abi.RegisteredPoStProof_StackedDrgWindow32GiBV1
+	minerAddrs := createMiner(t, v, owner, owner, wPoStProof, big.NewInt(1e10))
+	sectorSize, err := wPoStProof.SectorSize()
+	require.NoError(t, err)
+	sectorPower := big.NewIntUnsigned(uint64(sectorSize))
+
+	params := power.ReconcileClaimParams{
+		Miner:           minerAddrs.IDAddress,
+		RawBytePower:    sectorPower,
+		QualityAdjPower: sectorPower,
+	}
+
+	// only the verified registry's root key may reconcile claims
+	vm.ApplyCode(t, v, owner, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.ReconcileClaim, &params, exitcode.SysErrForbidden)
+
+	ret := vm.ApplyOk(t, v, vm.VerifregRoot, builtin.StoragePowerActorAddr, big.Zero(), builtin.MethodsPower.ReconcileClaim, &params).(*power.ReconcileClaimReturn)
+	assert.Equal(t, big.Zero(), ret.PreviousClaim.RawBytePower)
+
+	vm.ExpectInvocation{
+		To:     builtin.StoragePowerActorAddr,
+		Method: builtin.MethodsPower.ReconcileClaim,
+		SubInvocations: []vm.ExpectInvocation{
+			{To: builtin.VerifiedRegistryActorAddr, Method: builtin.MethodsVerifiedRegistry.RootKey},
+		},
+	}.Matches(t, v.Invocations()[2])
+
+	var st power.State
+	require.NoError(t, v.GetState(builtin.StoragePowerActorAddr, &st))
+	claim, found, err := st.GetClaim(v.Store(), minerAddrs.IDAddress)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, sectorPower, claim.RawBytePower)
+	assert.Equal(t, sectorPower, claim.QualityAdjPower)
+}
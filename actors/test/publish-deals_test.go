@@ -1,12 +1,14 @@
 package test
 
 import (
+	"bytes"
 	"context"
 	"math"
 	"testing"
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/power"
@@ -121,6 +123,28 @@ func TestPublishStorageDealsFailure(t *testing.T) {
 		assert.Equal(t, 1, len(dealRet.IDs))
 	})
 
+	t.Run("invalid client signature", func(t *testing.T) {
+		dealStart := v.GetEpoch() + miner.MaxProveCommitDuration[sealProof]
+		batcher := newDealBatcher(v)
+		// good deal
+		batcher.stage(t, client1, minerAddrs.IDAddress, "run14-deal0", 1<<30, false, dealStart, dealLifeTime,
+			defaultPricePerEpoch, defaultProviderCollateral, defaultClientCollateral)
+		// bad deal -- client signature is rejected by the verifier
+		batcher.stage(t, client2, minerAddrs.IDAddress, "run14-deal1", 1<<30, false, dealStart, dealLifeTime,
+			defaultPricePerEpoch, defaultProviderCollateral, defaultClientCollateral)
+		badSig := new(bytes.Buffer)
+		require.NoError(t, batcher.deals[1].MarshalCBOR(badSig))
+
+		v.SetSignatureVerifier(vm.RejectSignatures(vm.DefaultSignatureVerifier, badSig.Bytes()))
+		defer v.SetSignatureVerifier(nil)
+
+		dealRet := batcher.publishOK(t, worker)
+		goodInputs, err := dealRet.ValidDeals.All(math.MaxUint64)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{0}, goodInputs)
+		assert.Equal(t, 1, len(dealRet.IDs))
+	})
+
 	t.Run("no client lockup", func(t *testing.T) {
 		/* added no market collateral for cheapClient */
 
@@ -392,4 +416,46 @@ func TestPublishStorageDealsFailure(t *testing.T) {
 		assert.Equal(t, 5, len(dealRet.IDs))
 	})
 
+	t.Run("verified deal uses client datacap", func(t *testing.T) {
+		vAddrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10_000), vm.FIL), 666)
+		verifier, verifiedClient := vAddrs[0], vAddrs[1]
+
+		addVerifierParams := verifreg.AddVerifierParams{
+			Address:   verifier,
+			Allowance: abi.NewStoragePower(32 << 40),
+		}
+		vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &addVerifierParams)
+		addClientParams := verifreg.AddVerifiedClientParams{
+			Address:   verifiedClient,
+			Allowance: abi.NewStoragePower(1 << 34),
+		}
+		vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifiedClient, &addClientParams)
+		vm.ApplyOk(t, v, verifiedClient, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(100), vm.FIL), builtin.MethodsMarket.AddBalance, &verifiedClient)
+		clientID, found := v.NormalizeAddress(verifiedClient)
+		require.True(t, found)
+
+		dealStart := v.GetEpoch() + miner.MaxProveCommitDuration[sealProof]
+		batcher := newDealBatcher(v)
+		batcher.stage(t, verifiedClient, minerAddrs.IDAddress, "run13-deal0", 1<<30, false, dealStart, dealLifeTime,
+			defaultPricePerEpoch, defaultProviderCollateral, defaultClientCollateral)
+		batcher.stage(t, verifiedClient, minerAddrs.IDAddress, "run13-deal1", 1<<32, true, dealStart, dealLifeTime,
+			defaultPricePerEpoch, defaultProviderCollateral, defaultClientCollateral)
+		batcher.publishOK(t, worker)
+
+		// Only the verified deal's size is charged to the client.
+		useBytes := vm.ExpectSend{
+			From:   builtin.StorageMarketActorAddr,
+			To:     builtin.VerifiedRegistryActorAddr,
+			Method: builtin.MethodsVerifiedRegistry.UseBytes,
+		}
+		sends := useBytes.FindIn(v.LastInvocation())
+		require.Len(t, sends, 1)
+
+		useBytes.Params = func(params interface{}) bool {
+			p, ok := params.(*verifreg.UseBytesParams)
+			return ok && p.Address == clientID && p.DealSize.Equals(big.NewInt(1<<32))
+		}
+		send := useBytes.OccurredIn(t, v.LastInvocation())
+		assert.Equal(t, exitcode.Ok, send.Exitcode)
+	})
 }
@@ -232,6 +232,38 @@ func TestUnhealthySectorFailure(t *testing.T) {
 		&miner.ProveReplicaUpdatesParams{Updates: []miner.ReplicaUpdate{replicaUpdate}}, exitcode.ErrIllegalArgument)
 }
 
+// Tests that a sector which already holds deals cannot be updated again
+func TestNonCCSectorFailure(t *testing.T) {
+	v, sectorInfo, worker, minerAddrs, deadlineIndex, partitionIndex, _ := createMinerAndUpgradeASector(t)
+	require.NotEmpty(t, sectorInfo.DealIDs)
+
+	// make another deal, distinct from the one already in the sector so that it can be published
+	collateral := big.Mul(big.NewInt(3), vm.FIL)
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, collateral, builtin.MethodsMarket.AddBalance, &worker)
+	collateral = big.Mul(big.NewInt(64), vm.FIL)
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, collateral, builtin.MethodsMarket.AddBalance, &minerAddrs.IDAddress)
+	dealStart := v.GetEpoch() + miner.MaxProveCommitDuration[sectorInfo.SealProof]
+	dealIDs := publishDeal(t, v, worker, worker, minerAddrs.IDAddress, "nonCCDealLabel", 32<<30, false, dealStart, 180*builtin.EpochsInDay).IDs
+
+	replicaUpdate := miner.ReplicaUpdate{
+		SectorID:           sectorInfo.SectorNumber,
+		Deadline:           deadlineIndex,
+		Partition:          partitionIndex,
+		NewSealedSectorCID: tutil.MakeCID("replica2", &miner.SealedCIDPrefix),
+		Deals:              dealIDs,
+		UpdateProofType:    abi.RegisteredUpdateProof_StackedDrg32GiBV1,
+	}
+
+	vm.ApplyCode(t, v, worker, minerAddrs.RobustAddress, big.Zero(),
+		builtin.MethodsMiner.ProveReplicaUpdates,
+		&miner.ProveReplicaUpdatesParams{Updates: []miner.ReplicaUpdate{replicaUpdate}}, exitcode.ErrIllegalArgument)
+
+	// The sector retains its original deals and sealed CID.
+	newSectorInfo := vm.SectorInfo(t, v, minerAddrs.RobustAddress, sectorInfo.SectorNumber)
+	assert.Equal(t, sectorInfo.DealIDs, newSectorInfo.DealIDs)
+	assert.Equal(t, sectorInfo.SealedCID, newSectorInfo.SealedCID)
+}
+
 func TestTerminatedSectorFailure(t *testing.T) {
 	ctx := context.Background()
 	blkStore := ipld.NewBlockStoreInMemory()
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	init_ "github.com/filecoin-project/specs-actors/v8/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestResolvedAddresses(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10), vm.FIL), 93837778)
+	sender := addrs[0]
+	senderID, ok := v.NormalizeAddress(sender)
+	require.True(t, ok)
+
+	// Sending to an unknown public key address creates an account with the next available ID.
+	var initState init_.State
+	require.NoError(t, v.GetState(builtin.InitActorAddr, &initState))
+	expectedID, err := address.NewIDAddress(uint64(initState.NextID))
+	require.NoError(t, err)
+
+	recipient := tutil.NewSECP256K1Addr(t, "recipient")
+	vm.ApplyOk(t, v, sender, recipient, big.NewInt(1), builtin.MethodSend, nil)
+
+	resolved, err := v.ResolvedAddresses()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []vm.ResolvedAddress{
+		{Address: sender, ID: senderID},
+		{Address: recipient, ID: expectedID},
+	}, resolved)
+
+	recipientID, ok := v.NormalizeAddress(recipient)
+	require.True(t, ok)
+	assert.Equal(t, expectedID, recipientID)
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/states"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestRunEpochsThroughProvingPeriods(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+	wPoStProof, err := sealProof.RegisteredWindowPoStProof()
+	require.NoError(t, err)
+	sectorSize, err := sealProof.SectorSize()
+	require.NoError(t, err)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), builtin.TokenPrecision), 93837778)
+	owner, worker := addrs[0], addrs[0]
+	minerAddrs := createMiner(t, v, owner, worker, wPoStProof, big.Mul(big.NewInt(10_000), vm.FIL))
+
+	// advance vm so we can have seal randomness epoch in the past
+	v, err = v.WithEpoch(200)
+	require.NoError(t, err)
+
+	sectorNumber := abi.SectorNumber(100)
+	preCommitSectors(t, v, 1, 1, worker, minerAddrs.IDAddress, sealProof, sectorNumber, true, -1)
+
+	proveTime := v.GetEpoch() + miner.PreCommitChallengeDelay + 1
+	v, _ = vm.AdvanceByDeadlineTillEpoch(t, v, minerAddrs.IDAddress, proveTime)
+	v, err = v.WithEpoch(proveTime)
+	require.NoError(t, err)
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.ProveCommitSector,
+		&miner.ProveCommitSectorParams{SectorNumber: sectorNumber})
+
+	// Cron in the same epoch activates the sector and assigns it a deadline.
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+	dlIdx, pIdx := vm.SectorDeadline(t, v, minerAddrs.IDAddress, sectorNumber)
+
+	// Sweep several proving periods, proving the sector each time its deadline opens.
+	periods := 3
+	start := proveTime + 1
+	end := start + abi.ChainEpoch(periods)*miner.WPoStProvingPeriod - 1
+	posts := 0
+	v = vm.RunEpochs(t, v, start, end, func(v *vm.VM, epoch abi.ChainEpoch) {
+		dlInfo := vm.MinerDLInfo(t, v, minerAddrs.IDAddress)
+		if dlInfo.Index == dlIdx && epoch == dlInfo.Open {
+			vm.SubmitPoSt(t, v, minerAddrs.IDAddress, worker, dlInfo, pIdx)
+			posts++
+		}
+	})
+	assert.Equal(t, end, v.GetEpoch())
+
+	// The sector was proven once per proving period and never faulted.
+	assert.Equal(t, periods, posts)
+	sectorPower := miner.NewPowerPair(big.NewIntUnsigned(uint64(sectorSize)), big.NewIntUnsigned(uint64(sectorSize)))
+	assert.Equal(t, sectorPower, vm.MinerPower(t, v, minerAddrs.IDAddress))
+	dl := vm.DeadlineState(t, v, minerAddrs.IDAddress, dlIdx)
+	assert.Equal(t, uint64(0), dl.FaultyPower.Raw.Uint64())
+
+	stateTree, err := v.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v.GetEpoch())
+	require.NoError(t, err)
+	assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+}
@@ -0,0 +1,40 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestVerifregPropagatesStoreFault(t *testing.T) {
+	ctx := context.Background()
+	fs := ipld.NewFaultInjectingBlockStore(ipld.NewBlockStoreInMemory())
+	v := vm.NewVMWithSingletons(ctx, t, fs)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	verifier := addrs[0]
+
+	var st verifreg.State
+	require.NoError(t, v.GetState(builtin.VerifiedRegistryActorAddr, &st))
+	params := verifreg.AddVerifierParams{
+		Address:   verifier,
+		Allowance: abi.NewStoragePower(32 << 40),
+	}
+
+	// A failure to read the verifiers map aborts the message rather than being treated as a missing value.
+	fs.FailGet(st.Verifiers, xerrors.New("injected read failure"))
+	vm.ApplyCode(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &params, exitcode.ErrIllegalState)
+
+	// The aborted message left no trace, so the same message succeeds once the store recovers.
+	fs.Clear()
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &params)
+}
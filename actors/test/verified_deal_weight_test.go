@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	miner0 "github.com/filecoin-project/specs-actors/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/actors/states"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestVerifiedDealCountedOnce(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker, verifier, verifiedClient := addrs[0], addrs[1], addrs[2]
+
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+	wPoStProof, err := sealProof.RegisteredWindowPoStProof()
+	require.NoError(t, err)
+	sectorSize, err := sealProof.SectorSize()
+	require.NoError(t, err)
+	minerAddrs := createMiner(t, v, worker, worker, wPoStProof, big.Mul(big.NewInt(1_000), vm.FIL))
+
+	// register verifier then verified client
+	addVerifierParams := verifreg.AddVerifierParams{
+		Address:   verifier,
+		Allowance: abi.NewStoragePower(32 << 40),
+	}
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &addVerifierParams)
+	addClientParams := verifreg.AddVerifiedClientParams{
+		Address:   verifiedClient,
+		Allowance: abi.NewStoragePower(32 << 40),
+	}
+	vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifiedClient, &addClientParams)
+
+	// add market collateral for client and miner
+	vm.ApplyOk(t, v, verifiedClient, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(3), vm.FIL), builtin.MethodsMarket.AddBalance, &verifiedClient)
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(64), vm.FIL), builtin.MethodsMarket.AddBalance, &minerAddrs.IDAddress)
+
+	// publish a verified deal filling a whole sector
+	dealStart := v.GetEpoch() + miner.MaxProveCommitDuration[sealProof]
+	dealDuration := abi.ChainEpoch(180 * builtin.EpochsInDay)
+	deals := publishDeal(t, v, worker, verifiedClient, minerAddrs.IDAddress, "deal1", abi.PaddedPieceSize(sectorSize), true, dealStart, dealDuration)
+	expiration := dealStart + dealDuration
+
+	sectorNumber := abi.SectorNumber(100)
+	sectorInfo := func(sectorNumber abi.SectorNumber) miner0.SectorPreCommitInfo {
+		return miner0.SectorPreCommitInfo{
+			SealProof:     sealProof,
+			SectorNumber:  sectorNumber,
+			SealedCID:     tutil.MakeCID(fmt.Sprintf("%d", sectorNumber), &miner.SealedCIDPrefix),
+			SealRandEpoch: v.GetEpoch() - 1,
+			DealIDs:       deals.IDs,
+			Expiration:    expiration,
+		}
+	}
+
+	// The deal's weight cannot be claimed by two sectors in the same batch.
+	batchParams := miner.PreCommitSectorBatchParams{Sectors: []miner0.SectorPreCommitInfo{
+		sectorInfo(sectorNumber), sectorInfo(sectorNumber + 1),
+	}}
+	vm.ApplyCode(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.PreCommitSectorBatch, &batchParams, exitcode.ErrIllegalArgument)
+
+	// Commit the deal to a single sector.
+	batchParams = miner.PreCommitSectorBatchParams{Sectors: []miner0.SectorPreCommitInfo{sectorInfo(sectorNumber)}}
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.PreCommitSectorBatch, &batchParams)
+
+	proveTime := v.GetEpoch() + miner.PreCommitChallengeDelay + 1
+	v, _ = vm.AdvanceByDeadlineTillEpoch(t, v, minerAddrs.IDAddress, proveTime)
+	v, err = v.WithEpoch(proveTime)
+	require.NoError(t, err)
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.ProveCommitSector,
+		&miner.ProveCommitSectorParams{SectorNumber: sectorNumber})
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	// The activated deal no longer holds a DataCap ledger entry.
+	var mktState market.State
+	require.NoError(t, v.GetState(builtin.StorageMarketActorAddr, &mktState))
+	ledger, err := adt.AsMap(v.Store(), mktState.DataCapLedger, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var entry market.DataCapLedgerEntry
+	found, err := ledger.Get(abi.UIntKey(uint64(deals.IDs[0])), &entry)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// The sector's weight counts the deal's space-time exactly once, as verified.
+	verifiedWeight := big.Mul(big.NewIntUnsigned(uint64(sectorSize)), big.NewInt(int64(dealDuration)))
+	sector := vm.SectorInfo(t, v, minerAddrs.RobustAddress, sectorNumber)
+	assert.Equal(t, big.Zero(), sector.DealWeight)
+	assert.Equal(t, verifiedWeight, sector.VerifiedDealWeight)
+
+	// Once proven, the sector's power reflects that weight over the sector's lifetime.
+	dlInfo, pIdx, v := vm.AdvanceTillProvingDeadline(t, v, minerAddrs.IDAddress, sectorNumber)
+	vm.SubmitPoSt(t, v, minerAddrs.IDAddress, worker, dlInfo, pIdx)
+
+	rawPower := big.NewIntUnsigned(uint64(sectorSize))
+	qaPower := miner.QAPowerForWeight(sectorSize, expiration-proveTime, big.Zero(), verifiedWeight)
+	assert.Equal(t, miner.NewPowerPair(rawPower, qaPower), vm.MinerPower(t, v, minerAddrs.IDAddress))
+
+	// Trigger cron to keep reward accounting correct
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	stateTree, err := v.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v.GetEpoch())
+	require.NoError(t, err)
+	assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+}
@@ -0,0 +1,34 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestVerifregBelowMinimumAbortMessage(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	verifier, verifiedClient := addrs[0], addrs[1]
+	belowMinimum := big.Sub(verifreg.MinVerifiedDealSize, big.NewInt(1))
+
+	addVerifierParams := verifreg.AddVerifierParams{Address: verifier, Allowance: belowMinimum}
+	vm.ApplyAbortContainsMessage(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(),
+		builtin.MethodsVerifiedRegistry.AddVerifier, &addVerifierParams, exitcode.ErrIllegalArgument, "below MinVerifiedDealSize")
+
+	addVerifierParams.Allowance = abi.NewStoragePower(32 << 40)
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &addVerifierParams)
+
+	addClientParams := verifreg.AddVerifiedClientParams{Address: verifiedClient, Allowance: belowMinimum}
+	vm.ApplyAbortContainsMessage(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(),
+		builtin.MethodsVerifiedRegistry.AddVerifiedClient, &addClientParams, exitcode.ErrIllegalArgument, "for add verified client")
+}
@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+// Disputes are handled by miner.Actor.DisputeWindowedPoSt (miner method 24).
+func TestWindowPoStDispute(t *testing.T) {
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+	wPoStProof, err := sealProof.RegisteredWindowPoStProof()
+	require.NoError(t, err)
+
+	setup := func(t *testing.T) (*vm.VM, address.Address, address.Address, address.Address, abi.SectorNumber) {
+		ctx := context.Background()
+		v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+		addrs := vm.CreateAccounts(ctx, t, v, 2, big.Mul(big.NewInt(100_000), vm.FIL), 93837778)
+		worker := addrs[0]
+		disputer, found := v.NormalizeAddress(addrs[1])
+		require.True(t, found)
+		minerAddrs := createMiner(t, v, worker, worker, wPoStProof, big.Mul(big.NewInt(10_000), vm.FIL))
+
+		v, _, _, sectorNumber := createSector(t, v, worker, minerAddrs.IDAddress, 100, sealProof)
+		return v, worker, disputer, minerAddrs.IDAddress, sectorNumber
+	}
+
+	t.Run("invalid post is penalized and the disputer rewarded", func(t *testing.T) {
+		v, worker, disputer, minerID, sectorNumber := setup(t)
+		dlInfo, pIdx, v := vm.AdvanceTillProvingDeadline(t, v, minerID, sectorNumber)
+		vm.SubmitInvalidPoSt(t, v, minerID, worker, dlInfo, pIdx)
+		require.False(t, vm.MinerPower(t, v, minerID).IsZero())
+
+		// dispute once the challenge window has closed
+		v = advanceDisputeTestTo(t, v, minerID, dlInfo.Close)
+		disputerBefore := requireActor(t, v, disputer).Balance
+
+		disputeParams := &miner.DisputeWindowedPoStParams{
+			Deadline:  dlInfo.Index,
+			PoStIndex: 0,
+		}
+		vm.ApplyOk(t, v, disputer, minerID, big.Zero(), builtin.MethodsMiner.DisputeWindowedPoSt, disputeParams)
+
+		vm.ExpectInvocation{
+			To:     minerID,
+			Method: builtin.MethodsMiner.DisputeWindowedPoSt,
+			SubInvocations: []vm.ExpectInvocation{
+				{To: builtin.RewardActorAddr, Method: builtin.MethodsReward.ThisEpochReward},
+				{To: builtin.StoragePowerActorAddr, Method: builtin.MethodsPower.CurrentTotalPower},
+				{To: builtin.StoragePowerActorAddr, Method: builtin.MethodsPower.UpdateClaimedPower},
+				{To: disputer, Method: builtin.MethodSend, Value: vm.ExpectAttoFil(miner.BaseRewardForDisputedWindowPoSt)},
+				{To: builtin.BurntFundsActorAddr, Method: builtin.MethodSend},
+			},
+		}.Matches(t, v.LastInvocation())
+
+		assert.Equal(t, big.Add(disputerBefore, miner.BaseRewardForDisputedWindowPoSt), requireActor(t, v, disputer).Balance)
+		assert.True(t, vm.MinerPower(t, v, minerID).IsZero())
+
+		// the post was removed from the snapshot and cannot be disputed again
+		vm.ApplyCode(t, v, disputer, minerID, big.Zero(), builtin.MethodsMiner.DisputeWindowedPoSt, disputeParams, exitcode.ErrIllegalArgument)
+	})
+
+	t.Run("valid post cannot be disputed", func(t *testing.T) {
+		v, worker, disputer, minerID, sectorNumber := setup(t)
+		dlInfo, pIdx, v := vm.AdvanceTillProvingDeadline(t, v, minerID, sectorNumber)
+		vm.SubmitPoSt(t, v, minerID, worker, dlInfo, pIdx)
+
+		v = advanceDisputeTestTo(t, v, minerID, dlInfo.Close)
+		vm.ApplyCode(t, v, disputer, minerID, big.Zero(), builtin.MethodsMiner.DisputeWindowedPoSt,
+			&miner.DisputeWindowedPoStParams{Deadline: dlInfo.Index, PoStIndex: 0}, exitcode.ErrIllegalArgument)
+		assert.False(t, vm.MinerPower(t, v, minerID).IsZero())
+	})
+
+	t.Run("dispute outside the dispute window is forbidden", func(t *testing.T) {
+		v, worker, disputer, minerID, sectorNumber := setup(t)
+		dlInfo, pIdx, v := vm.AdvanceTillProvingDeadline(t, v, minerID, sectorNumber)
+		vm.SubmitInvalidPoSt(t, v, minerID, worker, dlInfo, pIdx)
+
+		// too early: the challenge window is still open
+		disputeParams := &miner.DisputeWindowedPoStParams{
+			Deadline:  dlInfo.Index,
+			PoStIndex: 0,
+		}
+		vm.ApplyCode(t, v, disputer, minerID, big.Zero(), builtin.MethodsMiner.DisputeWindowedPoSt, disputeParams, exitcode.ErrForbidden)
+
+		// too late: the dispute window has elapsed
+		v = advanceDisputeTestTo(t, v, minerID, dlInfo.Close+miner.WPoStDisputeWindow)
+		vm.ApplyCode(t, v, disputer, minerID, big.Zero(), builtin.MethodsMiner.DisputeWindowedPoSt, disputeParams, exitcode.ErrForbidden)
+		assert.False(t, vm.MinerPower(t, v, minerID).IsZero())
+	})
+}
+
+func advanceDisputeTestTo(t *testing.T, v *vm.VM, minerID address.Address, e abi.ChainEpoch) *vm.VM {
+	v, _ = vm.AdvanceByDeadlineTillEpoch(t, v, minerID, e)
+	v, err := v.WithEpoch(e)
+	require.NoError(t, err)
+	return v
+}
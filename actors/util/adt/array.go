@@ -15,8 +15,9 @@ var DefaultAmtOptions = []amt.Option{}
 
 // Array stores a sparse sequence of values in an AMT.
 type Array struct {
-	root  *amt.Root
-	store Store
+	root     *amt.Root
+	store    Store
+	bitwidth int
 }
 
 // AsArray interprets a store as an AMT-based array with root `r`.
@@ -28,8 +29,9 @@ func AsArray(s Store, r cid.Cid, bitwidth int) (*Array, error) {
 	}
 
 	return &Array{
-		root:  root,
-		store: s,
+		root:     root,
+		store:    s,
+		bitwidth: bitwidth,
 	}, nil
 }
 
@@ -41,8 +43,9 @@ func MakeEmptyArray(s Store, bitwidth int) (*Array, error) {
 		return nil, err
 	}
 	return &Array{
-		root:  root,
-		store: s,
+		root:     root,
+		store:    s,
+		bitwidth: bitwidth,
 	}, nil
 }
 
@@ -106,8 +109,23 @@ func (a *Array) BatchDelete(ix []uint64, strict bool) error {
 // Iterates all entries in the array, deserializing each value in turn into `out` and then calling a function.
 // Iteration halts if the function returns an error.
 // If the output parameter is nil, deserialization is skipped.
+// Fails without iterating if the array is taller than DefaultMaxDepth.
 func (a *Array) ForEach(out cbor.Unmarshaler, fn func(i int64) error) error {
-	return a.root.ForEach(a.store.Context(), func(k uint64, val *cbg.Deferred) error {
+	return a.ForEachBounded(out, DefaultMaxDepth, fn)
+}
+
+// Iterates the entries in the array like ForEach, beginning at the first populated index >= `start`.
+// Nodes holding only lower indices are not loaded, so iteration can be cheaply resumed from a cursor.
+func (a *Array) ForEachFrom(start uint64, out cbor.Unmarshaler, fn func(i int64) error) error {
+	if err := a.checkDepth(DefaultMaxDepth); err != nil {
+		return err
+	}
+	return a.root.ForEachAt(a.store.Context(), start, decodingCallback(out, fn))
+}
+
+// Returns an AMT iteration callback that deserializes each value into `out` (if non-nil) before calling `fn`.
+func decodingCallback(out cbor.Unmarshaler, fn func(i int64) error) func(uint64, *cbg.Deferred) error {
+	return func(k uint64, val *cbg.Deferred) error {
 		if out != nil {
 			if deferred, ok := out.(*cbg.Deferred); ok {
 				// fast-path deferred -> deferred to avoid re-decoding.
@@ -117,7 +135,149 @@ func (a *Array) ForEach(out cbor.Unmarshaler, fn func(i int64) error) error {
 			}
 		}
 		return fn(int64(k))
-	})
+	}
+}
+
+// Iterates all entries in the array like ForEach, but fails without iterating if the AMT is more
+// than `maxDepth` levels tall.
+func (a *Array) ForEachBounded(out cbor.Unmarshaler, maxDepth int, fn func(i int64) error) error {
+	if err := a.checkDepth(maxDepth); err != nil {
+		return err
+	}
+	return a.root.ForEach(a.store.Context(), decodingCallback(out, fn))
+}
+
+// Fails if the AMT is more than `maxDepth` levels tall.
+// The AMT is kept in canonical form, with the least height that addresses its highest index, so it is
+// too tall exactly when it holds an index beyond those addressable within `maxDepth` levels.
+func (a *Array) checkDepth(maxDepth int) error {
+	if maxDepth < 0 {
+		return xerrors.Errorf("negative max depth %d: %w", maxDepth, ErrMaxDepthExceeded)
+	}
+	// An AMT of height h addresses indices below 2^(bitwidth*(h+1)); beyond 64 bits, all indices are addressable.
+	if bits := a.bitwidth * (maxDepth + 1); bits < 64 {
+		limit := uint64(1) << bits
+		err := a.root.ForEachAt(a.store.Context(), limit, func(uint64, *cbg.Deferred) error {
+			return errStopIteration
+		})
+		if xerrors.Is(err, errStopIteration) {
+			return xerrors.Errorf("array holds an index beyond %d, exceeding max depth %d: %w", limit-1, maxDepth, ErrMaxDepthExceeded)
+		} else if err != nil {
+			return xerrors.Errorf("failed to check array height: %w", err)
+		}
+	}
+	return nil
+}
+
+var errStopIteration = xerrors.New("stop iteration")
+
+// Describes how densely an array's populated entries occupy its index space.
+type ArrayDensity struct {
+	// Number of populated entries.
+	Count uint64
+	// One more than the highest populated index, or zero for an empty array.
+	Span uint64
+}
+
+// Returns whether fewer than numerator/denominator of the indices within the span are populated.
+// An empty array is never below any density.
+func (d ArrayDensity) Below(numerator, denominator uint64) bool {
+	return d.Count*denominator < d.Span*numerator
+}
+
+// Reports the number of populated entries and the span of indices they occupy, so callers can decide when to Compact.
+// This iterates all entries to find the highest index, so is linear in the size of the array.
+func (a *Array) Density() (ArrayDensity, error) {
+	var d ArrayDensity
+	if err := a.root.ForEach(a.store.Context(), func(k uint64, _ *cbg.Deferred) error {
+		d.Count++
+		d.Span = k + 1
+		return nil
+	}); err != nil {
+		return ArrayDensity{}, xerrors.Errorf("failed to iterate array: %w", err)
+	}
+	return d, nil
+}
+
+// Moves the populated entries of the array, in order, to contiguous indices starting from zero.
+// Returns a mapping from each populated entry's old index to its new index, so that
+// callers can update references to the old indices.
+func (a *Array) Compact() (map[uint64]uint64, error) {
+	var indices []uint64
+	var values []cbg.Deferred
+	if err := a.root.ForEach(a.store.Context(), func(k uint64, val *cbg.Deferred) error {
+		indices = append(indices, k)
+		values = append(values, *val)
+		return nil
+	}); err != nil {
+		return nil, xerrors.Errorf("failed to iterate array: %w", err)
+	}
+	mapping := make(map[uint64]uint64, len(indices))
+	for j, k := range indices {
+		newIdx := uint64(j)
+		mapping[k] = newIdx
+		if k == newIdx {
+			continue
+		}
+		// Entries are visited in ascending order, so the new index is always below the old, and is either
+		// empty or held an entry that has already been moved.
+		if err := a.root.Set(a.store.Context(), newIdx, &values[j]); err != nil {
+			return nil, xerrors.Errorf("failed to set index %v in root %v: %w", newIdx, a.root, err)
+		}
+		if _, err := a.root.Delete(a.store.Context(), k); err != nil {
+			return nil, xerrors.Errorf("failed to delete index %v in root %v: %w", k, a.root, err)
+		}
+	}
+	return mapping, nil
+}
+
+// Removes all entries at indices >= `length`, returning the number of entries removed.
+// A length beyond the highest populated index removes nothing: an AMT is sparse, so there is nothing to
+// extend it with.
+func (a *Array) Truncate(length uint64) (uint64, error) {
+	var removed []uint64
+	if err := a.root.ForEachAt(a.store.Context(), length, func(k uint64, _ *cbg.Deferred) error {
+		removed = append(removed, k)
+		return nil
+	}); err != nil {
+		return 0, xerrors.Errorf("failed to iterate array from %d: %w", length, err)
+	}
+	if len(removed) == 0 {
+		return 0, nil
+	}
+	if err := a.BatchDelete(removed, true); err != nil {
+		return 0, err
+	}
+	return uint64(len(removed)), nil
+}
+
+// Replaces every entry in the array with the result of applying a function to its index and serialized value,
+// then flushes the array to the store once.
+// The function's results are applied only after all entries have been visited, so an error from the function
+// leaves the array unmodified.
+func (a *Array) Map(fn func(i uint64, raw []byte) ([]byte, error)) error {
+	var indices []uint64
+	var values [][]byte
+	if err := a.root.ForEach(a.store.Context(), func(k uint64, val *cbg.Deferred) error {
+		newRaw, err := fn(k, val.Raw)
+		if err != nil {
+			return err
+		}
+		indices = append(indices, k)
+		values = append(values, newRaw)
+		return nil
+	}); err != nil {
+		return xerrors.Errorf("failed to map array entries: %w", err)
+	}
+	for j, k := range indices {
+		if err := a.root.Set(a.store.Context(), k, &cbg.Deferred{Raw: values[j]}); err != nil {
+			return xerrors.Errorf("failed to set index %v in root %v: %w", k, a.root, err)
+		}
+	}
+	if _, err := a.Root(); err != nil {
+		return xerrors.Errorf("failed to flush array: %w", err)
+	}
+	return nil
 }
 
 func (a *Array) Length() uint64 {
@@ -150,3 +310,39 @@ func (a *Array) Pop(k uint64, out cbor.Unmarshaler) (bool, error) {
 	}
 	return true, nil
 }
+
+// Retrieves the value at the lowest populated index into the 'out' unmarshaler (if non-nil), and removes the entry.
+// Returns the index it occupied and a boolean indicating whether the array was non-empty.
+func (a *Array) PopFront(out cbor.Unmarshaler) (uint64, bool, error) {
+	if a.Length() == 0 {
+		return 0, false, nil
+	}
+	k, err := a.root.FirstSetIndex(a.store.Context())
+	if err != nil {
+		return 0, false, xerrors.Errorf("failed to find first index in root %v: %w", a.root, err)
+	}
+	if _, err := a.Pop(k, out); err != nil {
+		return 0, false, err
+	}
+	return k, true, nil
+}
+
+// Retrieves the value at the highest populated index into the 'out' unmarshaler (if non-nil), and removes the entry.
+// Returns the index it occupied and a boolean indicating whether the array was non-empty.
+// Finding the highest index iterates all entries, so this is linear in the size of the array.
+func (a *Array) PopBack(out cbor.Unmarshaler) (uint64, bool, error) {
+	if a.Length() == 0 {
+		return 0, false, nil
+	}
+	var k uint64
+	if err := a.root.ForEach(a.store.Context(), func(i uint64, _ *cbg.Deferred) error {
+		k = i
+		return nil
+	}); err != nil {
+		return 0, false, xerrors.Errorf("failed to find last index in root %v: %w", a.root, err)
+	}
+	if _, err := a.Pop(k, out); err != nil {
+		return 0, false, err
+	}
+	return k, true, nil
+}
@@ -1,10 +1,15 @@
 package adt_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 	"github.com/filecoin-project/specs-actors/v8/support/mock"
@@ -20,3 +25,421 @@ func TestArrayNotFound(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, found)
 }
+
+func TestArrayForEachBounded(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	arr, err := adt.MakeEmptyArray(store, 3)
+	require.NoError(t, err)
+
+	count := 0
+	countEntries := func(int64) error {
+		count++
+		return nil
+	}
+	require.NoError(t, arr.ForEachBounded(nil, 0, countEntries))
+	assert.Equal(t, 0, count)
+
+	// With a bitwidth of 3, index 512 requires a height of 3.
+	v := cbg.CborInt(1)
+	require.NoError(t, arr.Set(0, &v))
+	require.NoError(t, arr.Set(512, &v))
+
+	require.NoError(t, arr.ForEachBounded(nil, 3, countEntries))
+	assert.Equal(t, 2, count)
+
+	err = arr.ForEachBounded(nil, 2, func(int64) error {
+		t.Fatal("unexpected entry")
+		return nil
+	})
+	assert.True(t, xerrors.Is(err, adt.ErrMaxDepthExceeded))
+
+	// Deleting the high index shrinks the AMT back within bounds.
+	require.NoError(t, arr.Delete(512))
+	count = 0
+	require.NoError(t, arr.ForEachBounded(nil, 0, countEntries))
+	assert.Equal(t, 1, count)
+
+	// A bound beyond the 64-bit index space admits any array.
+	require.NoError(t, arr.ForEachBounded(nil, adt.DefaultMaxDepth, countEntries))
+}
+
+func TestArrayDensity(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	arr, err := adt.MakeEmptyArray(store, 3)
+	require.NoError(t, err)
+
+	d, err := arr.Density()
+	require.NoError(t, err)
+	assert.Equal(t, adt.ArrayDensity{Count: 0, Span: 0}, d)
+	assert.False(t, d.Below(1, 2))
+
+	for i := uint64(0); i < 10; i++ {
+		v := cbg.CborInt(i * 10)
+		require.NoError(t, arr.Set(i, &v))
+	}
+	d, err = arr.Density()
+	require.NoError(t, err)
+	assert.Equal(t, adt.ArrayDensity{Count: 10, Span: 10}, d)
+	assert.False(t, d.Below(1, 1))
+
+	// Delete all but indices 2, 5 and 7, and add a distant entry.
+	require.NoError(t, arr.BatchDelete([]uint64{0, 1, 3, 4, 6, 8, 9}, true))
+	v := cbg.CborInt(1000)
+	require.NoError(t, arr.Set(100, &v))
+	d, err = arr.Density()
+	require.NoError(t, err)
+	assert.Equal(t, adt.ArrayDensity{Count: 4, Span: 101}, d)
+	assert.True(t, d.Below(1, 10))
+	assert.False(t, d.Below(1, 50))
+}
+
+func TestArrayCompact(t *testing.T) {
+	store := func(t *testing.T) adt.Store {
+		return adt.AsStore(mock.NewBuilder(address.Undef).Build(t))
+	}
+
+	t.Run("moves entries to contiguous indices", func(t *testing.T) {
+		st := store(t)
+		arr, err := adt.MakeEmptyArray(st, 3)
+		require.NoError(t, err)
+		sparse := []uint64{0, 1, 7, 64, 1000}
+		for _, i := range sparse {
+			v := cbg.CborInt(i * 10)
+			require.NoError(t, arr.Set(i, &v))
+		}
+
+		mapping, err := arr.Compact()
+		require.NoError(t, err)
+		assert.Equal(t, map[uint64]uint64{0: 0, 1: 1, 7: 2, 64: 3, 1000: 4}, mapping)
+
+		d, err := arr.Density()
+		require.NoError(t, err)
+		assert.Equal(t, adt.ArrayDensity{Count: 5, Span: 5}, d)
+		for oldIdx, newIdx := range mapping {
+			var v cbg.CborInt
+			found, err := arr.Get(newIdx, &v)
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, cbg.CborInt(oldIdx*10), v)
+		}
+
+		// The result is the same as building the dense array afresh.
+		expected, err := adt.MakeEmptyArray(st, 3)
+		require.NoError(t, err)
+		for _, i := range sparse {
+			v := cbg.CborInt(i * 10)
+			require.NoError(t, expected.AppendContinuous(&v))
+		}
+		expectedRoot, err := expected.Root()
+		require.NoError(t, err)
+		root, err := arr.Root()
+		require.NoError(t, err)
+		assert.Equal(t, expectedRoot, root)
+	})
+
+	t.Run("dense array is unchanged", func(t *testing.T) {
+		arr, err := adt.MakeEmptyArray(store(t), 3)
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			v := cbg.CborInt(i)
+			require.NoError(t, arr.AppendContinuous(&v))
+		}
+		before, err := arr.Root()
+		require.NoError(t, err)
+
+		mapping, err := arr.Compact()
+		require.NoError(t, err)
+		assert.Equal(t, map[uint64]uint64{0: 0, 1: 1, 2: 2}, mapping)
+		after, err := arr.Root()
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+		arr, err := adt.MakeEmptyArray(store(t), 3)
+		require.NoError(t, err)
+		mapping, err := arr.Compact()
+		require.NoError(t, err)
+		assert.Empty(t, mapping)
+	})
+}
+
+func TestArrayPopFrontAndBack(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+
+	t.Run("empty", func(t *testing.T) {
+		arr, err := adt.MakeEmptyArray(store, 3)
+		require.NoError(t, err)
+
+		var v cbg.CborInt
+		_, found, err := arr.PopFront(&v)
+		require.NoError(t, err)
+		assert.False(t, found)
+		_, found, err = arr.PopBack(&v)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("single element", func(t *testing.T) {
+		for _, pop := range []func(*adt.Array, cbor.Unmarshaler) (uint64, bool, error){
+			(*adt.Array).PopFront,
+			(*adt.Array).PopBack,
+		} {
+			arr, err := adt.MakeEmptyArray(store, 3)
+			require.NoError(t, err)
+			v := cbg.CborInt(42)
+			require.NoError(t, arr.Set(17, &v))
+
+			var out cbg.CborInt
+			idx, found, err := pop(arr, &out)
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, uint64(17), idx)
+			assert.Equal(t, cbg.CborInt(42), out)
+			assert.Equal(t, uint64(0), arr.Length())
+
+			_, found, err = pop(arr, &out)
+			require.NoError(t, err)
+			assert.False(t, found)
+		}
+	})
+
+	t.Run("multiple elements", func(t *testing.T) {
+		arr, err := adt.MakeEmptyArray(store, 3)
+		require.NoError(t, err)
+		// Sparse indices spanning several levels of the tree.
+		for _, i := range []uint64{3, 9, 100, 600} {
+			v := cbg.CborInt(i * 10)
+			require.NoError(t, arr.Set(i, &v))
+		}
+
+		var out cbg.CborInt
+		idx, found, err := arr.PopFront(&out)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, uint64(3), idx)
+		assert.Equal(t, cbg.CborInt(30), out)
+
+		idx, found, err = arr.PopBack(&out)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, uint64(600), idx)
+		assert.Equal(t, cbg.CborInt(6000), out)
+
+		// Output may be omitted.
+		idx, found, err = arr.PopBack(nil)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, uint64(100), idx)
+
+		assert.Equal(t, uint64(1), arr.Length())
+		found, err = arr.Get(9, &out)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, cbg.CborInt(90), out)
+	})
+}
+
+func TestArrayTruncate(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	// Sparse indices spanning several levels of the tree.
+	indices := []uint64{0, 3, 9, 100, 600, 5000}
+	setup := func() *adt.Array {
+		arr, err := adt.MakeEmptyArray(store, 3)
+		require.NoError(t, err)
+		for _, i := range indices {
+			v := cbg.CborInt(i)
+			require.NoError(t, arr.Set(i, &v))
+		}
+		return arr
+	}
+	remaining := func(arr *adt.Array) []uint64 {
+		var found []uint64
+		var v cbg.CborInt
+		require.NoError(t, arr.ForEach(&v, func(i int64) error {
+			assert.Equal(t, cbg.CborInt(i), v)
+			found = append(found, uint64(i))
+			return nil
+		}))
+		return found
+	}
+
+	t.Run("removes indices at and above length", func(t *testing.T) {
+		arr := setup()
+		removed, err := arr.Truncate(100)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), removed)
+		assert.Equal(t, uint64(3), arr.Length())
+		assert.Equal(t, []uint64{0, 3, 9}, remaining(arr))
+
+		// Truncating to a length between populated indices.
+		removed, err = arr.Truncate(4)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), removed)
+		assert.Equal(t, []uint64{0, 3}, remaining(arr))
+	})
+
+	t.Run("truncating to zero empties the array", func(t *testing.T) {
+		arr := setup()
+		removed, err := arr.Truncate(0)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(len(indices)), removed)
+		assert.Equal(t, uint64(0), arr.Length())
+
+		empty, err := adt.MakeEmptyArray(store, 3)
+		require.NoError(t, err)
+		emptyRoot, err := empty.Root()
+		require.NoError(t, err)
+		root, err := arr.Root()
+		require.NoError(t, err)
+		assert.Equal(t, emptyRoot, root)
+	})
+
+	t.Run("length beyond the highest index removes nothing", func(t *testing.T) {
+		arr := setup()
+		before, err := arr.Root()
+		require.NoError(t, err)
+		removed, err := arr.Truncate(5001)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), removed)
+		after, err := arr.Root()
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+		assert.Equal(t, indices, remaining(arr))
+	})
+}
+
+func TestArrayForEachFrom(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	arr, err := adt.MakeEmptyArray(store, 3)
+	require.NoError(t, err)
+
+	// A sparse array spanning several levels of the AMT.
+	indices := []uint64{1, 5, 9, 1000, 5000, 70000}
+	for _, i := range indices {
+		v := cbg.CborInt(i * 10)
+		require.NoError(t, arr.Set(i, &v))
+	}
+
+	collect := func(start uint64) []uint64 {
+		var out []uint64
+		var v cbg.CborInt
+		require.NoError(t, arr.ForEachFrom(start, &v, func(i int64) error {
+			assert.Equal(t, cbg.CborInt(i*10), v)
+			out = append(out, uint64(i))
+			return nil
+		}))
+		return out
+	}
+
+	assert.Equal(t, indices, collect(0))
+	assert.Equal(t, []uint64{9, 1000, 5000, 70000}, collect(9))
+	assert.Equal(t, []uint64{1000, 5000, 70000}, collect(10))
+	assert.Equal(t, []uint64{70000}, collect(5001))
+	assert.Empty(t, collect(70001))
+
+	t.Run("resumes pagination from a cursor", func(t *testing.T) {
+		var paged []uint64
+		pageSize := 2
+		errPageFull := xerrors.New("page full")
+		cursor := uint64(0)
+		for {
+			var page []uint64
+			err := arr.ForEachFrom(cursor, nil, func(i int64) error {
+				if len(page) == pageSize {
+					return errPageFull
+				}
+				page = append(page, uint64(i))
+				return nil
+			})
+			if err != nil {
+				require.True(t, xerrors.Is(err, errPageFull))
+			}
+			if len(page) == 0 {
+				break
+			}
+			paged = append(paged, page...)
+			cursor = page[len(page)-1] + 1
+		}
+		assert.Equal(t, indices, paged)
+	})
+
+	t.Run("halts with the callback's error", func(t *testing.T) {
+		stop := xerrors.New("stop")
+		count := 0
+		err := arr.ForEachFrom(5, nil, func(int64) error {
+			count++
+			return stop
+		})
+		assert.True(t, xerrors.Is(err, stop))
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestArrayMap(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	indices := []uint64{0, 3, 9, 100, 600, 5000}
+	setup := func() *adt.Array {
+		arr, err := adt.MakeEmptyArray(store, 3)
+		require.NoError(t, err)
+		for _, i := range indices {
+			v := cbg.CborInt(i)
+			require.NoError(t, arr.Set(i, &v))
+		}
+		return arr
+	}
+	double := func(i uint64, raw []byte) ([]byte, error) {
+		var v cbg.CborInt
+		if err := v.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+			return nil, err
+		}
+		v *= 2
+		buf := new(bytes.Buffer)
+		if err := v.MarshalCBOR(buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	t.Run("transforms every element", func(t *testing.T) {
+		arr := setup()
+		require.NoError(t, arr.Map(double))
+		assert.Equal(t, uint64(len(indices)), arr.Length())
+
+		// The transformed values are visible after reloading the array from its root.
+		root, err := arr.Root()
+		require.NoError(t, err)
+		reloaded, err := adt.AsArray(store, root, 3)
+		require.NoError(t, err)
+		var found []uint64
+		var v cbg.CborInt
+		require.NoError(t, reloaded.ForEach(&v, func(i int64) error {
+			assert.Equal(t, cbg.CborInt(2*i), v)
+			found = append(found, uint64(i))
+			return nil
+		}))
+		assert.Equal(t, indices, found)
+	})
+
+	t.Run("error leaves the array unmodified", func(t *testing.T) {
+		arr := setup()
+		before, err := arr.Root()
+		require.NoError(t, err)
+		err = arr.Map(func(i uint64, raw []byte) ([]byte, error) {
+			if i == 600 {
+				return nil, xerrors.New("boom")
+			}
+			return double(i, raw)
+		})
+		require.Error(t, err)
+		after, err := arr.Root()
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
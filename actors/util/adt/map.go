@@ -3,6 +3,8 @@ package adt
 import (
 	"bytes"
 	"crypto/sha256"
+	"fmt"
+	"strings"
 
 	hamt "github.com/filecoin-project/go-hamt-ipld/v3"
 	"github.com/filecoin-project/go-state-types/abi"
@@ -21,6 +23,14 @@ var DefaultHamtOptions = []hamt.Option{
 	}),
 }
 
+// DefaultMaxDepth is the depth bound applied by the standard traversals of Map and Array, which no
+// well-formed HAMT or AMT reaches. A HAMT keyed by sha256 is at most 256/bitwidth levels deep, and an AMT at most 64.
+// ForEachBounded traverses with a different bound.
+const DefaultMaxDepth = 64
+
+// ErrMaxDepthExceeded is returned by a bounded traversal that reaches a node deeper than its limit.
+var ErrMaxDepthExceeded = xerrors.New("traversal exceeded max depth")
+
 // Map stores key-value pairs in a HAMT.
 type Map struct {
 	lastCid cid.Cid
@@ -119,14 +129,34 @@ func (m *Map) PutIfAbsent(k abi.Keyer, v cbor.Marshaler) (bool, error) {
 	}
 }
 
-// Removes the value at `k` from the hamt store, if it exists.
+// Sets key `k` to value `v`, returning the serialized value previously at `k`, if any.
+// The lookup loads the nodes on the path to `k`, so the following set does not reload them from the store.
+func (m *Map) Replace(k abi.Keyer, v cbor.Marshaler) ([]byte, bool, error) {
+	key := k.Key()
+	found, old, err := m.root.FindRaw(m.store.Context(), key)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to get key %v in node %v: %w", key, m.lastCid, err)
+	}
+	if err := m.root.Set(m.store.Context(), key, v); err != nil {
+		return nil, false, xerrors.Errorf("failed to set key %v value %v in node %v: %w", key, v, m.lastCid, err)
+	}
+	return old, found, nil
+}
+
+// Removes the value at `k` from the hamt store, if it exists, returning its serialized form.
 // Returns whether the key was previously present.
-func (m *Map) TryDelete(k abi.Keyer) (bool, error) {
-	if found, err := m.root.Delete(m.store.Context(), k.Key()); err != nil {
-		return false, xerrors.Errorf("failed to delete key %v in node %v: %v", k.Key(), m.root, err)
-	} else {
-		return found, nil
+func (m *Map) TryDelete(k abi.Keyer) ([]byte, bool, error) {
+	key := k.Key()
+	found, raw, err := m.root.FindRaw(m.store.Context(), key)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to get key %v in node %v: %w", key, m.lastCid, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	if _, err := m.root.Delete(m.store.Context(), key); err != nil {
+		return nil, false, xerrors.Errorf("failed to delete key %v in node %v: %w", key, m.lastCid, err)
 	}
+	return raw, true, nil
 }
 
 // Removes the value at `k` from the hamt store, expecting it to exist.
@@ -143,12 +173,94 @@ func (m *Map) Delete(k abi.Keyer) error {
 // calling a function with the corresponding key.
 // Iteration halts if the function returns an error.
 // If the output parameter is nil, deserialization is skipped.
+// Fails without iterating if the map is deeper than DefaultMaxDepth.
 func (m *Map) ForEach(out cbor.Unmarshaler, fn func(key string) error) error {
-	return m.root.ForEach(m.store.Context(), func(k string, val *cbg.Deferred) error {
+	return m.ForEachBounded(out, DefaultMaxDepth, fn)
+}
+
+// Iterates all entries in the map like ForEach, but fails without iterating if the map has nodes more than
+// `maxDepth` links below the root. This guards traversal of untrusted HAMTs, whose depth is not
+// otherwise bounded during iteration.
+func (m *Map) ForEachBounded(out cbor.Unmarshaler, maxDepth int, fn func(key string) error) error {
+	return m.forEachRaw(maxDepth, func(k string, val *cbg.Deferred) error {
+		if out != nil {
+			if err := out.UnmarshalCBOR(bytes.NewReader(val.Raw)); err != nil {
+				return err
+			}
+		}
+		return fn(k)
+	})
+}
+
+// A HAMT node together with its loaded children, aligned with its pointers.
+type boundedNode struct {
+	node     *hamt.Node
+	children []*boundedNode
+}
+
+// Iterates all entries in the map, calling a function with each key and serialized value, once all nodes
+// have been loaded and found to lie within `maxDepth` links of the root.
+// Each node is loaded only once. Any pending modifications are flushed to the store before loading.
+func (m *Map) forEachRaw(maxDepth int, fn func(k string, val *cbg.Deferred) error) error {
+	if err := m.root.Flush(m.store.Context()); err != nil {
+		return xerrors.Errorf("failed to flush map root: %w", err)
+	}
+	tree, err := m.loadBounded(m.root, 0, maxDepth)
+	if err != nil {
+		return err
+	}
+	return tree.forEach(fn)
+}
+
+func (m *Map) loadBounded(nd *hamt.Node, depth, maxDepth int) (*boundedNode, error) {
+	bn := &boundedNode{node: nd, children: make([]*boundedNode, len(nd.Pointers))}
+	for i, p := range nd.Pointers {
+		if !p.Link.Defined() {
+			continue
+		}
+		if depth >= maxDepth {
+			return nil, xerrors.Errorf("map %v exceeds max depth %d: %w", m.lastCid, maxDepth, ErrMaxDepthExceeded)
+		}
+		var child hamt.Node
+		if err := m.store.Get(m.store.Context(), p.Link, &child); err != nil {
+			return nil, xerrors.Errorf("failed to load map node %v: %w", p.Link, err)
+		}
+		loaded, err := m.loadBounded(&child, depth+1, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		bn.children[i] = loaded
+	}
+	return bn, nil
+}
+
+func (bn *boundedNode) forEach(fn func(k string, val *cbg.Deferred) error) error {
+	for i, p := range bn.node.Pointers {
+		if child := bn.children[i]; child != nil {
+			if err := child.forEach(fn); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, kv := range p.KVs {
+			if err := fn(string(kv.Key), kv.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Iterates the entries in the map whose keys begin with `prefix`, like ForEach.
+// Values of other entries are not deserialized. Since the HAMT places entries by the hash of
+// their key, entries sharing a prefix are not co-located and every node of the map is still visited.
+func (m *Map) ForEachWithPrefix(prefix []byte, out cbor.Unmarshaler, fn func(key string) error) error {
+	return m.forEachRaw(DefaultMaxDepth, func(k string, val *cbg.Deferred) error {
+		if !strings.HasPrefix(k, string(prefix)) {
+			return nil
+		}
 		if out != nil {
-			// Why doesn't hamt.ForEach() just return the value as bytes?
-			err := out.UnmarshalCBOR(bytes.NewReader(val.Raw))
-			if err != nil {
+			if err := out.UnmarshalCBOR(bytes.NewReader(val.Raw)); err != nil {
 				return err
 			}
 		}
@@ -165,6 +277,25 @@ func (m *Map) CollectKeys() (out []string, err error) {
 	return
 }
 
+// Collects all the entries from the map, keyed by their serialized key.
+// Each value is deserialized into a fresh object obtained from newValue, which callers may type-assert
+// to the concrete type returned. Intended for small maps, since every value is held in memory.
+func (m *Map) Entries(newValue func() cbor.Unmarshaler) (map[string]cbor.Unmarshaler, error) {
+	out := map[string]cbor.Unmarshaler{}
+	err := m.forEachRaw(DefaultMaxDepth, func(k string, val *cbg.Deferred) error {
+		v := newValue()
+		if err := v.UnmarshalCBOR(bytes.NewReader(val.Raw)); err != nil {
+			return xerrors.Errorf("failed to decode value at key %x: %w", k, err)
+		}
+		out[k] = v
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to iterate map %v: %w", m.lastCid, err)
+	}
+	return out, nil
+}
+
 // Retrieves the value for `k` into the 'out' unmarshaler (if non-nil), and removes the entry.
 // Returns a boolean indicating whether the element was previously in the map.
 func (m *Map) Pop(k abi.Keyer, out cbor.Unmarshaler) (bool, error) {
@@ -180,3 +311,182 @@ func (m *Map) Pop(k abi.Keyer, out cbor.Unmarshaler) (bool, error) {
 	}
 	return true, nil
 }
+
+// Removes all entries for which fn, called with the serialized key and value, returns true.
+// Returns the number of entries removed. If fn returns an error, no entries are removed.
+func (m *Map) DeleteIf(fn func(key, raw []byte) (bool, error)) (uint64, error) {
+	var matched []string
+	err := m.forEachRaw(DefaultMaxDepth, func(k string, val *cbg.Deferred) error {
+		remove, err := fn([]byte(k), val.Raw)
+		if err != nil {
+			return err
+		}
+		if remove {
+			matched = append(matched, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("failed to iterate map %v: %w", m.lastCid, err)
+	}
+	for _, k := range matched {
+		if err := m.Delete(rawKey(k)); err != nil {
+			return 0, err
+		}
+	}
+	return uint64(len(matched)), nil
+}
+
+// MergeMap combines the maps with roots `rootA` and `rootB` into a new map, returning its root.
+// Keys present in only one map keep their value. For keys present in both, onConflict is called with the key
+// and both serialized values, and returns the serialized value to store, or an error to abort the merge.
+// Both maps must have the same bitwidth. The input maps are not modified.
+func MergeMap(s Store, rootA, rootB cid.Cid, bitwidth int, onConflict func(key, a, b []byte) ([]byte, error)) (cid.Cid, error) {
+	merged, err := AsMap(s, rootA, bitwidth)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to load map %v: %w", rootA, err)
+	}
+	other, err := AsMap(s, rootB, bitwidth)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to load map %v: %w", rootB, err)
+	}
+
+	var valB cbg.Deferred
+	err = other.ForEach(&valB, func(key string) error {
+		k := rawKey(key)
+		var valA cbg.Deferred
+		found, err := merged.Get(k, &valA)
+		if err != nil {
+			return err
+		}
+		value := valB.Raw
+		if found {
+			if value, err = onConflict([]byte(key), valA.Raw, valB.Raw); err != nil {
+				return xerrors.Errorf("failed to resolve conflict at key %x: %w", key, err)
+			}
+		}
+		return merged.Put(k, &cbg.Deferred{Raw: value})
+	})
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to merge map %v into %v: %w", rootB, rootA, err)
+	}
+	return merged.Root()
+}
+
+// ErrMalformedMap is returned by ValidateMap for a HAMT that violates a structural invariant.
+var ErrMalformedMap = xerrors.New("malformed map")
+
+// The maximum number of entries in a HAMT bucket before it is split into a child node.
+const hamtBucketSize = 3
+
+// ValidateMap walks every node of the HAMT-based map with root `root`, checking the structural invariants
+// that the HAMT implementation maintains, and returns a descriptive error for the first violation found.
+// Nodes must have a bitfield consistent with their pointers, each pointer must be either a link or a bucket
+// of sorted keys no larger than the bucket size, every key must be placed at the position given by its hash,
+// and nodes other than the root must be non-empty and not collapsible into their parent.
+// Failure to load a node is also reported. Errors describing violations wrap ErrMalformedMap.
+func ValidateMap(s Store, root cid.Cid, bitwidth int) error {
+	if bitwidth < 1 || bitwidth > 8 {
+		return xerrors.Errorf("invalid bitwidth %d", bitwidth)
+	}
+	return validateMapNode(s, root, nil, bitwidth)
+}
+
+// Validates the node with CID `c`, reached by following the pointers at positions `path` from the root.
+func validateMapNode(s Store, c cid.Cid, path []int, bitwidth int) error {
+	var nd hamt.Node
+	if err := s.Get(s.Context(), c, &nd); err != nil {
+		return xerrors.Errorf("failed to load map node %v at path %v: %w", c, path, err)
+	}
+	malformed := func(format string, args ...interface{}) error {
+		return xerrors.Errorf("node %v at path %v: %s: %w", c, path, fmt.Sprintf(format, args...), ErrMalformedMap)
+	}
+
+	width := 1 << uint(bitwidth)
+	if nd.Bitfield == nil {
+		return malformed("missing bitfield")
+	}
+	if nd.Bitfield.Sign() < 0 || nd.Bitfield.BitLen() > width {
+		return malformed("bitfield %x has bits beyond width %d", nd.Bitfield, width)
+	}
+	var positions []int
+	for i := 0; i < width; i++ {
+		if nd.Bitfield.Bit(i) == 1 {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) != len(nd.Pointers) {
+		return malformed("bitfield has %d bits set but node has %d pointers", len(positions), len(nd.Pointers))
+	}
+	if len(path) > 0 && len(nd.Pointers) == 0 {
+		return malformed("non-root node is empty")
+	}
+
+	links, kvs := 0, 0
+	for i, p := range nd.Pointers {
+		isLink, isBucket := p.Link.Defined(), p.KVs != nil
+		if isLink == isBucket {
+			return malformed("pointer %d must be exactly one of a link or a bucket", i)
+		}
+		if isLink {
+			if p.Link.Type() != cid.DagCBOR {
+				return malformed("pointer %d links to non-CBOR block %v", i, p.Link)
+			}
+			links++
+			continue
+		}
+		if len(p.KVs) == 0 || len(p.KVs) > hamtBucketSize {
+			return malformed("pointer %d has bucket of %d entries, expected 1 to %d", i, len(p.KVs), hamtBucketSize)
+		}
+		for j, kv := range p.KVs {
+			if j > 0 && bytes.Compare(p.KVs[j-1].Key, kv.Key) >= 0 {
+				return malformed("pointer %d bucket keys %x and %x are out of order", i, p.KVs[j-1].Key, kv.Key)
+			}
+			// Keys are hashed by sha256, as configured by DefaultHamtOptions.
+			hash := sha256.Sum256(kv.Key)
+			for depth := 0; depth <= len(path); depth++ {
+				pos := positions[i]
+				if depth < len(path) {
+					pos = path[depth]
+				}
+				if idx := hashIndex(hash[:], depth, bitwidth); idx != pos {
+					return malformed("key %x belongs at position %d at depth %d, found at %d", kv.Key, idx, depth, pos)
+				}
+			}
+		}
+		kvs += len(p.KVs)
+	}
+	if len(path) > 0 && links == 0 && kvs <= hamtBucketSize {
+		return malformed("non-root node with %d entries should be collapsed into its parent", kvs)
+	}
+
+	for i, p := range nd.Pointers {
+		if !p.Link.Defined() {
+			continue
+		}
+		if (len(path)+2)*bitwidth > 8*sha256.Size {
+			return malformed("pointer %d links beyond the depth addressable by the hash", i)
+		}
+		childPath := append(append([]int{}, path...), positions[i])
+		if err := validateMapNode(s, p.Link, childPath, bitwidth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the `bitwidth` bits of `hash` indexing a node at `depth`, consuming bits from the most significant end.
+func hashIndex(hash []byte, depth, bitwidth int) int {
+	idx := 0
+	for b := depth * bitwidth; b < (depth+1)*bitwidth; b++ {
+		idx = idx<<1 | int(hash[b/8]>>(7-uint(b%8))&1)
+	}
+	return idx
+}
+
+// A key given directly by its serialized form, as passed to ForEach callbacks.
+type rawKey string
+
+func (k rawKey) Key() string {
+	return string(k)
+}
@@ -0,0 +1,531 @@
+package adt_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	hamt "github.com/filecoin-project/go-hamt-ipld/v3"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
+)
+
+func TestMapForEachBounded(t *testing.T) {
+	t.Run("iterates all entries of a well-formed map", func(t *testing.T) {
+		rt := mock.NewBuilder(address.Undef).Build(t)
+		store := adt.AsStore(rt)
+		m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		for i := uint64(0); i < 1000; i++ {
+			v := cbg.CborInt(i)
+			require.NoError(t, m.Put(abi.UIntKey(i), &v))
+		}
+
+		count := 0
+		var v cbg.CborInt
+		require.NoError(t, m.ForEachBounded(&v, adt.DefaultMaxDepth, func(key string) error {
+			k, err := abi.ParseUIntKey(key)
+			require.NoError(t, err)
+			assert.Equal(t, k, uint64(v))
+			count++
+			return nil
+		}))
+		assert.Equal(t, 1000, count)
+
+		// A limit of zero refuses to follow any link from the root.
+		err = m.ForEachBounded(nil, 0, func(string) error { return nil })
+		assert.True(t, xerrors.Is(err, adt.ErrMaxDepthExceeded))
+	})
+
+	t.Run("fails on a pathologically deep map before visiting any entry", func(t *testing.T) {
+		rt := mock.NewBuilder(address.Undef).Build(t)
+		store := adt.AsStore(rt)
+
+		// Construct a chain of nodes each holding an entry and linking only to the next, far deeper
+		// than any hash can index.
+		value := cbg.CborInt(1)
+		raw, err := valueBytes(&value)
+		require.NoError(t, err)
+		entry := func(i int) *hamt.Pointer {
+			return &hamt.Pointer{KVs: []*hamt.KV{{Key: []byte(fmt.Sprintf("k%d", i)), Value: &cbg.Deferred{Raw: raw}}}}
+		}
+		node := &hamt.Node{
+			Bitfield: big.NewInt(1),
+			Pointers: []*hamt.Pointer{entry(0)},
+		}
+		for i := 1; i <= 2*adt.DefaultMaxDepth; i++ {
+			c, err := store.Put(store.Context(), node)
+			require.NoError(t, err)
+			node = &hamt.Node{
+				Bitfield: big.NewInt(3),
+				Pointers: []*hamt.Pointer{entry(i), {Link: c}},
+			}
+		}
+		root, err := store.Put(store.Context(), node)
+		require.NoError(t, err)
+
+		m, err := adt.AsMap(store, root, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		unexpected := func(string) error {
+			t.Fatal("unexpected entry")
+			return nil
+		}
+		err = m.ForEachBounded(nil, adt.DefaultMaxDepth, unexpected)
+		assert.True(t, xerrors.Is(err, adt.ErrMaxDepthExceeded))
+
+		// The standard traversals apply the default bound.
+		err = m.ForEach(nil, unexpected)
+		assert.True(t, xerrors.Is(err, adt.ErrMaxDepthExceeded))
+		_, err = m.CollectKeys()
+		assert.True(t, xerrors.Is(err, adt.ErrMaxDepthExceeded))
+
+		// The entries are reachable given a sufficient limit.
+		count := 0
+		require.NoError(t, m.ForEachBounded(nil, 2*adt.DefaultMaxDepth, func(string) error {
+			count++
+			return nil
+		}))
+		assert.Equal(t, 2*adt.DefaultMaxDepth+1, count)
+	})
+}
+
+func valueBytes(v cbg.CBORMarshaler) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := v.MarshalCBOR(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func TestMergeMap(t *testing.T) {
+	mapWith := func(t *testing.T, store adt.Store, entries map[uint64]int64) cid.Cid {
+		m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		for k, v := range entries {
+			val := cbg.CborInt(v)
+			require.NoError(t, m.Put(abi.UIntKey(k), &val))
+		}
+		root, err := m.Root()
+		require.NoError(t, err)
+		return root
+	}
+	assertEntries := func(t *testing.T, store adt.Store, root cid.Cid, expected map[uint64]int64) {
+		m, err := adt.AsMap(store, root, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		actual := map[uint64]int64{}
+		var v cbg.CborInt
+		require.NoError(t, m.ForEach(&v, func(key string) error {
+			k, err := abi.ParseUIntKey(key)
+			require.NoError(t, err)
+			actual[k] = int64(v)
+			return nil
+		}))
+		assert.Equal(t, expected, actual)
+	}
+	failOnConflict := func(key, a, b []byte) ([]byte, error) {
+		return nil, xerrors.Errorf("conflict")
+	}
+
+	t.Run("disjoint maps", func(t *testing.T) {
+		rt := mock.NewBuilder(address.Undef).Build(t)
+		store := adt.AsStore(rt)
+		rootA := mapWith(t, store, map[uint64]int64{1: 10, 2: 20})
+		rootB := mapWith(t, store, map[uint64]int64{3: 30, 4: 40})
+
+		merged, err := adt.MergeMap(store, rootA, rootB, builtin.DefaultHamtBitwidth, failOnConflict)
+		require.NoError(t, err)
+		assertEntries(t, store, merged, map[uint64]int64{1: 10, 2: 20, 3: 30, 4: 40})
+
+		// Inputs are unchanged.
+		assertEntries(t, store, rootA, map[uint64]int64{1: 10, 2: 20})
+		assertEntries(t, store, rootB, map[uint64]int64{3: 30, 4: 40})
+	})
+
+	t.Run("conflicts are resolved", func(t *testing.T) {
+		rt := mock.NewBuilder(address.Undef).Build(t)
+		store := adt.AsStore(rt)
+		rootA := mapWith(t, store, map[uint64]int64{1: 10, 2: 20})
+		rootB := mapWith(t, store, map[uint64]int64{2: 5, 3: 30})
+
+		var conflicts []uint64
+		sum := func(key, a, b []byte) ([]byte, error) {
+			k, err := abi.ParseUIntKey(string(key))
+			require.NoError(t, err)
+			conflicts = append(conflicts, k)
+
+			var va, vb cbg.CborInt
+			require.NoError(t, va.UnmarshalCBOR(bytes.NewReader(a)))
+			require.NoError(t, vb.UnmarshalCBOR(bytes.NewReader(b)))
+			total := va + vb
+			return valueBytes(&total)
+		}
+
+		merged, err := adt.MergeMap(store, rootA, rootB, builtin.DefaultHamtBitwidth, sum)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{2}, conflicts)
+		assertEntries(t, store, merged, map[uint64]int64{1: 10, 2: 25, 3: 30})
+	})
+
+	t.Run("conflict resolution error aborts merge", func(t *testing.T) {
+		rt := mock.NewBuilder(address.Undef).Build(t)
+		store := adt.AsStore(rt)
+		rootA := mapWith(t, store, map[uint64]int64{1: 10})
+		rootB := mapWith(t, store, map[uint64]int64{1: 11})
+
+		_, err := adt.MergeMap(store, rootA, rootB, builtin.DefaultHamtBitwidth, failOnConflict)
+		assert.Error(t, err)
+	})
+}
+
+func TestMapForEachWithPrefix(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+
+	// Keys are namespaced by concatenating two addresses.
+	ns1, ns2, ns3 := tutil.NewIDAddr(t, 100), tutil.NewIDAddr(t, 200), tutil.NewIDAddr(t, 300)
+	for i := uint64(1); i <= 20; i++ {
+		for j, ns := range []address.Address{ns1, ns2} {
+			v := cbg.CborInt(int64(j)*1000 + int64(i))
+			key := concatKey(append(ns.Bytes(), tutil.NewIDAddr(t, 1000+i).Bytes()...))
+			require.NoError(t, m.Put(key, &v))
+		}
+	}
+
+	collect := func(prefix []byte) map[string]int64 {
+		out := map[string]int64{}
+		var v cbg.CborInt
+		require.NoError(t, m.ForEachWithPrefix(prefix, &v, func(key string) error {
+			out[key] = int64(v)
+			return nil
+		}))
+		return out
+	}
+
+	first := collect(ns1.Bytes())
+	assert.Len(t, first, 20)
+	for k, v := range first {
+		assert.True(t, bytes.HasPrefix([]byte(k), ns1.Bytes()))
+		assert.Less(t, v, int64(1000))
+	}
+	second := collect(ns2.Bytes())
+	assert.Len(t, second, 20)
+	for _, v := range second {
+		assert.Greater(t, v, int64(1000))
+	}
+	assert.Empty(t, collect(ns3.Bytes()))
+	assert.Len(t, collect(nil), 40)
+
+	// Iteration halts with the callback's error.
+	stop := xerrors.New("stop")
+	count := 0
+	err = m.ForEachWithPrefix(ns1.Bytes(), nil, func(string) error {
+		count++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestMapEntries(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+
+	st, err := verifreg.ConstructState(store, tutil.NewIDAddr(t, 80))
+	require.NoError(t, err)
+	verifiers, err := adt.AsMap(store, st.Verifiers, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+
+	empty, err := verifiers.Entries(func() cbor.Unmarshaler { return new(verifreg.DataCap) })
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	expected := map[address.Address]verifreg.DataCap{}
+	for i := uint64(0); i < 30; i++ {
+		verifier := tutil.NewIDAddr(t, 100+i)
+		dcap := abi.NewStoragePower(int64(1 << 20 * (i + 1)))
+		require.NoError(t, verifiers.Put(abi.AddrKey(verifier), &dcap))
+		expected[verifier] = dcap
+	}
+
+	entries, err := verifiers.Entries(func() cbor.Unmarshaler { return new(verifreg.DataCap) })
+	require.NoError(t, err)
+	require.Len(t, entries, len(expected))
+	for key, value := range entries {
+		verifier, err := address.NewFromBytes([]byte(key))
+		require.NoError(t, err)
+		assert.Equal(t, expected[verifier], *value.(*verifreg.DataCap))
+	}
+
+	// A value that does not decode fails the whole collection.
+	_, err = verifiers.Entries(func() cbor.Unmarshaler { return new(verifreg.RemoveDataCapRequest) })
+	assert.Error(t, err)
+}
+
+func TestMapReplace(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+
+	t.Run("absent key", func(t *testing.T) {
+		value := cbg.CborInt(1)
+		old, found, err := m.Replace(abi.UIntKey(1), &value)
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, old)
+
+		var out cbg.CborInt
+		found, err = m.Get(abi.UIntKey(1), &out)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, value, out)
+	})
+
+	t.Run("present key", func(t *testing.T) {
+		value := cbg.CborInt(2)
+		old, found, err := m.Replace(abi.UIntKey(1), &value)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		var prev cbg.CborInt
+		require.NoError(t, prev.UnmarshalCBOR(bytes.NewReader(old)))
+		assert.Equal(t, cbg.CborInt(1), prev)
+
+		var out cbg.CborInt
+		found, err = m.Get(abi.UIntKey(1), &out)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, value, out)
+
+		// The replaced value survives a flush and reload.
+		root, err := m.Root()
+		require.NoError(t, err)
+		reloaded, err := adt.AsMap(store, root, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		found, err = reloaded.Get(abi.UIntKey(1), &out)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, value, out)
+	})
+}
+
+type concatKey []byte
+
+func (k concatKey) Key() string {
+	return string(k)
+}
+
+func TestMapTryDelete(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	value := cbg.CborInt(7)
+	require.NoError(t, m.Put(abi.UIntKey(1), &value))
+
+	t.Run("absent key", func(t *testing.T) {
+		raw, found, err := m.TryDelete(abi.UIntKey(2))
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, raw)
+
+		has, err := m.Has(abi.UIntKey(1))
+		require.NoError(t, err)
+		assert.True(t, has)
+	})
+
+	t.Run("present key", func(t *testing.T) {
+		raw, found, err := m.TryDelete(abi.UIntKey(1))
+		require.NoError(t, err)
+		require.True(t, found)
+
+		var deleted cbg.CborInt
+		require.NoError(t, deleted.UnmarshalCBOR(bytes.NewReader(raw)))
+		assert.Equal(t, value, deleted)
+
+		has, err := m.Has(abi.UIntKey(1))
+		require.NoError(t, err)
+		assert.False(t, has)
+
+		// Deleting again finds nothing.
+		raw, found, err = m.TryDelete(abi.UIntKey(1))
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, raw)
+	})
+}
+
+func TestMapDeleteIf(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+
+	build := func() *adt.Map {
+		m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		for i := uint64(0); i < 100; i++ {
+			v := cbg.CborInt(i)
+			require.NoError(t, m.Put(abi.UIntKey(i), &v))
+		}
+		return m
+	}
+	evenValue := func(_, raw []byte) (bool, error) {
+		var v cbg.CborInt
+		if err := v.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+			return false, err
+		}
+		return v%2 == 0, nil
+	}
+
+	t.Run("deletes a subset", func(t *testing.T) {
+		m := build()
+		count, err := m.DeleteIf(evenValue)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(50), count)
+
+		// Only odd values remain, including after a flush and reload.
+		root, err := m.Root()
+		require.NoError(t, err)
+		reloaded, err := adt.AsMap(store, root, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		var v cbg.CborInt
+		remaining := 0
+		require.NoError(t, reloaded.ForEach(&v, func(string) error {
+			assert.Equal(t, cbg.CborInt(1), v%2)
+			remaining++
+			return nil
+		}))
+		assert.Equal(t, 50, remaining)
+	})
+
+	t.Run("deletes nothing", func(t *testing.T) {
+		m := build()
+		before, err := m.Root()
+		require.NoError(t, err)
+
+		count, err := m.DeleteIf(func(_, _ []byte) (bool, error) { return false, nil })
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), count)
+
+		after, err := m.Root()
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("predicate error aborts without deleting", func(t *testing.T) {
+		m := build()
+		before, err := m.Root()
+		require.NoError(t, err)
+
+		stop := xerrors.New("stop")
+		_, err = m.DeleteIf(func(_, _ []byte) (bool, error) { return true, stop })
+		assert.True(t, xerrors.Is(err, stop))
+
+		after, err := m.Root()
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
+
+func TestValidateMap(t *testing.T) {
+	// A plain block store, so a missing block is reported as an error rather than aborting.
+	store := adt.WrapBlockStore(context.Background(), ipld.NewBlockStoreInMemory())
+	mapWith := func(t *testing.T, count uint64) cid.Cid {
+		m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		for i := uint64(0); i < count; i++ {
+			v := cbg.CborInt(i)
+			require.NoError(t, m.Put(abi.UIntKey(i), &v))
+		}
+		root, err := m.Root()
+		require.NoError(t, err)
+		return root
+	}
+	// Stores a modified copy of the root node of the map at `root`.
+	tamper := func(t *testing.T, root cid.Cid, modify func(nd *hamt.Node)) cid.Cid {
+		var nd hamt.Node
+		require.NoError(t, store.Get(store.Context(), root, &nd))
+		modify(&nd)
+		c, err := store.Put(store.Context(), &nd)
+		require.NoError(t, err)
+		return c
+	}
+	large := mapWith(t, 1000)
+	small := mapWith(t, 20)
+
+	t.Run("well-formed maps are valid", func(t *testing.T) {
+		assert.NoError(t, adt.ValidateMap(store, mapWith(t, 0), builtin.DefaultHamtBitwidth))
+		assert.NoError(t, adt.ValidateMap(store, small, builtin.DefaultHamtBitwidth))
+		assert.NoError(t, adt.ValidateMap(store, large, builtin.DefaultHamtBitwidth))
+	})
+
+	t.Run("bitfield inconsistent with pointers", func(t *testing.T) {
+		root := tamper(t, large, func(nd *hamt.Node) {
+			require.Greater(t, len(nd.Pointers), 1)
+			nd.Bitfield = big.NewInt(1)
+		})
+		err := adt.ValidateMap(store, root, builtin.DefaultHamtBitwidth)
+		assert.True(t, xerrors.Is(err, adt.ErrMalformedMap))
+		assert.Contains(t, err.Error(), "bitfield has 1 bits set")
+	})
+
+	t.Run("bitfield wider than the node", func(t *testing.T) {
+		root := tamper(t, large, func(nd *hamt.Node) {
+			nd.Bitfield = new(big.Int).SetBit(nd.Bitfield, 1<<builtin.DefaultHamtBitwidth, 1)
+		})
+		err := adt.ValidateMap(store, root, builtin.DefaultHamtBitwidth)
+		assert.True(t, xerrors.Is(err, adt.ErrMalformedMap))
+		assert.Contains(t, err.Error(), "beyond width")
+	})
+
+	t.Run("bucket keys out of order", func(t *testing.T) {
+		root := tamper(t, small, func(nd *hamt.Node) {
+			for _, p := range nd.Pointers {
+				if len(p.KVs) > 1 {
+					p.KVs[0], p.KVs[1] = p.KVs[1], p.KVs[0]
+					return
+				}
+			}
+			t.Fatal("no bucket with multiple entries")
+		})
+		err := adt.ValidateMap(store, root, builtin.DefaultHamtBitwidth)
+		assert.True(t, xerrors.Is(err, adt.ErrMalformedMap))
+		assert.Contains(t, err.Error(), "out of order")
+	})
+
+	t.Run("entries at the wrong position", func(t *testing.T) {
+		// Swapping two links of the root moves every entry below them away from its hashed position.
+		root := tamper(t, large, func(nd *hamt.Node) {
+			nd.Pointers[0], nd.Pointers[1] = nd.Pointers[1], nd.Pointers[0]
+		})
+		err := adt.ValidateMap(store, root, builtin.DefaultHamtBitwidth)
+		assert.True(t, xerrors.Is(err, adt.ErrMalformedMap))
+		assert.Contains(t, err.Error(), "belongs at position")
+	})
+
+	t.Run("missing child node", func(t *testing.T) {
+		root := tamper(t, large, func(nd *hamt.Node) {
+			require.True(t, nd.Pointers[0].Link.Defined())
+			nd.Pointers[0].Link = tutil.MakeCID("missing", nil)
+		})
+		err := adt.ValidateMap(store, root, builtin.DefaultHamtBitwidth)
+		assert.Error(t, err)
+		assert.False(t, xerrors.Is(err, adt.ErrMalformedMap))
+	})
+}
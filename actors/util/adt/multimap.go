@@ -13,8 +13,13 @@ import (
 type Multimap struct {
 	mp            *Map
 	innerBitwidth int
+	// The maximum number of values per key, or zero for no limit.
+	maxValuesPerKey uint64
 }
 
+// ErrMaxValuesPerKeyExceeded is returned by Multimap.Add when a key already holds the maximum number of values.
+var ErrMaxValuesPerKeyExceeded = xerrors.New("multimap key holds maximum number of values")
+
 // Interprets a store as a HAMT-based map of AMTs with root `r`.
 // The outer map is interpreted with a branching factor of 2^bitwidth.
 func AsMultimap(s Store, r cid.Cid, outerBitwidth, innerBitwidth int) (*Multimap, error) {
@@ -23,7 +28,7 @@ func AsMultimap(s Store, r cid.Cid, outerBitwidth, innerBitwidth int) (*Multimap
 		return nil, err
 	}
 
-	return &Multimap{m, innerBitwidth}, nil
+	return &Multimap{mp: m, innerBitwidth: innerBitwidth}, nil
 }
 
 // Creates a new map backed by an empty HAMT and flushes it to the store.
@@ -33,7 +38,7 @@ func MakeEmptyMultimap(s Store, outerBitwidth, innerBitwidth int) (*Multimap, er
 	if err != nil {
 		return nil, err
 	}
-	return &Multimap{m, innerBitwidth}, nil
+	return &Multimap{mp: m, innerBitwidth: innerBitwidth}, nil
 }
 
 // Creates and stores a new empty multimap, returning its CID.
@@ -50,7 +55,15 @@ func (mm *Multimap) Root() (cid.Cid, error) {
 	return mm.mp.Root()
 }
 
+// Limits the number of values subsequently added under each key, so that callers may shard values
+// across keys instead. A limit of zero removes the limit. Keys already holding more values are not affected
+// until values are added to them.
+func (mm *Multimap) SetMaxValuesPerKey(max uint64) {
+	mm.maxValuesPerKey = max
+}
+
 // Adds a value for a key.
+// Fails with ErrMaxValuesPerKeyExceeded if the key already holds the maximum number of values.
 func (mm *Multimap) Add(key abi.Keyer, value cbor.Marshaler) error {
 	// Load the array under key, or initialize a new empty one if not found.
 	array, found, err := mm.Get(key)
@@ -63,6 +76,10 @@ func (mm *Multimap) Add(key abi.Keyer, value cbor.Marshaler) error {
 			return err
 		}
 	}
+	if mm.maxValuesPerKey > 0 && array.Length() >= mm.maxValuesPerKey {
+		return xerrors.Errorf("failed to add multimap key %v value %v: limit %d: %w", key, value, mm.maxValuesPerKey,
+			ErrMaxValuesPerKeyExceeded)
+	}
 
 	// Append to the array.
 	if err = array.AppendContinuous(value); err != nil {
@@ -85,7 +102,7 @@ func (mm *Multimap) Add(key abi.Keyer, value cbor.Marshaler) error {
 
 // Removes all values for a key.
 func (mm *Multimap) RemoveAll(key abi.Keyer) error {
-	if _, err := mm.mp.TryDelete(key); err != nil {
+	if _, _, err := mm.mp.TryDelete(key); err != nil {
 		return xerrors.Errorf("failed to delete multimap key %v root %v: %w", key, mm.mp.root, err)
 	}
 	return nil
@@ -122,6 +139,18 @@ func (mm *Multimap) ForAll(fn func(k string, arr *Array) error) error {
 	return nil
 }
 
+// Iterates every value under every key, passing each value's raw serialized form to a function.
+// Keys are visited in the underlying HAMT's iteration order and values in the order they were inserted,
+// so the order is deterministic for a given root.
+// Iteration halts if the function returns an error.
+func (mm *Multimap) ForEachAll(fn func(key string, i uint64, raw []byte) error) error {
+	return mm.ForAll(func(k string, arr *Array) error {
+		return arr.root.ForEach(mm.mp.store.Context(), func(i uint64, val *cbg.Deferred) error {
+			return fn(k, i, val.Raw)
+		})
+	})
+}
+
 func (mm *Multimap) Get(key abi.Keyer) (*Array, bool, error) {
 	var arrayRoot cbg.CborCid
 	found, err := mm.mp.Get(key, &arrayRoot)
@@ -0,0 +1,113 @@
+package adt_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/mock"
+)
+
+func TestMultimapForEachAll(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	mm, err := adt.MakeEmptyMultimap(store, builtin.DefaultHamtBitwidth, 3)
+	require.NoError(t, err)
+
+	// Key k holds the values k*100, k*100+1, ... for k+1 values.
+	expected := map[uint64][]uint64{}
+	for k := uint64(0); k < 20; k++ {
+		for j := uint64(0); j <= k; j++ {
+			v := cbg.CborInt(k*100 + j)
+			require.NoError(t, mm.Add(abi.UIntKey(k), &v))
+			expected[k] = append(expected[k], k*100+j)
+		}
+	}
+
+	visit := func(mm *adt.Multimap) ([]string, map[uint64][]uint64) {
+		var order []string
+		found := map[uint64][]uint64{}
+		require.NoError(t, mm.ForEachAll(func(key string, i uint64, raw []byte) error {
+			k, err := abi.ParseUIntKey(key)
+			require.NoError(t, err)
+			var v cbg.CborInt
+			require.NoError(t, v.UnmarshalCBOR(bytes.NewReader(raw)))
+			assert.Equal(t, uint64(len(found[k])), i)
+			found[k] = append(found[k], uint64(v))
+			order = append(order, key)
+			return nil
+		}))
+		return order, found
+	}
+
+	t.Run("visits every value in insertion order per key", func(t *testing.T) {
+		_, found := visit(mm)
+		assert.Equal(t, expected, found)
+	})
+
+	t.Run("order is deterministic for a root", func(t *testing.T) {
+		root, err := mm.Root()
+		require.NoError(t, err)
+		reloaded, err := adt.AsMultimap(store, root, builtin.DefaultHamtBitwidth, 3)
+		require.NoError(t, err)
+
+		first, _ := visit(mm)
+		second, _ := visit(reloaded)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("halts on error", func(t *testing.T) {
+		stop := xerrors.New("stop")
+		count := 0
+		err := mm.ForEachAll(func(string, uint64, []byte) error {
+			count++
+			if count == 5 {
+				return stop
+			}
+			return nil
+		})
+		assert.True(t, xerrors.Is(err, stop))
+		assert.Equal(t, 5, count)
+	})
+}
+
+func TestMultimapMaxValuesPerKey(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	mm, err := adt.MakeEmptyMultimap(store, builtin.DefaultHamtBitwidth, 3)
+	require.NoError(t, err)
+	mm.SetMaxValuesPerKey(3)
+
+	for i := int64(0); i < 3; i++ {
+		v := cbg.CborInt(i)
+		require.NoError(t, mm.Add(abi.UIntKey(1), &v))
+	}
+
+	// Adding beyond the limit fails, leaving the key's values unchanged.
+	v := cbg.CborInt(3)
+	err = mm.Add(abi.UIntKey(1), &v)
+	require.Error(t, err)
+	assert.True(t, xerrors.Is(err, adt.ErrMaxValuesPerKeyExceeded))
+	arr, found, err := mm.Get(abi.UIntKey(1))
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, uint64(3), arr.Length())
+
+	// The caller may shard the value to another key.
+	require.NoError(t, mm.Add(abi.UIntKey(2), &v))
+
+	// Removing the limit allows further values.
+	mm.SetMaxValuesPerKey(0)
+	require.NoError(t, mm.Add(abi.UIntKey(1), &v))
+	arr, _, err = mm.Get(abi.UIntKey(1))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), arr.Length())
+}
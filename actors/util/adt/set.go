@@ -51,7 +51,8 @@ func (h *Set) Has(k abi.Keyer) (bool, error) {
 // Removes `k` from the set, if present.
 // Returns whether the key was previously present.
 func (h *Set) TryDelete(k abi.Keyer) (bool, error) {
-	return h.m.TryDelete(k)
+	_, found, err := h.m.TryDelete(k)
+	return found, err
 }
 
 // Removes `k` from the set, expecting it to be present.
@@ -59,6 +60,48 @@ func (h *Set) Delete(k abi.Keyer) error {
 	return h.m.Delete(k)
 }
 
+// PutMany adds all of `keys` to the set.
+// The batch is applied in memory and written to the store on the next call to Root.
+func (h *Set) PutMany(keys []abi.Keyer) error {
+	for _, k := range keys {
+		if err := h.m.Put(k, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMany removes all of `keys` from the set, ignoring any that are not present.
+// Returns the number of keys that were previously present.
+// The batch is applied in memory and written to the store on the next call to Root.
+func (h *Set) DeleteMany(keys []abi.Keyer) (uint64, error) {
+	var deleted uint64
+	for _, k := range keys {
+		_, found, err := h.m.TryDelete(k)
+		if err != nil {
+			return deleted, err
+		}
+		if found {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// HasMany returns whether each of `keys` is in the set, in the order given.
+// Nodes loaded while looking up one key are retained for the lookups that follow, so each node
+// on the paths to the keys is read from the store at most once.
+func (h *Set) HasMany(keys []abi.Keyer) ([]bool, error) {
+	found := make([]bool, len(keys))
+	for i, k := range keys {
+		var err error
+		if found[i], err = h.m.Has(k); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
 // ForEach iterates over all values in the set, calling the callback for each value.
 // Returning error from the callback stops the iteration.
 func (h *Set) ForEach(cb func(k string) error) error {
@@ -0,0 +1,84 @@
+package adt_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/mock"
+)
+
+func TestSetBatchMutations(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	set, err := adt.MakeEmptySet(store, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+
+	var keys []abi.Keyer
+	for i := uint64(0); i < 100; i++ {
+		keys = append(keys, abi.UIntKey(i))
+	}
+	require.NoError(t, set.PutMany(keys))
+
+	// Delete the even keys, along with some that were never added.
+	var toDelete []abi.Keyer
+	for i := uint64(0); i < 200; i += 2 {
+		toDelete = append(toDelete, abi.UIntKey(i))
+	}
+	deleted, err := set.DeleteMany(toDelete)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(50), deleted)
+
+	root, err := set.Root()
+	require.NoError(t, err)
+	set, err = adt.AsSet(store, root, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+
+	for i := uint64(0); i < 200; i++ {
+		found, err := set.Has(abi.UIntKey(i))
+		require.NoError(t, err)
+		assert.Equal(t, i < 100 && i%2 == 1, found, "key %d", i)
+	}
+	collected, err := set.CollectKeys()
+	require.NoError(t, err)
+	assert.Len(t, collected, 50)
+
+	// Deleting the same batch again removes nothing.
+	deleted, err = set.DeleteMany(toDelete)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), deleted)
+}
+
+func TestSetHasMany(t *testing.T) {
+	rt := mock.NewBuilder(address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	set, err := adt.MakeEmptySet(store, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	for i := uint64(0); i < 100; i += 3 {
+		require.NoError(t, set.Put(abi.UIntKey(i)))
+	}
+	root, err := set.Root()
+	require.NoError(t, err)
+	set, err = adt.AsSet(store, root, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+
+	// Keys out of order, with a repeat and some beyond the range added.
+	var keys []abi.Keyer
+	var expected []bool
+	for _, i := range []uint64{99, 0, 4, 3, 150, 3, 50, 51} {
+		keys = append(keys, abi.UIntKey(i))
+		expected = append(expected, i < 100 && i%3 == 0)
+	}
+	found, err := set.HasMany(keys)
+	require.NoError(t, err)
+	assert.Equal(t, expected, found)
+
+	found, err = set.HasMany(nil)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
@@ -1,6 +1,7 @@
 package adt
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/filecoin-project/go-state-types/cbor"
@@ -8,28 +9,47 @@ import (
 	adt2 "github.com/filecoin-project/specs-actors/v2/actors/util/adt"
 	cid "github.com/ipfs/go-cid"
 	ipldcbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
 
 	vmr "github.com/filecoin-project/specs-actors/v8/actors/runtime"
 )
 
 type Store = adt2.Store
 
+var ErrBlockTooLarge = xerrors.New("block exceeds max size")
+
+// Configures a store returned by WrapStore or WrapBlockStore.
+type StoreOption func(*wstore)
+
+// Rejects Put of any value whose serialized block is larger than maxSize bytes, returning an
+// error wrapping ErrBlockTooLarge. This models the network's limit on block size.
+func WithMaxBlockSize(maxSize int) StoreOption {
+	return func(s *wstore) {
+		s.maxBlockSize = maxSize
+	}
+}
+
 // Adapts a vanilla IPLD store as an ADT store.
-func WrapStore(ctx context.Context, store ipldcbor.IpldStore) Store {
-	return &wstore{
+func WrapStore(ctx context.Context, store ipldcbor.IpldStore, opts ...StoreOption) Store {
+	s := &wstore{
 		ctx:       ctx,
 		IpldStore: store,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Adapts a block store as an ADT store.
-func WrapBlockStore(ctx context.Context, bs ipldcbor.IpldBlockstore) Store {
-	return WrapStore(ctx, ipldcbor.NewCborStore(bs))
+func WrapBlockStore(ctx context.Context, bs ipldcbor.IpldBlockstore, opts ...StoreOption) Store {
+	return WrapStore(ctx, ipldcbor.NewCborStore(bs), opts...)
 }
 
 type wstore struct {
 	ctx context.Context
 	ipldcbor.IpldStore
+	maxBlockSize int // Zero for no limit.
 }
 
 var _ Store = &wstore{}
@@ -38,6 +58,35 @@ func (s *wstore) Context() context.Context {
 	return s.ctx
 }
 
+func (s *wstore) Put(ctx context.Context, v interface{}) (cid.Cid, error) {
+	if s.maxBlockSize > 0 {
+		size, err := encodedSize(v)
+		if err != nil {
+			return cid.Undef, xerrors.Errorf("failed to encode block: %w", err)
+		}
+		if size > s.maxBlockSize {
+			return cid.Undef, xerrors.Errorf("block of %d bytes larger than %d: %w", size, s.maxBlockSize, ErrBlockTooLarge)
+		}
+	}
+	return s.IpldStore.Put(ctx, v)
+}
+
+// Returns the length of a value's serialized block.
+func encodedSize(v interface{}) (int, error) {
+	if m, ok := v.(cbor.Marshaler); ok {
+		var buf bytes.Buffer
+		if err := m.MarshalCBOR(&buf); err != nil {
+			return 0, err
+		}
+		return buf.Len(), nil
+	}
+	raw, err := ipldcbor.DumpObject(v)
+	if err != nil {
+		return 0, err
+	}
+	return len(raw), nil
+}
+
 // Adapter for a Runtime as an ADT Store.
 
 // Adapts a Runtime as an ADT store.
@@ -0,0 +1,53 @@
+package adt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+)
+
+func TestStoreMaxBlockSize(t *testing.T) {
+	ctx := context.Background()
+	maxSize := 1024
+	store := adt.WrapBlockStore(ctx, ipld.NewBlockStoreInMemory(), adt.WithMaxBlockSize(maxSize))
+
+	t.Run("accepts blocks within the limit", func(t *testing.T) {
+		v := cbg.CborInt(1)
+		_, err := store.Put(ctx, &v)
+		require.NoError(t, err)
+
+		_, err = fillArray(t, store, 4).Root()
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects an oversized block", func(t *testing.T) {
+		// An array wider than its entries holds them all in a single node.
+		_, err := fillArray(t, store, 200).Root()
+		require.Error(t, err)
+		assert.True(t, xerrors.Is(err, adt.ErrBlockTooLarge), "unexpected error %v", err)
+	})
+
+	t.Run("no limit by default", func(t *testing.T) {
+		unlimited := adt.WrapBlockStore(ctx, ipld.NewBlockStoreInMemory())
+		_, err := fillArray(t, unlimited, 200).Root()
+		require.NoError(t, err)
+	})
+}
+
+// Builds an array of bitwidth 8 holding `count` 9-byte values at contiguous indices.
+func fillArray(t *testing.T, store adt.Store, count int) *adt.Array {
+	arr, err := adt.MakeEmptyArray(store, 8)
+	require.NoError(t, err)
+	for i := 0; i < count; i++ {
+		v := cbg.CborInt(1<<40 + i)
+		require.NoError(t, arr.AppendContinuous(&v))
+	}
+	return arr
+}
@@ -96,6 +96,11 @@ func main() {
 		// method params and returns
 		//reward.AwardBlockRewardParams{}, // Aliased from v0
 		//reward.ThisEpochRewardReturn{}, // Aliased from v6
+		reward.CumulativeRewardReturn{},
+		reward.ThisEpochRewardBreakdownReturn{},
+		reward.RewardFilterStateReturn{},
+		reward.LatestEpochRewardReturn{},
+		reward.MintingDecayScheduleReturn{},
 	); err != nil {
 		panic(err)
 	}
@@ -146,6 +151,9 @@ func main() {
 		//power.EnrollCronEventParams{}, // Aliased from v0
 		//power.UpdateClaimedPowerParams{}, // Aliased from v0
 		//power.CurrentTotalPowerReturn{}, // Aliased from v6
+		power.CurrentPledgeBaseReturn{},
+		power.ReconcileClaimParams{},
+		power.ReconcileClaimReturn{},
 		// other types
 		//power.MinerConstructorParams{}, // Aliased from v3
 	); err != nil {
@@ -156,6 +164,9 @@ func main() {
 		// actor state
 		market.State{},
 		market.DealState{},
+		market.DataCapLedgerEntry{},
+		market.ClientAgents{},
+		market.CronTickSummary{},
 		// method params and returns
 		//market.WithdrawBalanceParams{}, // Aliased from v0
 		market.PublishStorageDealsParams{},
@@ -166,9 +177,25 @@ func main() {
 		//market.ComputeDataCommitmentParams{}, // Aliased from v5
 		//market.ComputeDataCommitmentReturn{}, // Aliased from v5
 		//market.OnMinerSectorsTerminateParams{}, // Aliased from v0
+		market.DealCollateralBoundsParams{},
+		market.DealCollateralBoundsReturn{},
+		market.DealDurationHistogramParams{},
+		market.DealDurationHistogramReturn{},
+		market.EscrowBreakdownParams{},
+		market.EscrowBreakdownReturn{},
+		market.BatchActivateDealsParams{},
+		market.BatchActivateDealsReturn{},
+		market.UpdateDealDurationBoundsParams{},
+		market.SettleDealPaymentsParams{},
+		market.SettleDealPaymentsReturn{},
+		market.PublishStorageDealsWithOptionsParams{},
+		market.DataCapReconciliationReturn{},
+		market.ClearDataCapMismatchesParams{},
+		market.ClientAgentParams{},
 		// other types
 		market.DealProposal{},       // Changed in v7
 		market.ClientDealProposal{}, // Changed in v7
+		market.DataCapMismatch{},
 		// market.SectorDeals{},     // Aliased from v3
 		// market.SectorWeights{},   // Aliased from v3
 	); err != nil {
@@ -191,6 +218,7 @@ func main() {
 		miner.VestingFunds{},
 		miner.VestingFund{},
 		miner.WindowedPoSt{},
+		miner.ExpirationSummary{},
 		// method params and returns
 		// miner.ConstructorParams{}, // in power actor
 		//miner.SubmitWindowedPoStParams{}, // Aliased from v0
@@ -198,6 +226,18 @@ func main() {
 		//miner.TerminateSectorsReturn{}, // Aliased from v0
 		//miner.ChangePeerIDParams{}, // Aliased from v0
 		//miner.ChangeMultiaddrsParams{}, // Aliased from v0
+		miner.DeadlineExpirationsParams{},
+		miner.DeadlineExpirationsReturn{},
+		miner.AggregateProveCommitBoundsReturn{},
+		miner.PreviewDeclareFaultsRecoveredReturn{},
+		miner.MinerAddressesReturn{},
+		miner.SectorHealthReturn{},
+		miner.SectorPledgeTopUp{},
+		miner.TopUpSectorPledgeParams{},
+		miner.DeadlineSectorsAtRisk{},
+		miner.SectorsAtRiskReturn{},
+		miner.DeadlineFaultsResult{},
+		miner.BatchDeclareFaultsReturn{},
 		//miner.ProveCommitSectorParams{}, // Aliased from v0
 		//miner.ProveCommitAggregateParams{}, // Aliased from v5
 		//miner.ChangeWorkerAddressParams{},  // Aliased from v0
@@ -233,13 +273,26 @@ func main() {
 		//verifreg.AddVerifierParams{}, // Aliased from v0
 		//verifreg.AddVerifiedClientParams{}, // Aliased from v0
 		//verifreg.UseBytesParams{}, // Aliased from v0
+		verifreg.UseBytesReturn{},
+		verifreg.RestoreBytesReturn{},
 		//verifreg.RestoreBytesParams{}, // Aliased from v0
 		verifreg.RemoveDataCapParams{}, // New in v7
 		verifreg.RemoveDataCapReturn{}, // New in v7
+		verifreg.SetMinVerifiedDealSizeParams{},
+		verifreg.AuditLogReturn{},
+		verifreg.RemoveVerifierAndReclaimParams{},
+		verifreg.RemoveVerifierAndReclaimReturn{},
+		verifreg.TotalDataCapReturn{},
+		verifreg.CanUseBytesReturn{},
+		verifreg.VerifierClientCountReturn{},
+		verifreg.RemoveVerifiersParams{},
+		verifreg.RemoveVerifiersReturn{},
+		verifreg.SetMaxClientDataCapParams{},
 		// other types
 		verifreg.RemoveDataCapRequest{},  // New in v7
 		verifreg.RemoveDataCapProposal{}, // New in v7
 		verifreg.RmDcProposalID{},        // New in v7
+		verifreg.AuditLogEntry{},
 	); err != nil {
 		panic(err)
 	}
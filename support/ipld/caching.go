@@ -0,0 +1,140 @@
+package ipld
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	block "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ipldcbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+)
+
+// A block store caching every block read from or written to another, so repeated reads are served
+// from memory. The cache is never evicted. The store is safe for concurrent use if the base store is.
+type CachingBlockStore struct {
+	base  ipldcbor.IpldBlockstore
+	mu    sync.Mutex
+	cache map[cid.Cid]block.Block
+}
+
+var _ ipldcbor.IpldBlockstore = (*CachingBlockStore)(nil)
+
+func NewCachingBlockStore(base ipldcbor.IpldBlockstore) *CachingBlockStore {
+	return &CachingBlockStore{
+		base:  base,
+		cache: make(map[cid.Cid]block.Block),
+	}
+}
+
+func (cs *CachingBlockStore) Get(ctx context.Context, c cid.Cid) (block.Block, error) {
+	if b, ok := cs.cached(c); ok {
+		return b, nil
+	}
+	b, err := cs.base.Get(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	cs.add(b)
+	return b, nil
+}
+
+func (cs *CachingBlockStore) Put(ctx context.Context, b block.Block) error {
+	if err := cs.base.Put(ctx, b); err != nil {
+		return err
+	}
+	cs.add(b)
+	return nil
+}
+
+func (cs *CachingBlockStore) Unwrap() ipldcbor.IpldBlockstore {
+	return cs.base
+}
+
+// Returns the number of blocks held in the cache.
+func (cs *CachingBlockStore) CacheSize() int {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return len(cs.cache)
+}
+
+// Prefetch warms the cache with the blocks reachable from root within depth links, so that a subsequent
+// traversal of that subtree is served from memory. A depth of zero loads only the root.
+// The blocks at each level are loaded concurrently, so the base store must be safe for concurrent use
+// (see SyncBlockStore). Only links to dag-cbor blocks are followed, as for Export.
+func (cs *CachingBlockStore) Prefetch(ctx context.Context, root cid.Cid, depth int) error {
+	if depth < 0 {
+		return xerrors.Errorf("negative prefetch depth %d", depth)
+	}
+	seen := map[cid.Cid]struct{}{root: {}}
+	level := []cid.Cid{root}
+	for d := 0; len(level) > 0; d++ {
+		blocks, err := cs.loadAll(ctx, level)
+		if err != nil {
+			return err
+		}
+		if d == depth {
+			break
+		}
+		var next []cid.Cid
+		for _, blk := range blocks {
+			if err := cbg.ScanForLinks(bytes.NewReader(blk.RawData()), func(l cid.Cid) {
+				if _, ok := seen[l]; ok || l.Prefix().Codec != cid.DagCBOR {
+					return
+				}
+				seen[l] = struct{}{}
+				next = append(next, l)
+			}); err != nil {
+				return xerrors.Errorf("failed to scan block %v for links: %w", blk.Cid(), err)
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// Loads blocks concurrently through the cache, returning them in the order requested.
+func (cs *CachingBlockStore) loadAll(ctx context.Context, cids []cid.Cid) ([]block.Block, error) {
+	blocks := make([]block.Block, len(cids))
+	errs := make([]error, len(cids))
+	var wg sync.WaitGroup
+	for i, c := range cids {
+		if b, ok := cs.cached(c); ok {
+			blocks[i] = b
+			continue
+		}
+		wg.Add(1)
+		go func(i int, c cid.Cid) {
+			defer wg.Done()
+			b, err := cs.base.Get(ctx, c)
+			if err != nil {
+				errs[i] = xerrors.Errorf("failed to prefetch block %v: %w", c, err)
+				return
+			}
+			cs.add(b)
+			blocks[i] = b
+		}(i, c)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+func (cs *CachingBlockStore) cached(c cid.Cid) (block.Block, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	b, ok := cs.cache[c]
+	return b, ok
+}
+
+func (cs *CachingBlockStore) add(b block.Block) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.cache[b.Cid()] = b
+}
@@ -0,0 +1,130 @@
+package ipld_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	block "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ipldcbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+)
+
+func TestCachingBlockStorePrefetch(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*ipld.MetricsBlockStore, *ipld.CachingBlockStore, cid.Cid) {
+		mem := ipld.NewBlockStoreInMemory()
+		root := buildArray(t, adt.WrapBlockStore(ctx, mem), 64)
+		metrics := ipld.NewMetricsBlockStore(mem)
+		return metrics, ipld.NewCachingBlockStore(ipld.NewSyncBlockStore(metrics)), root
+	}
+
+	t.Run("depth zero loads only the root", func(t *testing.T) {
+		metrics, cs, root := setup(t)
+		require.NoError(t, cs.Prefetch(ctx, root, 0))
+		assert.Equal(t, 1, cs.CacheSize())
+		assert.Equal(t, uint64(1), metrics.Reads)
+	})
+
+	t.Run("loads each level up to the depth", func(t *testing.T) {
+		_, shallow, root := setup(t)
+		require.NoError(t, shallow.Prefetch(ctx, root, 1))
+		_, deep, _ := setup(t)
+		require.NoError(t, deep.Prefetch(ctx, root, 2))
+		assert.Greater(t, shallow.CacheSize(), 1)
+		assert.Greater(t, deep.CacheSize(), shallow.CacheSize())
+	})
+
+	t.Run("traversal after full prefetch reads nothing from the base", func(t *testing.T) {
+		metrics, cs, root := setup(t)
+		require.NoError(t, cs.Prefetch(ctx, root, 100))
+
+		reads := metrics.Reads
+		traverseArray(t, adt.WrapBlockStore(ctx, cs), root)
+		assert.Equal(t, reads, metrics.Reads)
+	})
+
+	t.Run("missing block fails", func(t *testing.T) {
+		mem := ipld.NewBlockStoreInMemory()
+		fs := ipld.NewFaultInjectingBlockStore(mem)
+		root := buildArray(t, adt.WrapBlockStore(ctx, mem), 64)
+		fs.FailGet(root, ipld.ErrNotFound)
+
+		cs := ipld.NewCachingBlockStore(ipld.NewSyncBlockStore(fs))
+		err := cs.Prefetch(ctx, root, 1)
+		require.Error(t, err)
+		assert.True(t, xerrors.Is(err, ipld.ErrNotFound))
+	})
+
+	t.Run("negative depth fails", func(t *testing.T) {
+		_, cs, root := setup(t)
+		require.Error(t, cs.Prefetch(ctx, root, -1))
+	})
+}
+
+// Compares a traversal of a cold cache over a slow store with one following a prefetch.
+// The prefetch itself is excluded from the timing of the traversal it warms.
+func BenchmarkCachingBlockStoreTraversal(b *testing.B) {
+	ctx := context.Background()
+	mem := ipld.NewBlockStoreInMemory()
+	root := buildArray(b, adt.WrapBlockStore(ctx, mem), 256)
+	slow := &slowBlockStore{ipld.NewSyncBlockStore(mem), 100 * time.Microsecond}
+
+	b.Run("cold", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			traverseArray(b, adt.WrapBlockStore(ctx, ipld.NewCachingBlockStore(slow)), root)
+		}
+	})
+	b.Run("prefetched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			cs := ipld.NewCachingBlockStore(slow)
+			require.NoError(b, cs.Prefetch(ctx, root, 100))
+			b.StartTimer()
+			traverseArray(b, adt.WrapBlockStore(ctx, cs), root)
+		}
+	})
+}
+
+// Builds an array of narrow bitwidth, so that its nodes span several levels.
+func buildArray(t testing.TB, store adt.Store, count int) cid.Cid {
+	arr, err := adt.MakeEmptyArray(store, 2)
+	require.NoError(t, err)
+	for i := 0; i < count; i++ {
+		v := cbg.CborInt(i)
+		require.NoError(t, arr.AppendContinuous(&v))
+	}
+	root, err := arr.Root()
+	require.NoError(t, err)
+	return root
+}
+
+func traverseArray(t testing.TB, store adt.Store, root cid.Cid) {
+	arr, err := adt.AsArray(store, root, 2)
+	require.NoError(t, err)
+	var v cbg.CborInt
+	require.NoError(t, arr.ForEach(&v, func(int64) error { return nil }))
+}
+
+// A block store adding a fixed latency to every read.
+type slowBlockStore struct {
+	bs      ipldcbor.IpldBlockstore
+	latency time.Duration
+}
+
+func (ss *slowBlockStore) Get(ctx context.Context, c cid.Cid) (block.Block, error) {
+	time.Sleep(ss.latency)
+	return ss.bs.Get(ctx, c)
+}
+
+func (ss *slowBlockStore) Put(ctx context.Context, b block.Block) error {
+	return ss.bs.Put(ctx, b)
+}
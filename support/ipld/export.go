@@ -0,0 +1,109 @@
+package ipld
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipld/go-car"
+	carutil "github.com/ipld/go-car/util"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+)
+
+// Export writes all blocks reachable from roots to w in CAR format.
+// Blocks are written in ascending order of CID bytes, so exporting the same DAG always produces the same output
+// regardless of insertion order. Only links to dag-cbor blocks are followed; other links (such as piece
+// commitments and builtin actor code CIDs) are not expected to be present in the store.
+func (mb *BlockStoreInMemory) Export(ctx context.Context, roots []cid.Cid, w io.Writer) error {
+	reachable, err := mb.reachableFrom(ctx, roots)
+	if err != nil {
+		return err
+	}
+
+	ordered := make([]cid.Cid, 0, len(reachable))
+	for c := range reachable {
+		ordered = append(ordered, c)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return bytes.Compare(ordered[i].Bytes(), ordered[j].Bytes()) < 0
+	})
+
+	if err := car.WriteHeader(&car.CarHeader{Roots: roots, Version: 1}, w); err != nil {
+		return xerrors.Errorf("failed to write car header: %w", err)
+	}
+	for _, c := range ordered {
+		if err := carutil.LdWrite(w, c.Bytes(), mb.data[c].RawData()); err != nil {
+			return xerrors.Errorf("failed to write block %v: %w", c, err)
+		}
+	}
+	return nil
+}
+
+// ImportCAR reads all blocks from a CAR-format stream into the store, returning the roots named in its header.
+// Each block's data is checked against its CID. Blocks are not checked to be reachable from the roots, nor the
+// roots to be present. This is the inverse of Export.
+func (mb *BlockStoreInMemory) ImportCAR(ctx context.Context, r io.Reader) ([]cid.Cid, error) {
+	cr, err := car.NewCarReader(r)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read car header: %w", err)
+	}
+	for {
+		blk, err := cr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, xerrors.Errorf("failed to read car block: %w", err)
+		}
+		expected, err := blk.Cid().Prefix().Sum(blk.RawData())
+		if err != nil {
+			return nil, xerrors.Errorf("failed to hash block %v: %w", blk.Cid(), err)
+		}
+		if !expected.Equals(blk.Cid()) {
+			return nil, xerrors.Errorf("block data does not match cid %v", blk.Cid())
+		}
+		if err := mb.Put(ctx, blk); err != nil {
+			return nil, xerrors.Errorf("failed to put block %v: %w", blk.Cid(), err)
+		}
+	}
+	return cr.Header.Roots, nil
+}
+
+// Returns the set of blocks reachable from roots, following only links to dag-cbor blocks.
+func (mb *BlockStoreInMemory) reachableFrom(ctx context.Context, roots []cid.Cid) (map[cid.Cid]struct{}, error) {
+	reachable := make(map[cid.Cid]struct{})
+	var visit func(c cid.Cid) error
+	visit = func(c cid.Cid) error {
+		if _, ok := reachable[c]; ok {
+			return nil
+		}
+		blk, err := mb.Get(ctx, c)
+		if err != nil {
+			return xerrors.Errorf("failed to get block %v: %w", c, err)
+		}
+		reachable[c] = struct{}{}
+
+		var links []cid.Cid
+		if err := cbg.ScanForLinks(bytes.NewReader(blk.RawData()), func(l cid.Cid) {
+			if l.Prefix().Codec == cid.DagCBOR {
+				links = append(links, l)
+			}
+		}); err != nil {
+			return xerrors.Errorf("failed to scan block %v for links: %w", c, err)
+		}
+		for _, l := range links {
+			if err := visit(l); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, r := range roots {
+		if err := visit(r); err != nil {
+			return nil, err
+		}
+	}
+	return reachable, nil
+}
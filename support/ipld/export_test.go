@@ -0,0 +1,172 @@
+package ipld_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/ipld/go-car"
+	carutil "github.com/ipld/go-car/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+
+	// Builds a map in a new store, inserting entries in the given order.
+	build := func(t *testing.T, keys []uint64) (*ipld.BlockStoreInMemory, cid.Cid) {
+		bs := ipld.NewBlockStoreInMemory()
+		store := adt.WrapBlockStore(ctx, bs)
+		m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		for _, k := range keys {
+			v := cbg.CborInt(k)
+			require.NoError(t, m.Put(abi.UIntKey(k), &v))
+			// Flush intermediate roots so the store holds unreachable blocks too.
+			_, err := m.Root()
+			require.NoError(t, err)
+		}
+		root, err := m.Root()
+		require.NoError(t, err)
+		return bs, root
+	}
+
+	var keys, reversed []uint64
+	for i := uint64(0); i < 500; i++ {
+		keys = append(keys, i)
+		reversed = append([]uint64{i}, reversed...)
+	}
+	bs, root := build(t, keys)
+	bsRev, rootRev := build(t, reversed)
+	require.Equal(t, root, rootRev)
+
+	var out, outRev bytes.Buffer
+	require.NoError(t, bs.Export(ctx, []cid.Cid{root}, &out))
+	require.NoError(t, bsRev.Export(ctx, []cid.Cid{root}, &outRev))
+	assert.Equal(t, out.Bytes(), outRev.Bytes(), "export is independent of insertion order")
+
+	// Import into a fresh store.
+	imported := ipld.NewBlockStoreInMemory()
+	cr, err := car.NewCarReader(bytes.NewReader(out.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, []cid.Cid{root}, cr.Header.Roots)
+	count := 0
+	for {
+		blk, err := cr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.NoError(t, imported.Put(ctx, blk))
+		count++
+	}
+	assert.Greater(t, count, 1)
+
+	// The imported store holds the whole map, and exports identically.
+	m, err := adt.AsMap(adt.WrapBlockStore(ctx, imported), root, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var v cbg.CborInt
+	found := 0
+	require.NoError(t, m.ForEach(&v, func(key string) error {
+		found++
+		return nil
+	}))
+	assert.Equal(t, len(keys), found)
+
+	var reexported bytes.Buffer
+	require.NoError(t, imported.Export(ctx, []cid.Cid{root}, &reexported))
+	assert.Equal(t, out.Bytes(), reexported.Bytes())
+
+	// Exporting a root that is not present fails.
+	missing, err := abi.CidBuilder.Sum([]byte("missing"))
+	require.NoError(t, err)
+	assert.Error(t, bs.Export(ctx, []cid.Cid{missing}, io.Discard))
+}
+
+func TestImportCAR(t *testing.T) {
+	ctx := context.Background()
+
+	// Writes a CAR holding the given blocks, with the first as root.
+	writeCAR := func(t *testing.T, blocks ...[]byte) ([]cid.Cid, []byte) {
+		var out bytes.Buffer
+		var cids []cid.Cid
+		for _, data := range blocks {
+			c, err := abi.CidBuilder.Sum(data)
+			require.NoError(t, err)
+			cids = append(cids, c)
+		}
+		require.NoError(t, car.WriteHeader(&car.CarHeader{Roots: cids[:1], Version: 1}, &out))
+		for i, data := range blocks {
+			require.NoError(t, carutil.LdWrite(&out, cids[i].Bytes(), data))
+		}
+		return cids, out.Bytes()
+	}
+
+	t.Run("imports blocks and returns roots", func(t *testing.T) {
+		var value, other bytes.Buffer
+		v := cbg.CborInt(1234)
+		require.NoError(t, v.MarshalCBOR(&value))
+		o := cbg.CborInt(5678)
+		require.NoError(t, o.MarshalCBOR(&other))
+		cids, data := writeCAR(t, value.Bytes(), other.Bytes())
+
+		bs := ipld.NewBlockStoreInMemory()
+		roots, err := bs.ImportCAR(ctx, bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, cids[:1], roots)
+
+		store := adt.WrapBlockStore(ctx, bs)
+		var got cbg.CborInt
+		require.NoError(t, store.Get(ctx, roots[0], &got))
+		assert.Equal(t, cbg.CborInt(1234), got)
+		require.NoError(t, store.Get(ctx, cids[1], &got))
+		assert.Equal(t, cbg.CborInt(5678), got)
+	})
+
+	t.Run("round trips an export", func(t *testing.T) {
+		bs := ipld.NewBlockStoreInMemory()
+		store := adt.WrapBlockStore(ctx, bs)
+		m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		for k := uint64(0); k < 100; k++ {
+			v := cbg.CborInt(k * 10)
+			require.NoError(t, m.Put(abi.UIntKey(k), &v))
+		}
+		root, err := m.Root()
+		require.NoError(t, err)
+		var out bytes.Buffer
+		require.NoError(t, bs.Export(ctx, []cid.Cid{root}, &out))
+
+		imported := ipld.NewBlockStoreInMemory()
+		roots, err := imported.ImportCAR(ctx, bytes.NewReader(out.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, []cid.Cid{root}, roots)
+
+		m, err = adt.AsMap(adt.WrapBlockStore(ctx, imported), root, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		var v cbg.CborInt
+		found, err := m.Get(abi.UIntKey(42), &v)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, cbg.CborInt(420), v)
+	})
+
+	t.Run("rejects block not matching its cid", func(t *testing.T) {
+		cids, _ := writeCAR(t, []byte{0x01})
+		var out bytes.Buffer
+		require.NoError(t, car.WriteHeader(&car.CarHeader{Roots: cids, Version: 1}, &out))
+		require.NoError(t, carutil.LdWrite(&out, cids[0].Bytes(), []byte{0x02}))
+
+		_, err := ipld.NewBlockStoreInMemory().ImportCAR(ctx, bytes.NewReader(out.Bytes()))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+package ipld
+
+import (
+	"context"
+
+	block "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ipldcbor "github.com/ipfs/go-ipld-cbor"
+)
+
+// A block store wrapper that fails configured operations, for testing the handling of store errors.
+type FaultInjectingBlockStore struct {
+	bs ipldcbor.IpldBlockstore
+
+	// Errors to return for operations on specific blocks, by CID.
+	getFaults map[cid.Cid]error
+	putFaults map[cid.Cid]error
+	// Errors to return for the nth subsequent operation, counting from 1.
+	nthGet, nthPut       uint64
+	nthGetErr, nthPutErr error
+}
+
+var _ ipldcbor.IpldBlockstore = (*FaultInjectingBlockStore)(nil)
+
+func NewFaultInjectingBlockStore(underlying ipldcbor.IpldBlockstore) *FaultInjectingBlockStore {
+	return &FaultInjectingBlockStore{
+		bs:        underlying,
+		getFaults: make(map[cid.Cid]error),
+		putFaults: make(map[cid.Cid]error),
+	}
+}
+
+func (fs *FaultInjectingBlockStore) Get(ctx context.Context, c cid.Cid) (block.Block, error) {
+	if err := tick(&fs.nthGet, &fs.nthGetErr); err != nil {
+		return nil, err
+	}
+	if err, ok := fs.getFaults[c]; ok {
+		return nil, err
+	}
+	return fs.bs.Get(ctx, c)
+}
+
+func (fs *FaultInjectingBlockStore) Put(ctx context.Context, b block.Block) error {
+	if err := tick(&fs.nthPut, &fs.nthPutErr); err != nil {
+		return err
+	}
+	if err, ok := fs.putFaults[b.Cid()]; ok {
+		return err
+	}
+	return fs.bs.Put(ctx, b)
+}
+
+func (fs *FaultInjectingBlockStore) Unwrap() ipldcbor.IpldBlockstore {
+	return fs.bs
+}
+
+// Fails every Get of the block c with err.
+func (fs *FaultInjectingBlockStore) FailGet(c cid.Cid, err error) {
+	fs.getFaults[c] = err
+}
+
+// Fails every Put of the block c with err.
+func (fs *FaultInjectingBlockStore) FailPut(c cid.Cid, err error) {
+	fs.putFaults[c] = err
+}
+
+// Fails only the nth subsequent Get with err, where n = 1 is the next Get.
+func (fs *FaultInjectingBlockStore) FailNthGet(n uint64, err error) {
+	fs.nthGet, fs.nthGetErr = n, err
+}
+
+// Fails only the nth subsequent Put with err, where n = 1 is the next Put.
+func (fs *FaultInjectingBlockStore) FailNthPut(n uint64, err error) {
+	fs.nthPut, fs.nthPutErr = n, err
+}
+
+// Removes all configured faults.
+func (fs *FaultInjectingBlockStore) Clear() {
+	fs.getFaults = make(map[cid.Cid]error)
+	fs.putFaults = make(map[cid.Cid]error)
+	fs.nthGet, fs.nthGetErr = 0, nil
+	fs.nthPut, fs.nthPutErr = 0, nil
+}
+
+// Counts down an nth-operation fault, returning its error when the count reaches the operation.
+func tick(n *uint64, err *error) error {
+	if *n == 0 {
+		return nil
+	}
+	*n--
+	if *n > 0 {
+		return nil
+	}
+	e := *err
+	*err = nil
+	return e
+}
@@ -0,0 +1,77 @@
+package ipld_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+)
+
+func TestFaultInjectingBlockStore(t *testing.T) {
+	ctx := context.Background()
+	injected := xerrors.New("injected")
+
+	setup := func() (*ipld.FaultInjectingBlockStore, adt.Store) {
+		fs := ipld.NewFaultInjectingBlockStore(ipld.NewBlockStoreInMemory())
+		return fs, adt.WrapBlockStore(ctx, fs)
+	}
+
+	t.Run("fails gets and puts of specific blocks", func(t *testing.T) {
+		fs, store := setup()
+		one, two := cbg.CborInt(1), cbg.CborInt(2)
+		c1, err := store.Put(ctx, &one)
+		require.NoError(t, err)
+		c2, err := store.Put(ctx, &two)
+		require.NoError(t, err)
+
+		fs.FailGet(c1, injected)
+		var out cbg.CborInt
+		assert.True(t, xerrors.Is(store.Get(ctx, c1, &out), injected))
+		require.NoError(t, store.Get(ctx, c2, &out))
+		assert.Equal(t, two, out)
+
+		fs.FailPut(c2, injected)
+		_, err = store.Put(ctx, &two)
+		assert.True(t, xerrors.Is(err, injected))
+
+		fs.Clear()
+		require.NoError(t, store.Get(ctx, c1, &out))
+		assert.Equal(t, one, out)
+		_, err = store.Put(ctx, &two)
+		require.NoError(t, err)
+	})
+
+	t.Run("fails only the nth operation", func(t *testing.T) {
+		fs, store := setup()
+		v := cbg.CborInt(1)
+		c, err := store.Put(ctx, &v)
+		require.NoError(t, err)
+
+		fs.FailNthGet(2, injected)
+		fs.FailNthPut(1, injected)
+		var out cbg.CborInt
+		require.NoError(t, store.Get(ctx, c, &out))
+		assert.True(t, xerrors.Is(store.Get(ctx, c, &out), injected))
+		require.NoError(t, store.Get(ctx, c, &out))
+
+		_, err = store.Put(ctx, &v)
+		assert.True(t, xerrors.Is(err, injected))
+		_, err = store.Put(ctx, &v)
+		require.NoError(t, err)
+	})
+
+	t.Run("missing blocks are not found", func(t *testing.T) {
+		_, store := setup()
+		missing := cbg.CborInt(3)
+		c, _, err := ipld.MarshalCBOR(&missing)
+		require.NoError(t, err)
+		var out cbg.CborInt
+		assert.True(t, xerrors.Is(store.Get(ctx, c, &out), ipld.ErrNotFound))
+	})
+}
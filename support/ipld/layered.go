@@ -0,0 +1,74 @@
+package ipld
+
+import (
+	"context"
+
+	block "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	ipldcbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
+)
+
+// A copy-on-write block store layered over another, for speculative execution.
+// Puts are held in an overlay and gets read through it to the base store, so the base is not
+// modified until the overlay is committed. Wrap with adt.WrapBlockStore for use as an ADT store.
+type LayeredBlockStore struct {
+	base    ipldcbor.IpldBlockstore
+	overlay map[cid.Cid]block.Block
+	// CIDs of overlay blocks in the order they were first put, so commits are deterministic.
+	order []cid.Cid
+}
+
+var _ ipldcbor.IpldBlockstore = (*LayeredBlockStore)(nil)
+
+func NewLayeredBlockStore(base ipldcbor.IpldBlockstore) *LayeredBlockStore {
+	return &LayeredBlockStore{
+		base:    base,
+		overlay: make(map[cid.Cid]block.Block),
+	}
+}
+
+func (ls *LayeredBlockStore) Get(ctx context.Context, c cid.Cid) (block.Block, error) {
+	if b, ok := ls.overlay[c]; ok {
+		return b, nil
+	}
+	return ls.base.Get(ctx, c)
+}
+
+func (ls *LayeredBlockStore) Put(ctx context.Context, b block.Block) error {
+	if _, ok := ls.overlay[b.Cid()]; !ok {
+		ls.order = append(ls.order, b.Cid())
+	}
+	ls.overlay[b.Cid()] = b
+	return nil
+}
+
+func (ls *LayeredBlockStore) Unwrap() ipldcbor.IpldBlockstore {
+	return ls.base
+}
+
+// Returns the number of blocks held in the overlay.
+func (ls *LayeredBlockStore) OverlaySize() int {
+	return len(ls.overlay)
+}
+
+// Flattens the overlay into the base store, after which the overlay is empty.
+// If a put to the base fails, the blocks not yet written remain in the overlay.
+func (ls *LayeredBlockStore) Commit(ctx context.Context) error {
+	for i, c := range ls.order {
+		if err := ls.base.Put(ctx, ls.overlay[c]); err != nil {
+			ls.order = ls.order[i:]
+			return xerrors.Errorf("failed to commit block %v: %w", c, err)
+		}
+		delete(ls.overlay, c)
+	}
+	ls.order = nil
+	return nil
+}
+
+// Discards all blocks held in the overlay, leaving the base store as it was when the layer was created
+// or last committed.
+func (ls *LayeredBlockStore) Abort() {
+	ls.overlay = make(map[cid.Cid]block.Block)
+	ls.order = nil
+}
@@ -0,0 +1,102 @@
+package ipld_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+)
+
+func TestLayeredBlockStore(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*ipld.BlockStoreInMemory, *ipld.LayeredBlockStore, adt.Store, cid.Cid) {
+		base := ipld.NewBlockStoreInMemory()
+		one := cbg.CborInt(1)
+		c, err := adt.WrapBlockStore(ctx, base).Put(ctx, &one)
+		require.NoError(t, err)
+		ls := ipld.NewLayeredBlockStore(base)
+		return base, ls, adt.WrapBlockStore(ctx, ls), c
+	}
+
+	t.Run("reads through to the base", func(t *testing.T) {
+		_, _, store, c := setup(t)
+		var out cbg.CborInt
+		require.NoError(t, store.Get(ctx, c, &out))
+		assert.Equal(t, cbg.CborInt(1), out)
+	})
+
+	t.Run("abort discards overlay writes", func(t *testing.T) {
+		base, ls, store, _ := setup(t)
+		two := cbg.CborInt(2)
+		c, err := store.Put(ctx, &two)
+		require.NoError(t, err)
+
+		var out cbg.CborInt
+		require.NoError(t, store.Get(ctx, c, &out))
+		assert.Equal(t, two, out)
+		_, err = base.Get(ctx, c)
+		assert.True(t, xerrors.Is(err, ipld.ErrNotFound))
+
+		ls.Abort()
+		assert.Equal(t, 0, ls.OverlaySize())
+		_, err = ls.Get(ctx, c)
+		assert.True(t, xerrors.Is(err, ipld.ErrNotFound))
+		_, err = base.Get(ctx, c)
+		assert.True(t, xerrors.Is(err, ipld.ErrNotFound))
+	})
+
+	t.Run("commit flattens overlay into the base", func(t *testing.T) {
+		base, ls, store, _ := setup(t)
+		two, three := cbg.CborInt(2), cbg.CborInt(3)
+		c2, err := store.Put(ctx, &two)
+		require.NoError(t, err)
+		c3, err := store.Put(ctx, &three)
+		require.NoError(t, err)
+		assert.Equal(t, 2, ls.OverlaySize())
+
+		require.NoError(t, ls.Commit(ctx))
+		assert.Equal(t, 0, ls.OverlaySize())
+		baseStore := adt.WrapBlockStore(ctx, base)
+		var out cbg.CborInt
+		require.NoError(t, baseStore.Get(ctx, c2, &out))
+		assert.Equal(t, two, out)
+		require.NoError(t, baseStore.Get(ctx, c3, &out))
+		assert.Equal(t, three, out)
+
+		// Aborting after a commit leaves committed blocks in place.
+		ls.Abort()
+		require.NoError(t, store.Get(ctx, c2, &out))
+		assert.Equal(t, two, out)
+	})
+
+	t.Run("failed commit retains unwritten blocks", func(t *testing.T) {
+		injected := xerrors.New("injected")
+		fs := ipld.NewFaultInjectingBlockStore(ipld.NewBlockStoreInMemory())
+		ls := ipld.NewLayeredBlockStore(fs)
+		store := adt.WrapBlockStore(ctx, ls)
+		one, two := cbg.CborInt(1), cbg.CborInt(2)
+		_, err := store.Put(ctx, &one)
+		require.NoError(t, err)
+		c2, err := store.Put(ctx, &two)
+		require.NoError(t, err)
+
+		fs.FailPut(c2, injected)
+		assert.True(t, xerrors.Is(ls.Commit(ctx), injected))
+		assert.Equal(t, 1, ls.OverlaySize())
+
+		fs.Clear()
+		require.NoError(t, ls.Commit(ctx))
+		assert.Equal(t, 0, ls.OverlaySize())
+		var out cbg.CborInt
+		require.NoError(t, adt.WrapBlockStore(ctx, fs).Get(ctx, c2, &out))
+		assert.Equal(t, two, out)
+	})
+}
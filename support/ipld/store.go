@@ -1,13 +1,16 @@
 package ipld
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"io"
 	"sync"
 
 	block "github.com/ipfs/go-block-format"
 	"github.com/ipfs/go-cid"
 	ipldcbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 )
@@ -18,6 +21,9 @@ func NewADTStore(ctx context.Context) adt.Store {
 	return adt.WrapBlockStore(ctx, NewBlockStoreInMemory())
 }
 
+// Returned by BlockStoreInMemory when getting a block it does not hold.
+var ErrNotFound = xerrors.New("not found")
+
 //
 // A basic in-memory block store.
 //
@@ -36,7 +42,7 @@ func (mb *BlockStoreInMemory) Get(ctx context.Context, c cid.Cid) (block.Block,
 	if ok {
 		return d, nil
 	}
-	return nil, fmt.Errorf("not found")
+	return nil, ErrNotFound
 }
 
 func (mb *BlockStoreInMemory) Put(ctx context.Context, b block.Block) error {
@@ -44,6 +50,24 @@ func (mb *BlockStoreInMemory) Put(ctx context.Context, b block.Block) error {
 	return nil
 }
 
+// GC deletes all blocks not reachable from roots, returning the number of blocks deleted.
+// Reachability is determined as for Export. If any block reachable from roots is missing from the
+// store, nothing is deleted and an error is returned.
+func (mb *BlockStoreInMemory) GC(ctx context.Context, roots []cid.Cid) (int, error) {
+	reachable, err := mb.reachableFrom(ctx, roots)
+	if err != nil {
+		return 0, err
+	}
+	collected := 0
+	for c := range mb.data {
+		if _, ok := reachable[c]; !ok {
+			delete(mb.data, c)
+			collected++
+		}
+	}
+	return collected, nil
+}
+
 //
 // Synchronized block store wrapper.
 //
@@ -72,6 +96,10 @@ func (ss *SyncBlockStore) Put(ctx context.Context, b block.Block) error {
 	return ss.bs.Put(ctx, b)
 }
 
+func (ss *SyncBlockStore) Unwrap() ipldcbor.IpldBlockstore {
+	return ss.bs
+}
+
 //
 // Metric-recording block store wrapper.
 //
@@ -81,6 +109,12 @@ type MetricsBlockStore struct {
 	WriteBytes uint64
 	Reads      uint64
 	ReadBytes  uint64
+
+	// Optional classifier of blocks read, set by NewClassifyingMetricsBlockStore.
+	// If set, reads are also counted per class.
+	Classify       func(blk block.Block) BlockClass
+	ClassReads     map[BlockClass]uint64
+	ClassReadBytes map[BlockClass]uint64
 }
 
 var _ ipldcbor.IpldBlockstore = (*MetricsBlockStore)(nil)
@@ -89,6 +123,16 @@ func NewMetricsBlockStore(underlying ipldcbor.IpldBlockstore) *MetricsBlockStore
 	return &MetricsBlockStore{bs: underlying}
 }
 
+// Creates a metrics block store that also counts reads per class, as determined by classify.
+func NewClassifyingMetricsBlockStore(underlying ipldcbor.IpldBlockstore, classify func(blk block.Block) BlockClass) *MetricsBlockStore {
+	return &MetricsBlockStore{
+		bs:             underlying,
+		Classify:       classify,
+		ClassReads:     make(map[BlockClass]uint64),
+		ClassReadBytes: make(map[BlockClass]uint64),
+	}
+}
+
 func (ms *MetricsBlockStore) Get(ctx context.Context, c cid.Cid) (block.Block, error) {
 	ms.Reads++
 	blk, err := ms.bs.Get(ctx, c)
@@ -96,6 +140,11 @@ func (ms *MetricsBlockStore) Get(ctx context.Context, c cid.Cid) (block.Block, e
 		return blk, err
 	}
 	ms.ReadBytes += uint64(len(blk.RawData()))
+	if ms.Classify != nil {
+		class := ms.Classify(blk)
+		ms.ClassReads[class]++
+		ms.ClassReadBytes[class] += uint64(len(blk.RawData()))
+	}
 	return blk, nil
 }
 
@@ -120,3 +169,167 @@ func (ms *MetricsBlockStore) ReadSize() uint64 {
 func (ms *MetricsBlockStore) WriteSize() uint64 {
 	return ms.WriteBytes
 }
+
+func (ms *MetricsBlockStore) ClassReadCount(class BlockClass) uint64 {
+	return ms.ClassReads[class]
+}
+
+func (ms *MetricsBlockStore) ClassReadSize(class BlockClass) uint64 {
+	return ms.ClassReadBytes[class]
+}
+
+func (ms *MetricsBlockStore) Stats() Stats {
+	return Stats{
+		Reads:      ms.Reads,
+		ReadBytes:  ms.ReadBytes,
+		Writes:     ms.Writes,
+		WriteBytes: ms.WriteBytes,
+	}
+}
+
+func (ms *MetricsBlockStore) Unwrap() ipldcbor.IpldBlockstore {
+	return ms.bs
+}
+
+//
+// Stats aggregation across wrapped block stores.
+//
+
+// Counts of the block operations recorded by a block store.
+type Stats struct {
+	Reads      uint64
+	ReadBytes  uint64
+	Writes     uint64
+	WriteBytes uint64
+}
+
+func (s Stats) Add(other Stats) Stats {
+	return Stats{
+		Reads:      s.Reads + other.Reads,
+		ReadBytes:  s.ReadBytes + other.ReadBytes,
+		Writes:     s.Writes + other.Writes,
+		WriteBytes: s.WriteBytes + other.WriteBytes,
+	}
+}
+
+// Implemented by block stores that record the operations performed on them.
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// Implemented by block stores that wrap another, exposing the wrapped store.
+type BlockStoreWrapper interface {
+	Unwrap() ipldcbor.IpldBlockstore
+}
+
+// Sums the stats of every store reporting them in the chain of wrappers starting at bs.
+// Each operation is counted once by every reporting store it passes through, so the same operation
+// reaching two metrics stores is counted twice.
+func AggregateStats(bs ipldcbor.IpldBlockstore) Stats {
+	var total Stats
+	for bs != nil {
+		if reporter, ok := bs.(StatsReporter); ok {
+			total = total.Add(reporter.Stats())
+		}
+		wrapper, ok := bs.(BlockStoreWrapper)
+		if !ok {
+			break
+		}
+		bs = wrapper.Unwrap()
+	}
+	return total
+}
+
+// A stats source reporting the aggregate stats of a chain of wrapped block stores, as of each call.
+type AggregateStatsSource struct {
+	bs ipldcbor.IpldBlockstore
+}
+
+func NewAggregateStatsSource(bs ipldcbor.IpldBlockstore) *AggregateStatsSource {
+	return &AggregateStatsSource{bs: bs}
+}
+
+func (as *AggregateStatsSource) ReadCount() uint64 {
+	return AggregateStats(as.bs).Reads
+}
+
+func (as *AggregateStatsSource) WriteCount() uint64 {
+	return AggregateStats(as.bs).Writes
+}
+
+func (as *AggregateStatsSource) ReadSize() uint64 {
+	return AggregateStats(as.bs).ReadBytes
+}
+
+func (as *AggregateStatsSource) WriteSize() uint64 {
+	return AggregateStats(as.bs).WriteBytes
+}
+
+// A coarse classification of blocks by the data structure they belong to.
+type BlockClass int
+
+const (
+	BlockClassLeaf BlockClass = iota // Any block not recognised as a collection node
+	BlockClassHamt                   // A HAMT node
+	BlockClassAmt                    // An AMT root or node
+)
+
+func (c BlockClass) String() string {
+	switch c {
+	case BlockClassHamt:
+		return "hamt"
+	case BlockClassAmt:
+		return "amt"
+	default:
+		return "leaf"
+	}
+}
+
+// Classifies a block by inspecting the shape of its CBOR encoding.
+// A HAMT node is a 2-tuple and an AMT node a 3-tuple, each of a bitmap followed by an array.
+// An AMT root is a 4-tuple of three integers followed by an AMT node.
+// This is a heuristic: a leaf value with a matching shape is counted as a collection node.
+func ClassifyBlockCBOR(blk block.Block) BlockClass {
+	br := bytes.NewReader(blk.RawData())
+	maj, n, err := cbg.CborReadHeader(br)
+	if err != nil || maj != cbg.MajArray {
+		return BlockClassLeaf
+	}
+	switch n {
+	case 2, 3:
+		if !isBitmapThenArray(br) {
+			return BlockClassLeaf
+		}
+		if n == 2 {
+			return BlockClassHamt
+		}
+		return BlockClassAmt
+	case 4:
+		for i := 0; i < 3; i++ {
+			if maj, _, err := cbg.CborReadHeader(br); err != nil || maj != cbg.MajUnsignedInt {
+				return BlockClassLeaf
+			}
+		}
+		if maj, n, err := cbg.CborReadHeader(br); err != nil || maj != cbg.MajArray || n != 3 {
+			return BlockClassLeaf
+		}
+		if !isBitmapThenArray(br) {
+			return BlockClassLeaf
+		}
+		return BlockClassAmt
+	}
+	return BlockClassLeaf
+}
+
+// Reads a byte string followed by the header of an array.
+func isBitmapThenArray(br *bytes.Reader) bool {
+	maj, n, err := cbg.CborReadHeader(br)
+	if err != nil || maj != cbg.MajByteString {
+		return false
+	}
+	if _, err := br.Seek(int64(n), io.SeekCurrent); err != nil {
+		return false
+	}
+	maj, _, err = cbg.CborReadHeader(br)
+	return err == nil && maj == cbg.MajArray
+}
@@ -0,0 +1,184 @@
+package ipld_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+)
+
+func TestBlockStoreGC(t *testing.T) {
+	ctx := context.Background()
+	bs := ipld.NewBlockStoreInMemory()
+	store := adt.WrapBlockStore(ctx, bs)
+
+	// Builds a map, flushing intermediate roots so the store holds unreachable blocks too.
+	build := func(keys []uint64) cid.Cid {
+		m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		for _, k := range keys {
+			v := cbg.CborInt(k)
+			require.NoError(t, m.Put(abi.UIntKey(k), &v))
+			_, err := m.Root()
+			require.NoError(t, err)
+		}
+		root, err := m.Root()
+		require.NoError(t, err)
+		return root
+	}
+	var kept, discarded []uint64
+	for i := uint64(0); i < 200; i++ {
+		kept = append(kept, i)
+		discarded = append(discarded, i+1000)
+	}
+	keptRoot := build(kept)
+	discardedRoot := build(discarded)
+
+	// A missing root fails without collecting anything.
+	missing, err := abi.CidBuilder.Sum([]byte("missing"))
+	require.NoError(t, err)
+	_, err = bs.GC(ctx, []cid.Cid{keptRoot, missing})
+	assert.Error(t, err)
+	_, err = bs.Get(ctx, discardedRoot)
+	require.NoError(t, err)
+
+	collected, err := bs.GC(ctx, []cid.Cid{keptRoot})
+	require.NoError(t, err)
+	assert.Greater(t, collected, 0)
+
+	// The unreachable map is gone.
+	_, err = bs.Get(ctx, discardedRoot)
+	assert.Error(t, err)
+
+	// Every entry of the reachable map survives.
+	m, err := adt.AsMap(store, keptRoot, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var v cbg.CborInt
+	found := 0
+	require.NoError(t, m.ForEach(&v, func(key string) error {
+		found++
+		return nil
+	}))
+	assert.Equal(t, len(kept), found)
+
+	// Only unreachable blocks were collected, so collecting again finds nothing.
+	collected, err = bs.GC(ctx, []cid.Cid{keptRoot})
+	require.NoError(t, err)
+	assert.Equal(t, 0, collected)
+}
+
+func TestMetricsBlockStoreClassification(t *testing.T) {
+	ctx := context.Background()
+	bs := ipld.NewBlockStoreInMemory()
+	store := adt.WrapBlockStore(ctx, bs)
+
+	// Enough entries that each collection spans several nodes.
+	m, err := adt.MakeEmptyMap(store, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	a, err := adt.MakeEmptyArray(store, 3)
+	require.NoError(t, err)
+	for i := uint64(0); i < 500; i++ {
+		v := cbg.CborInt(i)
+		require.NoError(t, m.Put(abi.UIntKey(i), &v))
+		require.NoError(t, a.Set(i, &v))
+	}
+	mapRoot, err := m.Root()
+	require.NoError(t, err)
+	arrayRoot, err := a.Root()
+	require.NoError(t, err)
+	leaf := cbg.CborInt(7)
+	leafRoot, err := store.Put(ctx, &leaf)
+	require.NoError(t, err)
+
+	t.Run("map traversal is attributed to the map class", func(t *testing.T) {
+		ms := ipld.NewClassifyingMetricsBlockStore(bs, ipld.ClassifyBlockCBOR)
+		m, err := adt.AsMap(adt.WrapBlockStore(ctx, ms), mapRoot, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		var v cbg.CborInt
+		require.NoError(t, m.ForEach(&v, func(string) error { return nil }))
+
+		assert.Greater(t, ms.ReadCount(), uint64(1))
+		assert.Equal(t, ms.ReadCount(), ms.ClassReadCount(ipld.BlockClassHamt))
+		assert.Equal(t, ms.ReadSize(), ms.ClassReadSize(ipld.BlockClassHamt))
+		assert.Equal(t, uint64(0), ms.ClassReadCount(ipld.BlockClassAmt))
+		assert.Equal(t, uint64(0), ms.ClassReadCount(ipld.BlockClassLeaf))
+	})
+
+	t.Run("array traversal is attributed to the array class", func(t *testing.T) {
+		ms := ipld.NewClassifyingMetricsBlockStore(bs, ipld.ClassifyBlockCBOR)
+		a, err := adt.AsArray(adt.WrapBlockStore(ctx, ms), arrayRoot, 3)
+		require.NoError(t, err)
+		var v cbg.CborInt
+		require.NoError(t, a.ForEach(&v, func(int64) error { return nil }))
+
+		assert.Greater(t, ms.ReadCount(), uint64(1))
+		assert.Equal(t, ms.ReadCount(), ms.ClassReadCount(ipld.BlockClassAmt))
+		assert.Equal(t, uint64(0), ms.ClassReadCount(ipld.BlockClassHamt))
+	})
+
+	t.Run("other values are leaves", func(t *testing.T) {
+		ms := ipld.NewClassifyingMetricsBlockStore(bs, ipld.ClassifyBlockCBOR)
+		var v cbg.CborInt
+		require.NoError(t, adt.WrapBlockStore(ctx, ms).Get(ctx, leafRoot, &v))
+
+		assert.Equal(t, uint64(1), ms.ClassReadCount(ipld.BlockClassLeaf))
+	})
+
+	t.Run("reads are not classified by default", func(t *testing.T) {
+		ms := ipld.NewMetricsBlockStore(bs)
+		var v cbg.CborInt
+		require.NoError(t, adt.WrapBlockStore(ctx, ms).Get(ctx, leafRoot, &v))
+
+		assert.Equal(t, uint64(1), ms.ReadCount())
+		assert.Equal(t, uint64(0), ms.ClassReadCount(ipld.BlockClassLeaf))
+	})
+}
+
+func TestAggregateStats(t *testing.T) {
+	ctx := context.Background()
+	injected := xerrors.New("injected")
+
+	// Metrics recorded above and below a fault-injecting store, all behind a synchronized store.
+	inner := ipld.NewMetricsBlockStore(ipld.NewBlockStoreInMemory())
+	faults := ipld.NewFaultInjectingBlockStore(inner)
+	outer := ipld.NewMetricsBlockStore(faults)
+	bs := ipld.NewSyncBlockStore(outer)
+	store := adt.WrapBlockStore(ctx, bs)
+
+	one, two := cbg.CborInt(1), cbg.CborInt(2)
+	c1, err := store.Put(ctx, &one)
+	require.NoError(t, err)
+	c2, err := store.Put(ctx, &two)
+	require.NoError(t, err)
+	var out cbg.CborInt
+	require.NoError(t, store.Get(ctx, c1, &out))
+
+	// A failed read is counted by the outer store only.
+	faults.FailGet(c2, injected)
+	assert.True(t, xerrors.Is(store.Get(ctx, c2, &out), injected))
+
+	assert.Equal(t, ipld.Stats{Reads: 2, ReadBytes: 1, Writes: 2, WriteBytes: 2}, outer.Stats())
+	assert.Equal(t, ipld.Stats{Reads: 1, ReadBytes: 1, Writes: 2, WriteBytes: 2}, inner.Stats())
+	aggregate := ipld.AggregateStats(bs)
+	assert.Equal(t, outer.Stats().Add(inner.Stats()), aggregate)
+
+	// Aggregation starts from the given store, so omits stores wrapping it.
+	assert.Equal(t, inner.Stats(), ipld.AggregateStats(faults))
+
+	// The stats source reflects operations performed after it was created.
+	source := ipld.NewAggregateStatsSource(bs)
+	require.NoError(t, store.Get(ctx, c1, &out))
+	assert.Equal(t, aggregate.Reads+2, source.ReadCount())
+	assert.Equal(t, aggregate.ReadBytes+2, source.ReadSize())
+	assert.Equal(t, aggregate.Writes, source.WriteCount())
+	assert.Equal(t, aggregate.WriteBytes, source.WriteSize())
+}
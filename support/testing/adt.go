@@ -1,9 +1,14 @@
 package testing
 
 import (
+	"math/rand"
 	"testing"
 
+	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 )
 
 type rooter interface {
@@ -18,3 +23,43 @@ func MustRoot(t testing.TB, r rooter) cid.Cid {
 	}
 	return c
 }
+
+// Populates a new map with up to n entries derived deterministically from seed, returning its root.
+// Keys and values are random integers,
+// so the same seed, size and bitwidth always yield the same root.
+func SeedMap(t testing.TB, store adt.Store, seed int64, n int, bitwidth int) cid.Cid {
+	t.Helper()
+	m, err := adt.MakeEmptyMap(store, bitwidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < n; i++ {
+		key := abi.UIntKey(r.Uint64())
+		value := cbg.CborInt(r.Int63())
+		if err := m.Put(key, &value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return MustRoot(t, m)
+}
+
+// Populates a new array with n entries at sparse indices derived deterministically from seed, returning its root.
+// Indices are drawn from a range a few times larger than n, so the array has gaps (and fewer than n entries
+// if indices collide). The same seed, size and bitwidth always yield the same root.
+func SeedArray(t testing.TB, store adt.Store, seed int64, n int, bitwidth int) cid.Cid {
+	t.Helper()
+	a, err := adt.MakeEmptyArray(store, bitwidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < n; i++ {
+		idx := uint64(r.Int63n(int64(4*n + 1)))
+		value := cbg.CborInt(r.Int63())
+		if err := a.Set(idx, &value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return MustRoot(t, a)
+}
@@ -0,0 +1,50 @@
+package testing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
+)
+
+func TestSeedMap(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+	root := tutil.SeedMap(t, store, 42, 100, 5)
+
+	// The same seed in a fresh store yields the same root.
+	assert.Equal(t, root, tutil.SeedMap(t, ipld.NewADTStore(context.Background()), 42, 100, 5))
+	assert.NotEqual(t, root, tutil.SeedMap(t, store, 43, 100, 5))
+
+	m, err := adt.AsMap(store, root, 5)
+	require.NoError(t, err)
+	keys, err := m.CollectKeys()
+	require.NoError(t, err)
+	assert.Len(t, keys, 100) // Collisions among random 64-bit keys are vanishingly unlikely.
+}
+
+func TestSeedArray(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+	root := tutil.SeedArray(t, store, 42, 100, 3)
+
+	assert.Equal(t, root, tutil.SeedArray(t, ipld.NewADTStore(context.Background()), 42, 100, 3))
+	assert.NotEqual(t, root, tutil.SeedArray(t, store, 43, 100, 3))
+
+	arr, err := adt.AsArray(store, root, 3)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, arr.Length(), uint64(100))
+	assert.Greater(t, arr.Length(), uint64(0))
+
+	var v cbg.CborInt
+	maxIdx := int64(0)
+	require.NoError(t, arr.ForEach(&v, func(i int64) error {
+		maxIdx = i
+		return nil
+	}))
+	assert.LessOrEqual(t, maxIdx, int64(400))
+}
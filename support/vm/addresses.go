@@ -0,0 +1,54 @@
+package vm
+
+import (
+	"sort"
+
+	"github.com/filecoin-project/go-address"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	init_ "github.com/filecoin-project/specs-actors/v8/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+// A non-ID address and the ID address it resolves to through the init actor.
+type ResolvedAddress struct {
+	Address address.Address // Robust (public key or actor) address
+	ID      address.Address
+}
+
+// ResolvedAddresses returns every entry in the init actor's address map in the current
+// (not necessarily committed) state, ordered by robust address.
+func (vm *VM) ResolvedAddresses() ([]ResolvedAddress, error) {
+	var st init_.State
+	if err := vm.GetState(builtin.InitActorAddr, &st); err != nil {
+		return nil, xerrors.Errorf("failed to load init actor state: %w", err)
+	}
+	m, err := adt.AsMap(vm.store, st.AddressMap, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load address map: %w", err)
+	}
+
+	var resolved []ResolvedAddress
+	var actorID cbg.CborInt
+	err = m.ForEach(&actorID, func(k string) error {
+		addr, err := address.NewFromBytes([]byte(k))
+		if err != nil {
+			return err
+		}
+		idAddr, err := address.NewIDAddress(uint64(actorID))
+		if err != nil {
+			return err
+		}
+		resolved = append(resolved, ResolvedAddress{Address: addr, ID: idAddr})
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to iterate address map: %w", err)
+	}
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].Address.String() < resolved[j].Address.String()
+	})
+	return resolved, nil
+}
@@ -0,0 +1,121 @@
+package vm
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/states"
+)
+
+// The change in an actor's balance over the execution of a message.
+type BalanceChange struct {
+	Address address.Address // ID address of the actor
+	Before  abi.TokenAmount
+	After   abi.TokenAmount
+}
+
+func (c BalanceChange) Delta() abi.TokenAmount {
+	return big.Sub(c.After, c.Before)
+}
+
+// The balance changes resulting from a single top-level message.
+type MessageBalanceChanges struct {
+	From   address.Address
+	To     address.Address
+	Method abi.MethodNum
+	// Actors whose balance changed, ordered by address. Actors created by the message have a zero Before balance,
+	// and deleted actors a zero After balance.
+	Changes []BalanceChange
+}
+
+// Delta returns the change in balance of the actor with ID address addr, which is zero if it did not change.
+func (m MessageBalanceChanges) Delta(addr address.Address) abi.TokenAmount {
+	for _, c := range m.Changes {
+		if c.Address == addr {
+			return c.Delta()
+		}
+	}
+	return big.Zero()
+}
+
+// BalanceChangeRecorder records the balance changes of every actor affected by each message applied to a VM,
+// so tests can assert on the flow of funds without reading balances before and after each message.
+type BalanceChangeRecorder struct {
+	mu       sync.Mutex
+	messages []MessageBalanceChanges
+}
+
+func NewBalanceChangeRecorder() *BalanceChangeRecorder {
+	return &BalanceChangeRecorder{}
+}
+
+// Messages returns the changes for each message applied since the recorder was installed or last reset,
+// in order of application. Messages that left all balances unchanged are included with no changes.
+func (r *BalanceChangeRecorder) Messages() []MessageBalanceChanges {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]MessageBalanceChanges(nil), r.messages...)
+}
+
+// Last returns the changes for the most recently applied message, or false if no message has been recorded.
+func (r *BalanceChangeRecorder) Last() (MessageBalanceChanges, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.messages) == 0 {
+		return MessageBalanceChanges{}, false
+	}
+	return r.messages[len(r.messages)-1], true
+}
+
+func (r *BalanceChangeRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = nil
+}
+
+func (r *BalanceChangeRecorder) record(from, to address.Address, method abi.MethodNum, before, after map[address.Address]abi.TokenAmount) {
+	var changes []BalanceChange
+	for addr, b := range before {
+		a, ok := after[addr]
+		if !ok {
+			a = big.Zero()
+		}
+		if !a.Equals(b) {
+			changes = append(changes, BalanceChange{Address: addr, Before: b, After: a})
+		}
+	}
+	for addr, a := range after {
+		if _, ok := before[addr]; !ok && !a.IsZero() {
+			changes = append(changes, BalanceChange{Address: addr, Before: big.Zero(), After: a})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Address.String() < changes[j].Address.String()
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, MessageBalanceChanges{From: from, To: to, Method: method, Changes: changes})
+}
+
+// Returns the balance of every actor in the current (not necessarily committed) state, keyed by ID address.
+func (vm *VM) actorBalances() (map[address.Address]abi.TokenAmount, error) {
+	balances := make(map[address.Address]abi.TokenAmount)
+	var act states.Actor
+	err := vm.actors.ForEach(&act, func(k string) error {
+		addr, err := address.NewFromBytes([]byte(k))
+		if err != nil {
+			return err
+		}
+		balances[addr] = act.Balance
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
@@ -0,0 +1,45 @@
+package vm
+
+import (
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"golang.org/x/xerrors"
+)
+
+// A message to be applied as part of a bundle.
+type BundleMessage struct {
+	From   address.Address
+	To     address.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params interface{}
+}
+
+// ApplyBundle applies the messages in order as a single all-or-nothing unit.
+// If any message fails, no further messages are applied and the state is rolled back to that before the bundle,
+// including the senders' call sequence numbers, as if the bundle was never included.
+// Returns the result of each message applied, the last of which is the failure if the bundle was rolled back,
+// and any internal vm errors.
+func (vm *VM) ApplyBundle(msgs []BundleMessage, info string) ([]MessageResult, error) {
+	priorRoot, err := vm.checkpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]MessageResult, 0, len(msgs))
+	for i, msg := range msgs {
+		result, err := vm.ApplyMessage(msg.From, msg.To, msg.Value, msg.Method, msg.Params, info)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to apply bundle message %d: %w", i, err)
+		}
+		results = append(results, result)
+		if result.Code != exitcode.Ok {
+			if err := vm.rollback(priorRoot); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,79 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+)
+
+// Identifies an exit code returned by a method of an actor type.
+type ExitCodeKey struct {
+	Code   cid.Cid
+	Method abi.MethodNum
+	Exit   exitcode.ExitCode
+}
+
+// ExitCodeCoverage records the (actor method, exit code) pairs returned by invocations.
+// A single instance may be installed in many VMs to accumulate coverage across a whole test suite,
+// revealing abort paths that no test exercises.
+type ExitCodeCoverage struct {
+	mu   sync.Mutex
+	seen map[ExitCodeKey]uint64
+}
+
+func NewExitCodeCoverage() *ExitCodeCoverage {
+	return &ExitCodeCoverage{seen: make(map[ExitCodeKey]uint64)}
+}
+
+func (c *ExitCodeCoverage) record(code cid.Cid, method abi.MethodNum, exit exitcode.ExitCode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seen[ExitCodeKey{Code: code, Method: method, Exit: exit}]++
+}
+
+// Count returns the number of invocations of the method of an actor type that returned an exit code.
+func (c *ExitCodeCoverage) Count(code cid.Cid, method abi.MethodNum, exit exitcode.ExitCode) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.seen[ExitCodeKey{Code: code, Method: method, Exit: exit}]
+}
+
+// Keys returns all recorded pairs, ordered by actor name, method number and then exit code.
+func (c *ExitCodeCoverage) Keys() []ExitCodeKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]ExitCodeKey, 0, len(c.seen))
+	for k := range c.seen {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ni, nj := builtin.ActorNameByCode(keys[i].Code), builtin.ActorNameByCode(keys[j].Code)
+		if ni != nj {
+			return ni < nj
+		}
+		if keys[i].Method != keys[j].Method {
+			return keys[i].Method < keys[j].Method
+		}
+		return keys[i].Exit < keys[j].Exit
+	})
+	return keys
+}
+
+// WriteReport writes one line per recorded pair to w, as tab-separated actor name, method number,
+// exit code and invocation count.
+func (c *ExitCodeCoverage) WriteReport(w io.Writer) error {
+	for _, k := range c.Keys() {
+		if _, err := fmt.Fprintf(w, "%s\t%d\t%s\t%d\n", builtin.ActorNameByCode(k.Code), k.Method, k.Exit,
+			c.Count(k.Code, k.Method, k.Exit)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/cbor"
+	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/actors/states"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+// Expands the collections referenced from a decoded actor state into a human-readable form.
+type stateCollectionDumper func(store adt.Store, state cbor.Er, sb *strings.Builder) error
+
+// Actor types whose states hold collections worth expanding in a dump.
+// States of other actor types are printed without following any links.
+var stateCollectionDumpers = map[cid.Cid]stateCollectionDumper{
+	builtin.VerifiedRegistryActorCodeID: dumpVerifregCollections,
+}
+
+// DumpActorState loads the state of the actor at addr and returns a human-readable representation of it,
+// decoded according to the actor's code CID.
+// This is intended for debugging failed scenarios and the format is not stable.
+func (vm *VM) DumpActorState(addr address.Address) (string, error) {
+	act, state, err := vm.loadActorState(addr)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "actor %v (%s)\n", addr, builtin.ActorNameByCode(act.Code))
+	fmt.Fprintf(&sb, "  balance: %v\n", act.Balance)
+	fmt.Fprintf(&sb, "  nonce: %d\n", act.CallSeqNum)
+	fmt.Fprintf(&sb, "  head: %v\n", act.Head)
+	fmt.Fprintf(&sb, "  state: %+v\n", state)
+
+	if dump, ok := stateCollectionDumpers[act.Code]; ok {
+		if err := dump(vm.store, state, &sb); err != nil {
+			return "", xerrors.Errorf("failed to dump collections of actor %v: %w", addr, err)
+		}
+	}
+	return sb.String(), nil
+}
+
+// ExportActorStateJSON loads the state of the actor at addr and serializes it as indented JSON with object keys
+// sorted, so that the output is stable and suitable for comparison with golden files.
+// Collections are represented by their root CIDs and are not expanded.
+func (vm *VM) ExportActorStateJSON(addr address.Address) ([]byte, error) {
+	_, state, err := vm.loadActorState(addr)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal state of actor %v: %w", addr, err)
+	}
+
+	// Round trip through generic values, whose map keys encoding/json writes in sorted order.
+	// Numbers are kept in their literal form rather than converted to floats.
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, xerrors.Errorf("failed to decode state of actor %v: %w", addr, err)
+	}
+	out, err := json.MarshalIndent(generic, "", "  ")
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal state of actor %v: %w", addr, err)
+	}
+	return append(out, '\n'), nil
+}
+
+// Loads an actor and its state decoded according to the actor's code CID.
+func (vm *VM) loadActorState(addr address.Address) (*states.Actor, cbor.Er, error) {
+	act, found, err := vm.GetActor(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, xerrors.Errorf("actor %v not found", addr)
+	}
+	impl, ok := vm.ActorImpls[act.Code]
+	if !ok {
+		return nil, nil, xerrors.Errorf("no implementation for actor %v code %v", addr, act.Code)
+	}
+
+	state := impl.State()
+	if err := vm.store.Get(vm.ctx, act.Head, state); err != nil {
+		return nil, nil, xerrors.Errorf("failed to load state of actor %v: %w", addr, err)
+	}
+	return act, state, nil
+}
+
+func dumpVerifregCollections(store adt.Store, state cbor.Er, sb *strings.Builder) error {
+	st := state.(*verifreg.State)
+	if err := dumpDataCapMap(store, "verifiers", st.Verifiers, sb); err != nil {
+		return err
+	}
+	return dumpDataCapMap(store, "verified clients", st.VerifiedClients, sb)
+}
+
+func dumpDataCapMap(store adt.Store, name string, root cid.Cid, sb *strings.Builder) error {
+	m, err := adt.AsMap(store, root, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("failed to load %s: %w", name, err)
+	}
+
+	var lines []string
+	var dcap verifreg.DataCap
+	err = m.ForEach(&dcap, func(key string) error {
+		a, err := address.NewFromBytes([]byte(key))
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("    %v: %v\n", a, dcap))
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to iterate %s: %w", name, err)
+	}
+	// Sort for a deterministic output regardless of HAMT ordering.
+	sort.Strings(lines)
+
+	fmt.Fprintf(sb, "  %s (%d):\n", name, len(lines))
+	for _, l := range lines {
+		sb.WriteString(l)
+	}
+	return nil
+}
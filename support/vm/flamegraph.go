@@ -0,0 +1,93 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	goruntime "runtime"
+	"sort"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+)
+
+// WriteFoldedStacks writes the gas charged by the invocations recorded by the VM in the folded-stack format
+// consumed by flamegraph tooling (e.g. flamegraph.pl or speedscope).
+// Each line is a semicolon-separated stack of frames, from the top-level message down to the invoked method,
+// followed by the gas charged within that method itself, excluding its sub-invocations.
+// Frames are named by actor type and method, e.g. "fil/8/storagepower.CreateMiner".
+// Identical stacks are merged and lines are sorted, so the output is deterministic.
+func (vm *VM) WriteFoldedStacks(w io.Writer) error {
+	folded := make(map[string]int64)
+	for _, inv := range vm.invocations {
+		if err := vm.foldInvocation(folded, nil, inv); err != nil {
+			return err
+		}
+	}
+
+	stacks := make([]string, 0, len(folded))
+	for stack, gas := range folded {
+		if gas > 0 {
+			stacks = append(stacks, stack)
+		}
+	}
+	sort.Strings(stacks)
+	for _, stack := range stacks {
+		if _, err := fmt.Fprintf(w, "%s %d\n", stack, folded[stack]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (vm *VM) foldInvocation(folded map[string]int64, stack []string, inv *Invocation) error {
+	frame, err := vm.frameName(inv)
+	if err != nil {
+		return err
+	}
+	stack = append(stack, frame)
+
+	self := inv.GasUsed
+	for _, sub := range inv.SubInvocations {
+		self -= sub.GasUsed
+		if err := vm.foldInvocation(folded, stack, sub); err != nil {
+			return err
+		}
+	}
+	folded[strings.Join(stack, ";")] += self
+	return nil
+}
+
+// Names the frame for an invocation by the receiving actor's type and method.
+// Receivers that no longer exist (e.g. were deleted later in the message) are named by address.
+func (vm *VM) frameName(inv *Invocation) (string, error) {
+	act, found, err := vm.GetActor(inv.Msg.to)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return fmt.Sprintf("%s.%d", inv.Msg.to, inv.Msg.method), nil
+	}
+	return fmt.Sprintf("%s.%s", builtin.ActorNameByCode(act.Code), vm.methodName(act.Code, inv.Msg.method)), nil
+}
+
+// Resolves the name of an exported actor method, falling back to the method number.
+func (vm *VM) methodName(code cid.Cid, method abi.MethodNum) string {
+	if method == builtin.MethodSend {
+		return "Send"
+	}
+	impl, ok := vm.ActorImpls[code]
+	if !ok {
+		return fmt.Sprintf("%d", method)
+	}
+	exports := impl.Exports()
+	if int(method) >= len(exports) || exports[method] == nil {
+		return fmt.Sprintf("%d", method)
+	}
+	name := goruntime.FuncForPC(reflect.ValueOf(exports[method]).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	return name[strings.LastIndexByte(name, '.')+1:]
+}
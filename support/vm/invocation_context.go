@@ -415,6 +415,15 @@ func (ic *invocationContext) Send(toAddr address.Address, methodNum abi.MethodNu
 	ic.topLevel.gasUsed = newCtx.topLevel.gasUsed
 	ic.stats.MergeSubStat(newCtx.toActor.Code, newMsg.method, newCtx.stats)
 
+	// Running out of gas terminates the whole message, so is propagated rather than returned to the caller.
+	if code == exitcode.SysErrOutOfGas {
+		ic.Abortf(exitcode.SysErrOutOfGas, "send to %v method %d ran out of gas", toAddr, methodNum)
+	}
+	// A failed send has no return value to decode.
+	if !code.IsSuccess() {
+		return code
+	}
+
 	err = ret.Into(out)
 	if err != nil {
 		ic.Abortf(exitcode.ErrSerialization, "failed to serialize send return value into output parameter")
@@ -504,7 +513,7 @@ func (ic *invocationContext) StartSpan(_ string) func() {
 
 // Provides the system call interface.
 func (ic *invocationContext) Syscalls() runtime.Syscalls {
-	return fakeSyscalls{receiver: ic.msg.to, epoch: ic.rt.currentEpoch}
+	return fakeSyscalls{receiver: ic.msg.to, epoch: ic.rt.currentEpoch, verifySignature: ic.rt.sigVerifier}
 }
 
 // Note events that may make debugging easier
@@ -532,18 +541,40 @@ func (r returnWrapper) Into(o cbor.Unmarshaler) error {
 /////////////////////////////////////////////
 
 type fakeSyscalls struct {
-	receiver address.Address
-	epoch    abi.ChainEpoch
+	receiver        address.Address
+	epoch           abi.ChainEpoch
+	verifySignature SignatureVerifier
 }
 
-func (s fakeSyscalls) VerifySignature(sig crypto.Signature, _ address.Address, msg []byte) error {
-	if !bytes.Equal(sig.Data, msg) {
+// Verifies a signature by signer over plaintext, returning an error if it is invalid.
+type SignatureVerifier func(sig crypto.Signature, signer address.Address, plaintext []byte) error
+
+// Accepts a signature, of any type and from any signer, exactly when its data equals the plaintext.
+func DefaultSignatureVerifier(sig crypto.Signature, _ address.Address, plaintext []byte) error {
+	if !bytes.Equal(sig.Data, plaintext) {
 		return xerrors.New("invalid sig: message should be equal to sig bytes")
 	}
 
 	return nil
 }
 
+// Returns a verifier that rejects signatures with the given data, and otherwise defers to another verifier.
+// Tests use this to make specific signatures invalid while keeping the default behaviour for others.
+func RejectSignatures(next SignatureVerifier, rejected ...[]byte) SignatureVerifier {
+	return func(sig crypto.Signature, signer address.Address, plaintext []byte) error {
+		for _, r := range rejected {
+			if bytes.Equal(sig.Data, r) {
+				return xerrors.Errorf("invalid sig: signature by %v rejected", signer)
+			}
+		}
+		return next(sig, signer, plaintext)
+	}
+}
+
+func (s fakeSyscalls) VerifySignature(sig crypto.Signature, signer address.Address, msg []byte) error {
+	return s.verifySignature(sig, signer, msg)
+}
+
 func (s fakeSyscalls) HashBlake2b(b []byte) [32]byte {
 	return blake2b.Sum256(b)
 }
@@ -620,8 +651,12 @@ type storeWrapper struct {
 
 func (s storeWrapper) StoreGet(c cid.Cid, o cbor.Unmarshaler) bool {
 	err := s.s.Get(s.rt.ctx, c, o)
-	// assume all errors are not found errors (bad assumption, but ok for testing)
-	return err == nil
+	if xerrors.Is(err, ipld.ErrNotFound) {
+		return false
+	} else if err != nil {
+		s.rt.Abortf(exitcode.ErrIllegalState, "could not get object %v from store: %v", c, err)
+	}
+	return true
 }
 
 func (s storeWrapper) StorePut(x cbor.Marshaler) cid.Cid {
@@ -645,7 +680,7 @@ func (ic *invocationContext) invoke() (ret returnWrapper, errcode exitcode.ExitC
 		panic(err)
 	}
 
-	ic.rt.startInvocation(&ic.msg)
+	ic.rt.startInvocation(&ic.msg, ic.topLevel.gasUsed)
 
 	// Install handler for abort, which rolls back all state changes from this and any nested invocations.
 	// This is the only path by which a non-OK exit code may be returned.
@@ -660,7 +695,8 @@ func (ic *invocationContext) invoke() (ret returnWrapper, errcode exitcode.ExitC
 			case abort:
 				ic.rt.Log(rt.WARN, "Abort during actor execution. errMsg: %v exitCode: %d sender: %v receiver; %v method: %d value %v",
 					r, r.code, ic.msg.from, ic.msg.to, ic.msg.method, ic.msg.value)
-				ic.rt.endInvocation(r.code, abi.Empty)
+				ic.rt.abortInvocation(r.code, r.msg, ic.topLevel.gasUsed)
+				ic.recordExitCode(r.code)
 				ret = returnWrapper{abi.Empty} // The Empty here should never be used, but slightly safer than zero value.
 				errcode = r.code
 				return
@@ -706,7 +742,8 @@ func (ic *invocationContext) invoke() (ret returnWrapper, errcode exitcode.ExitC
 
 	// 5. if we are just sending funds, there is nothing else to do.
 	if ic.msg.method == builtin.MethodSend {
-		ic.rt.endInvocation(exitcode.Ok, abi.Empty)
+		ic.rt.endInvocation(exitcode.Ok, abi.Empty, ic.topLevel.gasUsed)
+		ic.recordExitCode(exitcode.Ok)
 		return returnWrapper{abi.Empty}, exitcode.Ok
 	}
 
@@ -733,10 +770,20 @@ func (ic *invocationContext) invoke() (ret returnWrapper, errcode exitcode.ExitC
 	ic.checkStateObjectsUnmodified()
 
 	// 3. success!
-	ic.rt.endInvocation(exitcode.Ok, marsh)
+	ic.rt.endInvocation(exitcode.Ok, marsh, ic.topLevel.gasUsed)
+	ic.recordExitCode(exitcode.Ok)
 	return ret, exitcode.Ok
 }
 
+// Records the exit code of this invocation if the VM is collecting exit code coverage.
+// Invocations that fail before the receiving actor is resolved are not attributed to any actor type.
+func (ic *invocationContext) recordExitCode(code exitcode.ExitCode) {
+	if ic.rt.exitCoverage == nil || ic.toActor == nil {
+		return
+	}
+	ic.rt.exitCoverage.record(ic.toActor.Code, ic.msg.method, code)
+}
+
 func (ic *invocationContext) dispatch(actor runtime.VMActor, method abi.MethodNum, arg interface{}) (interface{}, error) {
 	// get method signature
 	exports := actor.Exports()
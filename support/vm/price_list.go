@@ -158,6 +158,11 @@ type pricelist struct {
 
 var _ Pricelist = (*pricelist)(nil)
 
+// Returns the pricing used by new VMs, which matches that of network version 13.
+func DefaultPricelist() Pricelist {
+	return &v13PriceList
+}
+
 // OnChainMessage returns the gas used for storing a message of a given size in the chain.
 func (pl *pricelist) OnChainMessage(msgSize int) GasCharge {
 	return newGasCharge("OnChainMessage", pl.onChainMessageComputeBase,
@@ -34,6 +34,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v8/actors/states"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/smoothing"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
 	actor_testing "github.com/filecoin-project/specs-actors/v8/support/testing"
 )
 
@@ -52,7 +53,7 @@ func init() {
 // Genesis like setup
 //
 
-// Creates a new VM and initializes all singleton actors plus a root verifier account.
+// Creates a new VM in test mode and initializes all singleton actors plus a root verifier account.
 func NewVMWithSingletons(ctx context.Context, t testing.TB, bs ipldcbor.IpldBlockstore) *VM {
 	lookup := map[cid.Cid]runtime.VMActor{}
 	for _, ba := range exported.BuiltinActors() {
@@ -61,6 +62,7 @@ func NewVMWithSingletons(ctx context.Context, t testing.TB, bs ipldcbor.IpldBloc
 
 	store := adt.WrapBlockStore(ctx, bs)
 	vm := NewVM(ctx, lookup, store)
+	vm.EnableTestMode()
 
 	systemState, err := system.ConstructState(store)
 	require.NoError(t, err)
@@ -99,6 +101,43 @@ func NewVMWithSingletons(ctx context.Context, t testing.TB, bs ipldcbor.IpldBloc
 	return vm
 }
 
+// A Scenario is an independent test run against a freshly initialized VM.
+// Run returns the VM at the end of the scenario (which may have been advanced to a later epoch).
+type Scenario struct {
+	Name string
+	Run  func(t *testing.T, v *VM) *VM
+}
+
+type ScenarioResult struct {
+	Name      string
+	Failed    bool
+	Epoch     abi.ChainEpoch
+	StateRoot cid.Cid
+}
+
+// Runs each scenario as a parallel subtest, each with its own VM backed by a separate synchronized block store,
+// and returns once all have finished. Results are in the same order as the scenarios.
+func RunScenariosParallel(t *testing.T, scenarios ...Scenario) []ScenarioResult {
+	results := make([]ScenarioResult, len(scenarios))
+	// Parallel subtests of this group run only once its function returns, and t.Run waits for them all.
+	t.Run("scenarios", func(t *testing.T) {
+		for i, s := range scenarios {
+			i, s := i, s
+			results[i].Name = s.Name
+			t.Run(s.Name, func(t *testing.T) {
+				t.Parallel()
+				defer func() { results[i].Failed = t.Failed() }()
+
+				bs := ipld.NewSyncBlockStore(ipld.NewBlockStoreInMemory())
+				v := s.Run(t, NewVMWithSingletons(context.Background(), t, bs))
+				results[i].Epoch = v.GetEpoch()
+				results[i].StateRoot = v.StateRoot()
+			})
+		}
+	})
+	return results
+}
+
 // Creates n account actors in the VM with the given balance
 func CreateAccounts(ctx context.Context, t testing.TB, vm *VM, n int, balance abi.TokenAmount, seed int64) []address.Address {
 	var initState initactor.State
@@ -217,6 +256,58 @@ func listInvocations(invocations []*Invocation) string {
 	return list
 }
 
+// ExpectSend is a pattern for a send occurring anywhere within an invocation tree, irrespective of
+// its position or of other sends in the tree.
+// The To and Method fields must be supplied. From and Params are optional, where an Undef or nil value
+// indicates that any value will match.
+type ExpectSend struct {
+	To     address.Address
+	Method abi.MethodNum
+
+	// optional
+	From   address.Address
+	Params func(params interface{}) bool
+}
+
+// Returns all invocations within the tree rooted at invocation, including the root, that match the
+// expectation, in call order.
+func (es ExpectSend) FindIn(invocation *Invocation) []*Invocation {
+	var found []*Invocation
+	if es.matches(invocation) {
+		found = append(found, invocation)
+	}
+	for _, sub := range invocation.SubInvocations {
+		found = append(found, es.FindIn(sub)...)
+	}
+	return found
+}
+
+// Requires that a send matching the expectation occurred within the tree rooted at invocation,
+// returning the first such send.
+func (es ExpectSend) OccurredIn(t testing.TB, invocation *Invocation) *Invocation {
+	found := es.FindIn(invocation)
+	require.NotEmpty(t, found, "no send [%s:%d] found in invocations:\n%s", es.To, es.Method, listInvocationTree("", invocation))
+	return found[0]
+}
+
+func (es ExpectSend) matches(invocation *Invocation) bool {
+	if es.To != invocation.Msg.to || es.Method != invocation.Msg.method {
+		return false
+	}
+	if address.Undef != es.From && es.From != invocation.Msg.from {
+		return false
+	}
+	return es.Params == nil || es.Params(invocation.Msg.params)
+}
+
+func listInvocationTree(indent string, invocation *Invocation) string {
+	list := fmt.Sprintf("%s[%s->%s:%d]\n", indent, invocation.Msg.from, invocation.Msg.to, invocation.Msg.method)
+	for _, sub := range invocation.SubInvocations {
+		list += listInvocationTree(indent+"  ", sub)
+	}
+	return list
+}
+
 // helpers to simplify pointer creation
 func ExpectAttoFil(amount big.Int) *big.Int                    { return &amount }
 func ExpectBytes(b []byte) *objectExpectation                  { return ExpectObject(builtin.CBORBytes(b)) }
@@ -309,6 +400,25 @@ func AdvanceOneEpochWithCron(t *testing.T, v *VM) *VM {
 	return v
 }
 
+// RunEpochs steps through each epoch in [from, to], calling perEpoch (if non-nil) with a VM at that epoch
+// and then running cron, as a chain would apply messages before the end-of-tipset cron tick.
+// Returns a VM at epoch to, after its cron tick.
+func RunEpochs(t *testing.T, v *VM, from, to abi.ChainEpoch, perEpoch func(v *VM, epoch abi.ChainEpoch)) *VM {
+	require.GreaterOrEqual(t, from, v.GetEpoch(), "cannot run epochs before the current epoch")
+	require.GreaterOrEqual(t, to, from, "epoch range ends before it starts")
+	for epoch := from; epoch <= to; epoch++ {
+		var err error
+		v, err = v.WithEpoch(epoch)
+		require.NoError(t, err)
+		if perEpoch != nil {
+			perEpoch(v, epoch)
+		}
+		result := RequireApplyMessage(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil, t.Name())
+		require.Equal(t, exitcode.Ok, result.Code)
+	}
+	return v
+}
+
 // AdvanceByDeadline creates a new VM advanced to an epoch specified by the predicate while keeping the
 // miner state up-to-date by running a cron at the end of each deadline period.
 func AdvanceByDeadline(t *testing.T, v *VM, minerIDAddr address.Address, predicate advanceDeadlinePredicate) (*VM, *dline.Info) {
@@ -342,6 +452,30 @@ func AdvanceByDeadlineTillIndex(t *testing.T, v *VM, minerIDAddr address.Address
 	})
 }
 
+// Returns the open and close epochs of the miner's next instance of deadline i that has not yet closed.
+func DeadlineOpenClose(t *testing.T, v *VM, minerIDAddr address.Address, i uint64) (abi.ChainEpoch, abi.ChainEpoch) {
+	require.Less(t, i, miner.WPoStPeriodDeadlines)
+	dlInfo := MinerDLInfo(t, v, minerIDAddr)
+	offset := (i + miner.WPoStPeriodDeadlines - dlInfo.Index) % miner.WPoStPeriodDeadlines
+	open := dlInfo.Open + abi.ChainEpoch(offset)*miner.WPoStChallengeWindow
+	return open, open + miner.WPoStChallengeWindow
+}
+
+// Advances to the open epoch of the miner's next instance of deadline i, running cron at the end of each
+// deadline in between. If deadline i is already open the VM is returned at its current epoch.
+func AdvanceTillDeadlineOpen(t *testing.T, v *VM, minerIDAddr address.Address, i uint64) (*VM, *dline.Info) {
+	open, _ := DeadlineOpenClose(t, v, minerIDAddr, i)
+	v, _ = AdvanceByDeadlineTillIndex(t, v, minerIDAddr, i)
+	if v.GetEpoch() < open {
+		var err error
+		v, err = v.WithEpoch(open)
+		require.NoError(t, err)
+	}
+	dlInfo := MinerDLInfo(t, v, minerIDAddr)
+	require.Equal(t, open, dlInfo.Open)
+	return v, dlInfo
+}
+
 // Advance to the epoch when the sector is due to be proven.
 // Returns the deadline info for proving deadline for sector, partition index of sector, and a VM at the opening of
 // the deadline (ready for SubmitWindowedPoSt).
@@ -620,6 +754,13 @@ func ApplyCode(t *testing.T, v *VM, from, to address.Address, value abi.TokenAmo
 	return result.Ret
 }
 
+// Applies a message expected to abort with the given exit code and a message containing the given substring.
+func ApplyAbortContainsMessage(t *testing.T, v *VM, from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}, code exitcode.ExitCode, substr string) {
+	result := RequireApplyMessage(t, v, from, to, value, method, params, t.Name())
+	require.Equal(t, code, result.Code, "unexpected exit code")
+	require.Contains(t, result.AbortMessage, substr, "unexpected abort message")
+}
+
 func RequireApplyMessage(t *testing.T, v *VM, from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}, name string) MessageResult {
 	result, err := v.ApplyMessage(from, to, value, method, params, name)
 	require.NoError(t, err)
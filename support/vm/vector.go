@@ -117,7 +117,7 @@ func SetEndStateTree(rawRoot cid.Cid, store adt.Store) Option {
 
 func SetMessage(from, to address.Address, nonce uint64, value big.Int, method abi.MethodNum, params interface{}) Option {
 	return func(tv *testVector) error {
-		msg, err := makeChainMessage(from, to, nonce, value, method, params)
+		msg, err := makeChainMessage(from, to, nonce, value, method, params, defaultGasLimit)
 		if err != nil {
 			return err
 		}
@@ -126,6 +126,14 @@ func SetMessage(from, to address.Address, nonce uint64, value big.Int, method ab
 	}
 }
 
+// Must be applied after SetMessage.
+func SetGasLimit(limit int64) Option {
+	return func(tv *testVector) error {
+		tv.Message.GasLimit = limit
+		return nil
+	}
+}
+
 func SetReceipt(res MessageResult) Option {
 	return func(tv *testVector) error {
 		tv.Receipt = res
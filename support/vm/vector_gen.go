@@ -60,6 +60,9 @@ func (g *vectorGen) after(v *VM, from, to address.Address, value abi.TokenAmount
 	if err := SetMessage(from, to, callSeq, value, method, params)(&(g.vector)); err != nil {
 		return err
 	}
+	if err := SetGasLimit(v.gasLimit)(&(g.vector)); err != nil {
+		return err
+	}
 	if err := SetEndStateTree(v.StateRoot(), v.store)(&(g.vector)); err != nil {
 		return err
 	}
@@ -47,10 +47,17 @@ type VM struct {
 
 	statsSource   StatsSource
 	statsByMethod StatsByCall
+	exitCoverage  *ExitCodeCoverage
+	balanceRec    *BalanceChangeRecorder
 
 	circSupply abi.TokenAmount
 
-	gasPrices Pricelist
+	gasPrices   Pricelist
+	gasLimit    int64
+	sigVerifier SignatureVerifier
+
+	// Whether test-only setup operations that bypass message execution, such as SetActorBalance, are permitted.
+	testMode bool
 }
 
 // VM types
@@ -84,7 +91,7 @@ type ChainMessage struct {
 	Params []byte
 }
 
-func makeChainMessage(from, to address.Address, nonce uint64, value abi.TokenAmount, method abi.MethodNum, params interface{}) (*ChainMessage, error) {
+func makeChainMessage(from, to address.Address, nonce uint64, value abi.TokenAmount, method abi.MethodNum, params interface{}, gasLimit int64) (*ChainMessage, error) {
 	var buf bytes.Buffer
 	if params == nil {
 		if err := abi.Empty.MarshalCBOR(&buf); err != nil {
@@ -102,7 +109,7 @@ func makeChainMessage(from, to address.Address, nonce uint64, value abi.TokenAmo
 		To:         to,
 		Nonce:      nonce,
 		Value:      value,
-		GasLimit:   defaultGasLimit,
+		GasLimit:   gasLimit,
 		GasFeeCap:  big.Zero(),
 		GasPremium: big.Zero(),
 		Method:     method,
@@ -113,8 +120,13 @@ func makeChainMessage(from, to address.Address, nonce uint64, value abi.TokenAmo
 type Invocation struct {
 	Msg            *InternalMessage
 	Exitcode       exitcode.ExitCode
+	AbortMessage   string
 	Ret            cbor.Marshaler
 	SubInvocations []*Invocation
+	// Gas charged to the message while this invocation was executing, including its sub-invocations.
+	GasUsed int64
+
+	gasAtStart int64
 }
 
 // NewVM creates a new runtime for executing messages.
@@ -145,6 +157,8 @@ func NewVM(ctx context.Context, actorImpls ActorImplLookup, store adt.Store) *VM
 		statsByMethod:  make(StatsByCall),
 		circSupply:     big.Mul(big.NewInt(1e9), big.NewInt(1e18)),
 		gasPrices:      &v13PriceList,
+		gasLimit:       defaultGasLimit,
+		sigVerifier:    DefaultSignatureVerifier,
 	}
 }
 
@@ -173,6 +187,8 @@ func NewVMAtEpoch(ctx context.Context, actorImpls ActorImplLookup, store adt.Sto
 		statsByMethod:  make(StatsByCall),
 		circSupply:     big.Mul(big.NewInt(1e9), big.NewInt(1e18)),
 		gasPrices:      &v13PriceList,
+		gasLimit:       defaultGasLimit,
+		sigVerifier:    DefaultSignatureVerifier,
 	}, nil
 }
 
@@ -199,8 +215,13 @@ func (vm *VM) WithEpoch(epoch abi.ChainEpoch) (*VM, error) {
 		networkVersion: vm.networkVersion,
 		statsSource:    vm.statsSource,
 		statsByMethod:  make(StatsByCall),
+		exitCoverage:   vm.exitCoverage,
+		balanceRec:     vm.balanceRec,
 		circSupply:     vm.circSupply,
-		gasPrices:      &v13PriceList,
+		gasPrices:      vm.gasPrices,
+		gasLimit:       vm.gasLimit,
+		sigVerifier:    vm.sigVerifier,
+		testMode:       vm.testMode,
 	}, nil
 }
 
@@ -227,8 +248,13 @@ func (vm *VM) WithNetworkVersion(nv network.Version) (*VM, error) {
 		networkVersion: nv,
 		statsSource:    vm.statsSource,
 		statsByMethod:  make(StatsByCall),
+		exitCoverage:   vm.exitCoverage,
+		balanceRec:     vm.balanceRec,
 		circSupply:     vm.circSupply,
-		gasPrices:      &v13PriceList,
+		gasPrices:      vm.gasPrices,
+		gasLimit:       vm.gasLimit,
+		sigVerifier:    vm.sigVerifier,
+		testMode:       vm.testMode,
 	}, nil
 }
 
@@ -283,13 +309,43 @@ func (vm *VM) SetActorState(ctx context.Context, key address.Address, state cbor
 	return vm.setActor(ctx, key, a)
 }
 
+// Permits test-only setup operations that modify state directly rather than by applying messages.
+func (vm *VM) EnableTestMode() {
+	vm.testMode = true
+}
+
+// SetActorBalance directly sets the balance of an existing actor, for setting up test scenarios.
+// This is not a message: no funds are transferred from any other actor, so the total balance of all actors
+// changes by the difference. It fails unless the VM is in test mode.
+func (vm *VM) SetActorBalance(addr address.Address, amount abi.TokenAmount) error {
+	if !vm.testMode {
+		return xerrors.Errorf("cannot set balance of %s outside test mode", addr)
+	}
+	if amount.LessThan(big.Zero()) {
+		return xerrors.Errorf("cannot set negative balance %v of %s", amount, addr)
+	}
+	idAddr, found := vm.NormalizeAddress(addr)
+	if !found {
+		return xerrors.Errorf("could not resolve address %s to set balance", addr)
+	}
+	a, found, err := vm.GetActor(idAddr)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return xerrors.Errorf("could not find actor %s to set balance", addr)
+	}
+	a.Balance = amount
+	return vm.setActor(vm.ctx, idAddr, a)
+}
+
 // deleteActor remove the actor from the storage.
 //
 // This method will NOT return an error if the actor was not found.
 // This behaviour is based on a principle that some store implementations might not be able to determine
 // whether something exists before deleting it.
 func (vm *VM) deleteActor(_ context.Context, key address.Address) error {
-	found, err := vm.actors.TryDelete(abi.AddrKey(key))
+	_, found, err := vm.actors.TryDelete(abi.AddrKey(key))
 	vm.actorsDirty = found
 	return err
 }
@@ -338,6 +394,8 @@ type MessageResult struct {
 	Ret        cbor.Marshaler
 	Code       exitcode.ExitCode
 	GasCharged int64
+	// The message with which the top-level invocation aborted, empty if it did not abort.
+	AbortMessage string
 }
 
 // ApplyMessage applies the message to the current state. It returns result of message application and any internal vm errors.
@@ -349,16 +407,63 @@ func (vm *VM) ApplyMessage(from, to address.Address, value abi.TokenAmount, meth
 		return MessageResult{}, err
 	}
 
+	var balancesBefore map[address.Address]abi.TokenAmount
+	if vm.balanceRec != nil {
+		var err error
+		if balancesBefore, err = vm.actorBalances(); err != nil {
+			return MessageResult{}, err
+		}
+	}
+
 	result, callSeq, fakesAccessed, err := vm.applyMessageInternal(from, to, value, method, params)
 	if err != nil {
 		return MessageResult{}, err
 	}
+
+	if vm.balanceRec != nil {
+		balancesAfter, err := vm.actorBalances()
+		if err != nil {
+			return MessageResult{}, err
+		}
+		vm.balanceRec.record(from, to, method, balancesBefore, balancesAfter)
+	}
 	if err := vectorGen.after(vm, from, to, value, method, params, callSeq, result, fakesAccessed, info); err != nil {
 		return MessageResult{}, err
 	}
 	return result, nil
 }
 
+// EstimateGas applies a message to the current state and then reverts all its effects, including the
+// increment of the sender's call sequence number. The result's GasCharged is the gas the message would use if
+// then applied to the same state. Invocations, logs and call stats recorded while estimating are discarded,
+// and no exit code coverage or balance changes are recorded.
+func (vm *VM) EstimateGas(from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}) (result MessageResult, err error) {
+	// Flush the actors to a root to roll back to, without moving the VM's state root.
+	stateRoot, actorsDirty := vm.stateRoot, vm.actorsDirty
+	root, err := vm.actors.Root()
+	if err != nil {
+		return MessageResult{}, err
+	}
+
+	invocations, logs, stats, coverage := vm.invocations, vm.logs, vm.statsByMethod, vm.exitCoverage
+	vm.statsByMethod = make(StatsByCall)
+	vm.exitCoverage = nil
+	// Revert the message's effects however it concludes, including when it fails with an error.
+	defer func() {
+		vm.invocations, vm.logs, vm.statsByMethod, vm.exitCoverage = invocations, logs, stats, coverage
+		if rbErr := vm.rollback(root); rbErr != nil && err == nil {
+			result, err = MessageResult{}, rbErr
+		}
+		vm.stateRoot, vm.actorsDirty = stateRoot, actorsDirty
+	}()
+
+	result, _, _, err = vm.applyMessageInternal(from, to, value, method, params)
+	if err != nil {
+		return MessageResult{}, err
+	}
+	return result, nil
+}
+
 func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmount, method abi.MethodNum, params interface{}) (MessageResult, uint64, bool, error) {
 	// This method does not actually execute the message itself,
 	// but rather deals with the pre/post processing of a message.
@@ -368,7 +473,7 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 	// load actor from global state
 	fromID, ok := vm.NormalizeAddress(from)
 	if !ok {
-		return MessageResult{nil, exitcode.SysErrSenderInvalid, gasCharged}, 0, false, nil
+		return MessageResult{nil, exitcode.SysErrSenderInvalid, gasCharged, ""}, 0, false, nil
 	}
 
 	fromActor, found, err := vm.GetActor(fromID)
@@ -377,7 +482,7 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 	}
 	if !found {
 		// Execution error; sender does not exist at time of message execution.
-		return MessageResult{nil, exitcode.SysErrSenderInvalid, gasCharged}, 0, false, nil
+		return MessageResult{nil, exitcode.SysErrSenderInvalid, gasCharged, ""}, 0, false, nil
 	}
 
 	// send
@@ -401,7 +506,7 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 		return MessageResult{}, 0, false, err
 	}
 
-	msg, err := makeChainMessage(from, to, callSeq, value, method, params)
+	msg, err := makeChainMessage(from, to, callSeq, value, method, params, vm.gasLimit)
 	if err != nil {
 		return MessageResult{}, 0, false, err
 	}
@@ -412,6 +517,10 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 	bs := msgBuf.Bytes()
 	charge := vm.gasPrices.OnChainMessage(len(bs))
 	msgGasCharge := charge.Total()
+	if msgGasCharge > vm.gasLimit {
+		// The message can't pay for its own inclusion, so is not executed at all.
+		return MessageResult{nil, exitcode.SysErrOutOfGas, vm.gasLimit, ""}, callSeq, false, nil
+	}
 
 	topLevel := topLevelContext{
 		originatorStableAddress: from,
@@ -421,7 +530,7 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 		circSupply:              vm.circSupply,
 		gasUsed:                 msgGasCharge,
 		gasPrices:               vm.gasPrices,
-		gasAvailable:            defaultGasLimit,
+		gasAvailable:            vm.gasLimit,
 		fakeSyscallsAccessed:    false,
 	}
 
@@ -466,7 +575,9 @@ func (vm *VM) applyMessageInternal(from, to address.Address, value abi.TokenAmou
 	retGasCharge := vm.gasPrices.OnChainReturnValue(len(retBuf.Bytes()))
 	gasCharged = retGasCharge.Total() + ctx.topLevel.gasUsed
 
-	return MessageResult{ret.inner, exitCode, gasCharged}, callSeq, ctx.topLevel.fakeSyscallsAccessed, nil
+	abortMessage := vm.LastInvocation().AbortMessage
+
+	return MessageResult{ret.inner, exitCode, gasCharged, abortMessage}, callSeq, ctx.topLevel.fakeSyscallsAccessed, nil
 }
 
 func (vm *VM) StateRoot() cid.Cid {
@@ -537,6 +648,33 @@ func (vm *VM) GetActorImpls() map[cid.Cid]rt.VMActor {
 	return vm.ActorImpls
 }
 
+// Installs an actor implementation under a code CID, replacing any implementation already installed for it.
+// The change applies to this VM and any VMs subsequently derived from it, but not to the VM it was derived from,
+// so a scenario can run actors of different versions side by side.
+func (vm *VM) SetActorImpl(code cid.Cid, impl rt.VMActor) {
+	impls := make(ActorImplLookup, len(vm.ActorImpls)+1)
+	for c, a := range vm.ActorImpls { //nolint:nomaprange
+		impls[c] = a
+	}
+	impls[code] = impl
+	vm.ActorImpls = impls
+}
+
+// Changes the code CID of an existing actor, leaving its balance and state in place, as an actor upgrade does.
+// An implementation must be installed for the code before the actor can next be invoked.
+func (vm *VM) SetActorCode(ctx context.Context, a address.Address, code cid.Cid) error {
+	act, found, err := vm.GetActor(a)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return xerrors.Errorf("could not find actor %s to set code", a)
+	}
+	act.Code = code
+	na, _ := vm.NormalizeAddress(a)
+	return vm.setActor(ctx, na, act)
+}
+
 // transfer debits money from one account and credits it to another.
 // avoid calling this method with a zero amount else it will perform unnecessary actor loading.
 //
@@ -608,6 +746,63 @@ func (vm *VM) GetStatsSource() StatsSource {
 	return vm.statsSource
 }
 
+// Installs a recorder of the exit code of every subsequent invocation, or removes it if nil.
+// VMs derived from this one with WithEpoch or WithNetworkVersion share the recorder.
+func (vm *VM) SetExitCodeCoverage(c *ExitCodeCoverage) {
+	vm.exitCoverage = c
+}
+
+func (vm *VM) GetExitCodeCoverage() *ExitCodeCoverage {
+	return vm.exitCoverage
+}
+
+// Installs a recorder of the balance changes caused by every subsequent message, or removes it if nil.
+// VMs derived from this one with WithEpoch or WithNetworkVersion share the recorder.
+func (vm *VM) SetBalanceChangeRecorder(r *BalanceChangeRecorder) {
+	vm.balanceRec = r
+}
+
+func (vm *VM) GetBalanceChangeRecorder() *BalanceChangeRecorder {
+	return vm.balanceRec
+}
+
+// Sets the gas limit of every subsequent top-level message. A message that exhausts its gas aborts with
+// SysErrOutOfGas and all its state changes, except the sender's call sequence number increment, are reverted.
+// VMs derived from this one with WithEpoch or WithNetworkVersion inherit the limit.
+func (vm *VM) SetGasLimit(limit int64) {
+	vm.gasLimit = limit
+}
+
+func (vm *VM) GetGasLimit() int64 {
+	return vm.gasLimit
+}
+
+// Sets the gas pricing of every subsequent message, or restores the default pricing if nil.
+// VMs derived from this one with WithEpoch or WithNetworkVersion inherit the pricing.
+func (vm *VM) SetPricelist(pl Pricelist) {
+	if pl == nil {
+		pl = DefaultPricelist()
+	}
+	vm.gasPrices = pl
+}
+
+func (vm *VM) GetPricelist() Pricelist {
+	return vm.gasPrices
+}
+
+// Sets the verifier of signatures checked by actors in every subsequent message, or restores the default
+// verifier if nil. VMs derived from this one with WithEpoch or WithNetworkVersion inherit the verifier.
+func (vm *VM) SetSignatureVerifier(v SignatureVerifier) {
+	if v == nil {
+		v = DefaultSignatureVerifier
+	}
+	vm.sigVerifier = v
+}
+
+func (vm *VM) GetSignatureVerifier() SignatureVerifier {
+	return vm.sigVerifier
+}
+
 func (vm *VM) StoreReads() uint64 {
 	if vm.statsSource != nil {
 		return vm.statsSource.ReadCount()
@@ -640,8 +835,8 @@ func (vm *VM) StoreWriteBytes() uint64 {
 // invocation tracking
 //
 
-func (vm *VM) startInvocation(msg *InternalMessage) {
-	invocation := Invocation{Msg: msg}
+func (vm *VM) startInvocation(msg *InternalMessage, gasUsed int64) {
+	invocation := Invocation{Msg: msg, gasAtStart: gasUsed}
 	if len(vm.invocationStack) > 0 {
 		parent := vm.invocationStack[len(vm.invocationStack)-1]
 		parent.SubInvocations = append(parent.SubInvocations, &invocation)
@@ -651,15 +846,22 @@ func (vm *VM) startInvocation(msg *InternalMessage) {
 	vm.invocationStack = append(vm.invocationStack, &invocation)
 }
 
-func (vm *VM) endInvocation(code exitcode.ExitCode, ret cbor.Marshaler) {
+func (vm *VM) endInvocation(code exitcode.ExitCode, ret cbor.Marshaler, gasUsed int64) {
 	curIndex := len(vm.invocationStack) - 1
 	current := vm.invocationStack[curIndex]
 	current.Exitcode = code
 	current.Ret = ret
+	current.GasUsed = gasUsed - current.gasAtStart
 
 	vm.invocationStack = vm.invocationStack[:curIndex]
 }
 
+// Ends the current invocation with the exit code and message of an abort.
+func (vm *VM) abortInvocation(code exitcode.ExitCode, msg string, gasUsed int64) {
+	vm.invocationStack[len(vm.invocationStack)-1].AbortMessage = msg
+	vm.endInvocation(code, abi.Empty, gasUsed)
+}
+
 func (vm *VM) Invocations() []*Invocation {
 	return vm.invocations
 }